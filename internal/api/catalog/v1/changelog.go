@@ -0,0 +1,229 @@
+package catalogv1
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	semver "github.com/Masterminds/semver/v3"
+)
+
+const changelogDateLayout = "2006-01-02"
+
+var (
+	reChangelogVersion = regexp.MustCompile(`^##\s+(.*?)\s*$`)
+	reChangelogSection = regexp.MustCompile(`^###\s+(.*?)\s*$`)
+	reChangelogItem    = regexp.MustCompile(`^\s?[-*+]\s+(.*)$`)
+	reChangelogLinkRef = regexp.MustCompile(`^\[[^\]]+\]:\s*\S+`)
+	reChangelogRelease = regexp.MustCompile(`^\[?([^\]\s]+)\]?(?:\s+-\s+(\S+))?(\s+\[YANKED\])?$`)
+)
+
+// Changelog is the structured representation of a CHANGELOG.md file written
+// in the Keep a Changelog format (https://keepachangelog.com/en/1.0.0/).
+type Changelog struct {
+	Unreleased *ChangelogEntry  `json:"unreleased,omitempty" yaml:"unreleased,omitempty"`
+	Entries    []ChangelogEntry `json:"entries" yaml:"entries"`
+}
+
+type ChangelogEntry struct {
+	Version    string   `json:"version" yaml:"version"`
+	Date       string   `json:"date,omitempty" yaml:"date,omitempty"`
+	Yanked     bool     `json:"yanked,omitempty" yaml:"yanked,omitempty"`
+	Added      []string `json:"added,omitempty" yaml:"added,omitempty"`
+	Changed    []string `json:"changed,omitempty" yaml:"changed,omitempty"`
+	Deprecated []string `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
+	Removed    []string `json:"removed,omitempty" yaml:"removed,omitempty"`
+	Fixed      []string `json:"fixed,omitempty" yaml:"fixed,omitempty"`
+	Security   []string `json:"security,omitempty" yaml:"security,omitempty"`
+}
+
+func FixtureChangelogMarkdown(versions ...string) string {
+	var sb strings.Builder
+	sb.WriteString("# Changelog\n")
+	for _, version := range versions {
+		sb.WriteString(fmt.Sprintf("\n## [%s] - 2022-01-25\n### Added\n- Release %s\n", version, version))
+	}
+	return sb.String()
+}
+
+func (e *ChangelogEntry) section(name string) (*[]string, error) {
+	switch strings.ToLower(name) {
+	case "added":
+		return &e.Added, nil
+	case "changed":
+		return &e.Changed, nil
+	case "deprecated":
+		return &e.Deprecated, nil
+	case "removed":
+		return &e.Removed, nil
+	case "fixed":
+		return &e.Fixed, nil
+	case "security":
+		return &e.Security, nil
+	}
+	return nil, fmt.Errorf("section must be one of %s, got: %s", validChangelogSections(), name)
+}
+
+// Entry returns the changelog entry for the given version. Versions are
+// compared semantically so that e.g. "v1.2.0" matches "1.2.0".
+func (c Changelog) Entry(version string) (ChangelogEntry, bool) {
+	want, err := semver.NewVersion(version)
+	if err != nil {
+		return ChangelogEntry{}, false
+	}
+	for _, entry := range c.Entries {
+		got, err := semver.NewVersion(entry.Version)
+		if err != nil {
+			continue
+		}
+		if got.Equal(want) {
+			return entry, true
+		}
+	}
+	return ChangelogEntry{}, false
+}
+
+func ChangelogFromMarkdown(data []byte) (Changelog, error) {
+	changelog := Changelog{
+		Entries: []ChangelogEntry{},
+	}
+
+	var entry *ChangelogEntry
+	var section *[]string
+	inCodeBlock := false
+	seen := map[string]bool{}
+
+	// entries are appended once the next entry heading (or EOF) is reached so
+	// that the pointers above never refer to an element of a growing slice
+	flush := func() {
+		if entry != nil && entry.Version != "" {
+			changelog.Entries = append(changelog.Entries, *entry)
+		}
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inCodeBlock = !inCodeBlock
+		}
+		if inCodeBlock {
+			if section != nil && len(*section) > 0 {
+				appendToLastItem(*section, line)
+			}
+			continue
+		}
+
+		if matches := reChangelogVersion.FindStringSubmatch(line); matches != nil {
+			flush()
+			entry, section = nil, nil
+
+			release, err := parseChangelogRelease(matches[1])
+			if err != nil {
+				return changelog, fmt.Errorf("line %d: %w", lineNum, err)
+			}
+			if release.Version == "" {
+				if changelog.Unreleased != nil {
+					return changelog, fmt.Errorf("line %d: duplicate unreleased entry", lineNum)
+				}
+				changelog.Unreleased = &release
+				entry = changelog.Unreleased
+				continue
+			}
+			if seen[release.Version] {
+				return changelog, fmt.Errorf("line %d: duplicate entry for version %s", lineNum, release.Version)
+			}
+			seen[release.Version] = true
+			entry = &release
+			continue
+		}
+
+		if matches := reChangelogSection.FindStringSubmatch(line); matches != nil {
+			if entry == nil {
+				return changelog, fmt.Errorf("line %d: section %q must follow a version heading", lineNum, matches[1])
+			}
+			s, err := entry.section(matches[1])
+			if err != nil {
+				return changelog, fmt.Errorf("line %d: %w", lineNum, err)
+			}
+			section = s
+			continue
+		}
+
+		// anything before the first entry (title, introduction, etc.) and
+		// link reference definitions are not part of any entry
+		if entry == nil || strings.TrimSpace(line) == "" || reChangelogLinkRef.MatchString(line) {
+			continue
+		}
+
+		if matches := reChangelogItem.FindStringSubmatch(line); matches != nil {
+			if section == nil {
+				return changelog, fmt.Errorf("line %d: change must belong to a section (%s)", lineNum, validChangelogSections())
+			}
+			*section = append(*section, matches[1])
+			continue
+		}
+
+		// indented lines continue the previous change, e.g. nested lists or
+		// wrapped text
+		if section != nil && len(*section) > 0 {
+			appendToLastItem(*section, strings.TrimSpace(line))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return changelog, fmt.Errorf("error reading changelog: %w", err)
+	}
+	flush()
+
+	return changelog, nil
+}
+
+func parseChangelogRelease(heading string) (ChangelogEntry, error) {
+	entry := ChangelogEntry{}
+
+	matches := reChangelogRelease.FindStringSubmatch(heading)
+	if matches == nil {
+		return entry, fmt.Errorf("invalid version heading: %s", heading)
+	}
+
+	version := matches[1]
+	if strings.EqualFold(version, "unreleased") {
+		return entry, nil
+	}
+	if _, err := semver.NewVersion(version); err != nil {
+		return entry, fmt.Errorf("invalid version %s: %w", version, err)
+	}
+	entry.Version = version
+
+	if date := matches[2]; date != "" {
+		if _, err := time.Parse(changelogDateLayout, date); err != nil {
+			return entry, fmt.Errorf("invalid date for version %s, must be in YYYY-MM-DD format: %s", version, date)
+		}
+		entry.Date = date
+	}
+	entry.Yanked = matches[3] != ""
+
+	return entry, nil
+}
+
+func appendToLastItem(items []string, line string) {
+	last := len(items) - 1
+	items[last] = items[last] + "\n" + line
+}
+
+func validChangelogSections() []string {
+	return []string{
+		"Added",
+		"Changed",
+		"Deprecated",
+		"Removed",
+		"Fixed",
+		"Security",
+	}
+}
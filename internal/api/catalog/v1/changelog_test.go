@@ -0,0 +1,148 @@
+package catalogv1
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChangelogFromMarkdown(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       string
+		want       Changelog
+		wantErr    bool
+		wantErrMsg string
+	}{
+		{
+			name: "empty changelog",
+			data: "",
+			want: Changelog{
+				Entries: []ChangelogEntry{},
+			},
+		},
+		{
+			name: "keep a changelog format",
+			data: `# Changelog
+All notable changes to this project will be documented in this file.
+
+## [Unreleased]
+### Added
+- Something new
+
+## [1.1.0] - 2022-02-01
+### Added
+- Support for foo
+- Support for bar
+  across multiple lines
+### Fixed
+* Fixed baz
+
+## 1.0.0 - 2022-01-25 [YANKED]
+### Added
+- Initial release
+
+[1.1.0]: https://example.com/compare/1.0.0...1.1.0
+`,
+			want: Changelog{
+				Unreleased: &ChangelogEntry{
+					Added: []string{"Something new"},
+				},
+				Entries: []ChangelogEntry{
+					{
+						Version: "1.1.0",
+						Date:    "2022-02-01",
+						Added:   []string{"Support for foo", "Support for bar\nacross multiple lines"},
+						Fixed:   []string{"Fixed baz"},
+					},
+					{
+						Version: "1.0.0",
+						Date:    "2022-01-25",
+						Yanked:  true,
+						Added:   []string{"Initial release"},
+					},
+				},
+			},
+		},
+		{
+			name: "invalid version",
+			data: `## [foo] - 2022-01-25
+`,
+			wantErr:    true,
+			wantErrMsg: "line 1: invalid version foo: Invalid Semantic Version",
+		},
+		{
+			name: "invalid date",
+			data: `## [1.0.0] - 25/01/2022
+`,
+			wantErr:    true,
+			wantErrMsg: "line 1: invalid date for version 1.0.0, must be in YYYY-MM-DD format: 25/01/2022",
+		},
+		{
+			name: "duplicate version",
+			data: `## [1.0.0]
+## [1.0.0]
+`,
+			wantErr:    true,
+			wantErrMsg: "line 2: duplicate entry for version 1.0.0",
+		},
+		{
+			name: "invalid section",
+			data: `## [1.0.0]
+### Notes
+`,
+			wantErr:    true,
+			wantErrMsg: "line 2: section must be one of [Added Changed Deprecated Removed Fixed Security], got: Notes",
+		},
+		{
+			name: "change without section",
+			data: `## [1.0.0]
+- Initial release
+`,
+			wantErr:    true,
+			wantErrMsg: "line 2: change must belong to a section ([Added Changed Deprecated Removed Fixed Security])",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ChangelogFromMarkdown([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ChangelogFromMarkdown() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				if err.Error() != tt.wantErrMsg {
+					t.Errorf("ChangelogFromMarkdown() error msg = %v, wantErrMsg %v", err.Error(), tt.wantErrMsg)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ChangelogFromMarkdown() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChangelog_Entry(t *testing.T) {
+	changelog := Changelog{
+		Entries: []ChangelogEntry{
+			{Version: "20220125.0.0"},
+			{Version: "1.2.0"},
+		},
+	}
+	tests := []struct {
+		version string
+		want    bool
+	}{
+		{version: "20220125.0.0", want: true},
+		{version: "v1.2.0", want: true},
+		{version: "1.2.1", want: false},
+		{version: "invalid", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			if _, got := changelog.Entry(tt.version); got != tt.want {
+				t.Errorf("Changelog.Entry() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	metav1 "github.com/sensu/catalog-api/internal/api/metadata/v1"
+	"github.com/sensu/catalog-api/internal/position"
 )
 
 type Prompt struct {
@@ -62,6 +63,10 @@ type Integration struct {
 	Prompts            []Prompt        `json:"prompts,omitempty" yaml:"prompts,omitempty"`
 	ResourcePatches    []ResourcePatch `json:"resource_patches,omitempty" yaml:"resource_patches,omitempty"`
 	PostInstall        []PostInstall   `json:"post_install,omitempty" yaml:"post_install,omitempty"`
+
+	// Positions holds the positions of the fields of an integration decoded
+	// from yaml, which are attached to validation errors.
+	Positions position.Map `json:"-" yaml:"-"`
 }
 
 func FixtureIntegration(namespace, name string) Integration {
@@ -109,66 +114,65 @@ func FixtureIntegration(namespace, name string) Integration {
 	}
 }
 
+// Validate validates the integration against the default taxonomy.
 func (i Integration) Validate() error {
+	return i.ValidateWith(DefaultTaxonomy())
+}
+
+// ValidateWith validates the integration against the given taxonomy.
+func (i Integration) ValidateWith(taxonomy Taxonomy) error {
 	if i.Metadata.Namespace == "" {
-		return errors.New("namespace cannot be empty")
+		return i.fieldError("metadata.namespace", errors.New("namespace cannot be empty"))
 	}
 	if i.Metadata.Name == "" {
-		return errors.New("name cannot be empty")
+		return i.fieldError("metadata.name", errors.New("name cannot be empty"))
 	}
 	if i.DisplayName == "" {
-		return errors.New("display_name cannot be empty")
+		return i.fieldError("display_name", errors.New("display_name cannot be empty"))
 	}
-	if !isValidClass(i.Class) {
-		return fmt.Errorf("class must be one of %s", validClasses())
+	if !contains(taxonomy.ValidClasses(), i.Class) {
+		return i.fieldError("class", fmt.Errorf("class must be one of %s", taxonomy.ValidClasses()))
 	}
-	if !isValidProvider(i.Provider) {
-		return fmt.Errorf("provider must be one of %s, got: %s", validProviders(), i.Provider)
+	if !contains(taxonomy.ValidProviders(), i.Provider) {
+		return i.fieldError("provider", fmt.Errorf("provider must be one of %s, got: %s", taxonomy.ValidProviders(), i.Provider))
 	}
 	if i.ShortDescription == "" {
-		return errors.New("short_description cannot be empty")
+		return i.fieldError("short_description", errors.New("short_description cannot be empty"))
 	}
 	if len(i.Contributors) == 0 {
-		return errors.New("one or more contributors must be defined")
+		return i.fieldError("contributors", errors.New("one or more contributors must be defined"))
 	}
-
-	return nil
-}
-
-func validProviders() []string {
-	return []string{
-		"alerts",
-		"deregistration",
-		"discovery",
-		"events",
-		"incidents",
-		"metrics",
-		"monitoring",
-		"remediation",
+	if len(taxonomy.Platforms) > 0 {
+		for j, platform := range i.SupportedPlatforms {
+			if !contains(taxonomy.Platforms, platform) {
+				return i.fieldError(fmt.Sprintf("supported_platforms.%d", j), fmt.Errorf("supported platform must be one of %s, got: %s", taxonomy.Platforms, platform))
+			}
+		}
 	}
-}
-
-func isValidProvider(provider string) bool {
-	for _, p := range validProviders() {
-		if p == provider {
-			return true
+	if len(taxonomy.Tags) > 0 {
+		for j, tag := range i.Tags {
+			if !contains(taxonomy.Tags, tag) {
+				return i.fieldError(fmt.Sprintf("tags.%d", j), fmt.Errorf("tag must be one of the tags of the catalog, got: %s", tag))
+			}
 		}
 	}
-	return false
+
+	return nil
 }
 
-func validClasses() []string {
-	return []string{
-		"community",
-		"partner",
-		"supported",
-		"enterprise",
+// fieldError returns err at the position of field, or at the position of its
+// closest parent if field is missing. err is returned as is if the
+// integration was not decoded from yaml.
+func (i Integration) fieldError(field string, err error) error {
+	if pos := i.Positions.Lookup(field); pos.IsValid() {
+		return position.At(pos, err)
 	}
+	return err
 }
 
-func isValidClass(class string) bool {
-	for _, c := range validClasses() {
-		if c == class {
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
 			return true
 		}
 	}
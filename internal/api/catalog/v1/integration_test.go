@@ -88,3 +88,61 @@ func TestPostInstall_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestIntegration_ValidateWith(t *testing.T) {
+	tests := []struct {
+		name        string
+		integration func(*Integration)
+		taxonomy    Taxonomy
+		wantErrMsg  string
+	}{
+		{
+			name:     "default taxonomy",
+			taxonomy: Taxonomy{},
+		},
+		{
+			name:        "provider outside of the default taxonomy",
+			integration: func(i *Integration) { i.Provider = "tracing" },
+			taxonomy:    Taxonomy{},
+			wantErrMsg:  "provider must be one of [alerts deregistration discovery events incidents metrics monitoring remediation], got: tracing",
+		},
+		{
+			name:        "provider of a custom taxonomy",
+			integration: func(i *Integration) { i.Provider = "tracing" },
+			taxonomy:    Taxonomy{Providers: []string{"tracing"}},
+		},
+		{
+			name:       "class outside of a custom taxonomy",
+			taxonomy:   Taxonomy{Classes: []string{"official"}},
+			wantErrMsg: "class must be one of [official]",
+		},
+		{
+			name:       "supported platform outside of the taxonomy",
+			taxonomy:   Taxonomy{Platforms: []string{"linux"}},
+			wantErrMsg: "supported platform must be one of [linux], got: darwin",
+		},
+		{
+			name:       "tag outside of the taxonomy",
+			taxonomy:   Taxonomy{Tags: []string{"tag1"}},
+			wantErrMsg: "tag must be one of the tags of the catalog, got: tag2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			integration := FixtureIntegration("example_ns", "example")
+			if tt.integration != nil {
+				tt.integration(&integration)
+			}
+			err := integration.ValidateWith(tt.taxonomy)
+			if tt.wantErrMsg == "" {
+				if err != nil {
+					t.Errorf("ValidateWith() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErrMsg {
+				t.Errorf("ValidateWith() error = %v, want %s", err, tt.wantErrMsg)
+			}
+		})
+	}
+}
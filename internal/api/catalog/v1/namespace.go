@@ -0,0 +1,89 @@
+package catalogv1
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	metav1 "github.com/sensu/catalog-api/internal/api/metadata/v1"
+	"github.com/sensu/catalog-api/internal/position"
+)
+
+// Namespace is the optional metadata of a namespace of integrations, defined
+// by the namespace.yaml within the namespace directory.
+type Namespace struct {
+	Metadata    metav1.Metadata `json:"metadata" yaml:"metadata"`
+	DisplayName string          `json:"display_name" yaml:"display_name"`
+	Description string          `json:"description,omitempty" yaml:"description,omitempty"`
+
+	// Logo is the name of a png image within the namespace directory.
+	Logo   string          `json:"logo,omitempty" yaml:"logo,omitempty"`
+	Owners []string        `json:"owners,omitempty" yaml:"owners,omitempty"`
+	Links  []NamespaceLink `json:"links,omitempty" yaml:"links,omitempty"`
+
+	// Positions holds the positions of the fields of a namespace decoded from
+	// yaml, which are attached to validation errors.
+	Positions position.Map `json:"-" yaml:"-"`
+}
+
+type NamespaceLink struct {
+	Title string `json:"title" yaml:"title"`
+	URL   string `json:"url" yaml:"url"`
+}
+
+func FixtureNamespace(name string) Namespace {
+	return Namespace{
+		Metadata:    metav1.Metadata{Name: name},
+		DisplayName: strings.Title(name),
+		Description: "lorem ipsum",
+		Owners:      []string{"@artem", "@olha"},
+		Links: []NamespaceLink{
+			{Title: "Website", URL: "https://example.com"},
+		},
+	}
+}
+
+func (n Namespace) Validate() error {
+	if n.Metadata.Name == "" {
+		return n.fieldError("metadata.name", errors.New("name cannot be empty"))
+	}
+	if n.DisplayName == "" {
+		return n.fieldError("display_name", errors.New("display_name cannot be empty"))
+	}
+	if n.Logo != "" {
+		// subdirectories of the namespace directory are integrations
+		if strings.Contains(n.Logo, "/") {
+			return n.fieldError("logo", fmt.Errorf("logo must be the name of a file within the namespace directory, got: %s", n.Logo))
+		}
+		if path.Ext(n.Logo) != ".png" {
+			return n.fieldError("logo", fmt.Errorf("logo must be a png image, got: %s", n.Logo))
+		}
+	}
+	for i, owner := range n.Owners {
+		if owner == "" {
+			return n.fieldError(fmt.Sprintf("owners.%d", i), errors.New("owner cannot be empty"))
+		}
+	}
+	for i, link := range n.Links {
+		field := fmt.Sprintf("links.%d", i)
+		if link.Title == "" {
+			return n.fieldError(field+".title", errors.New("link title cannot be empty"))
+		}
+		u, err := url.Parse(link.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return n.fieldError(field+".url", fmt.Errorf("link url must be an http or https url, got: %s", link.URL))
+		}
+	}
+	return nil
+}
+
+// fieldError returns err at the position of field, or at the position of its
+// closest parent if field is missing.
+func (n Namespace) fieldError(field string, err error) error {
+	if pos := n.Positions.Lookup(field); pos.IsValid() {
+		return position.At(pos, err)
+	}
+	return err
+}
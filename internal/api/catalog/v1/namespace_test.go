@@ -0,0 +1,78 @@
+package catalogv1
+
+import (
+	"testing"
+)
+
+func TestNamespace_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		namespace  func(*Namespace)
+		wantErrMsg string
+	}{
+		{
+			name: "valid namespace",
+		},
+		{
+			name:      "without optional fields",
+			namespace: func(n *Namespace) { *n = Namespace{Metadata: n.Metadata, DisplayName: n.DisplayName} },
+		},
+		{
+			name:       "missing display name",
+			namespace:  func(n *Namespace) { n.DisplayName = "" },
+			wantErrMsg: "display_name cannot be empty",
+		},
+		{
+			name:      "logo within the namespace directory",
+			namespace: func(n *Namespace) { n.Logo = "logo.png" },
+		},
+		{
+			name:       "logo outside of the namespace directory",
+			namespace:  func(n *Namespace) { n.Logo = "../logo.png" },
+			wantErrMsg: "logo must be the name of a file within the namespace directory, got: ../logo.png",
+		},
+		{
+			name:       "logo within a subdirectory",
+			namespace:  func(n *Namespace) { n.Logo = "img/logo.png" },
+			wantErrMsg: "logo must be the name of a file within the namespace directory, got: img/logo.png",
+		},
+		{
+			name:       "logo that is not a png image",
+			namespace:  func(n *Namespace) { n.Logo = "logo.svg" },
+			wantErrMsg: "logo must be a png image, got: logo.svg",
+		},
+		{
+			name:       "empty owner",
+			namespace:  func(n *Namespace) { n.Owners = append(n.Owners, "") },
+			wantErrMsg: "owner cannot be empty",
+		},
+		{
+			name:       "link without title",
+			namespace:  func(n *Namespace) { n.Links[0].Title = "" },
+			wantErrMsg: "link title cannot be empty",
+		},
+		{
+			name:       "link with relative url",
+			namespace:  func(n *Namespace) { n.Links[0].URL = "/docs" },
+			wantErrMsg: "link url must be an http or https url, got: /docs",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			namespace := FixtureNamespace("example_ns")
+			if tt.namespace != nil {
+				tt.namespace(&namespace)
+			}
+			err := namespace.Validate()
+			if tt.wantErrMsg == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErrMsg {
+				t.Errorf("Validate() error = %v, want %s", err, tt.wantErrMsg)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"io"
 
+	"github.com/sensu/catalog-api/internal/position"
 	"gopkg.in/yaml.v3"
 )
 
@@ -12,21 +13,31 @@ type Resource map[string]interface{}
 
 type Resources []Resource
 
+// ResourcesFromYAML decodes the resources of a multi-document yaml file.
+// Errors carry the position of the offending document or line.
 func ResourcesFromYAML(data []byte) (Resources, error) {
 	resources := Resources{}
 	dec := yaml.NewDecoder(bytes.NewReader(data))
 
 	for {
-		doc := new(Resource)
-
-		if err := dec.Decode(&doc); err != nil {
+		node := yaml.Node{}
+		if err := dec.Decode(&node); err != nil {
 			if errors.Is(err, io.EOF) {
 				break
 			}
-			return resources, err
+			return resources, position.FromYAML(err)
+		}
+
+		doc := new(Resource)
+		if err := node.Decode(&doc); err != nil {
+			return resources, position.FromYAML(err)
 		}
 		if doc == nil {
-			return resources, errors.New("empty yaml document")
+			pos := position.Of(&node)
+			if len(node.Content) > 0 {
+				pos = position.Of(node.Content[0])
+			}
+			return resources, position.At(pos, errors.New("empty yaml document"))
 		}
 		resources = append(resources, *doc)
 	}
@@ -0,0 +1,52 @@
+package catalogv1
+
+import (
+	"testing"
+
+	"github.com/sensu/catalog-api/internal/position"
+)
+
+func TestResourcesFromYAML(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		want     int
+		wantLine int
+	}{
+		{
+			name: "multiple documents",
+			data: "type: CheckConfig\n---\ntype: Handler\n",
+			want: 2,
+		},
+		{
+			name:     "syntax error in a later document",
+			data:     "type: CheckConfig\n---\ntype: Handler\nspec: [\n",
+			wantLine: 4,
+		},
+		{
+			name:     "document that is not a mapping",
+			data:     "type: CheckConfig\n---\n- Handler\n",
+			wantLine: 3,
+		},
+		{
+			name:     "empty document",
+			data:     "type: CheckConfig\n---\n---\ntype: Handler\n",
+			wantLine: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resources, err := ResourcesFromYAML([]byte(tt.data))
+			if tt.wantLine == 0 {
+				if err != nil || len(resources) != tt.want {
+					t.Errorf("ResourcesFromYAML() = %v, %v, want %d resources", resources, err, tt.want)
+				}
+				return
+			}
+			posErr, ok := position.Find(err)
+			if !ok || posErr.Line != tt.wantLine {
+				t.Errorf("ResourcesFromYAML() error = %v (%+v), want a position error on line %d", err, posErr, tt.wantLine)
+			}
+		})
+	}
+}
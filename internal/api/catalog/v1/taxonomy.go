@@ -0,0 +1,50 @@
+package catalogv1
+
+// Taxonomy holds the values that the classifying fields of the integrations
+// of a catalog may take. Providers & classes default to those of the Sensu
+// catalog when empty; any supported platform & tag is allowed when
+// Platforms & Tags are empty.
+type Taxonomy struct {
+	Providers []string
+	Classes   []string
+	Platforms []string
+	Tags      []string
+}
+
+// DefaultTaxonomy returns the taxonomy of the Sensu catalog.
+func DefaultTaxonomy() Taxonomy {
+	return Taxonomy{
+		Providers: []string{
+			"alerts",
+			"deregistration",
+			"discovery",
+			"events",
+			"incidents",
+			"metrics",
+			"monitoring",
+			"remediation",
+		},
+		Classes: []string{
+			"community",
+			"partner",
+			"supported",
+			"enterprise",
+		},
+	}
+}
+
+// ValidProviders returns the providers that integrations may have.
+func (t Taxonomy) ValidProviders() []string {
+	if len(t.Providers) == 0 {
+		return DefaultTaxonomy().Providers
+	}
+	return t.Providers
+}
+
+// ValidClasses returns the classes that integrations may have.
+func (t Taxonomy) ValidClasses() []string {
+	if len(t.Classes) == 0 {
+		return DefaultTaxonomy().Classes
+	}
+	return t.Classes
+}
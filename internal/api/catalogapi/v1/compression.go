@@ -0,0 +1,62 @@
+package catalogapiv1
+
+import (
+	"path"
+	"strings"
+)
+
+// Content codings of the pre-compressed siblings of text endpoints, which
+// are written alongside an endpoint by appending the extension of the coding
+// to its path, e.g. catalog.json.br.
+const (
+	EncodingBrotli = "br"
+	EncodingGzip   = "gzip"
+)
+
+// Encodings lists the content codings of pre-compressed siblings in order of
+// preference.
+var Encodings = []string{EncodingBrotli, EncodingGzip}
+
+var encodingExts = map[string]string{
+	EncodingBrotli: ".br",
+	EncodingGzip:   ".gz",
+}
+
+var compressibleExts = map[string]bool{
+	".json": true,
+	".md":   true,
+	".svg":  true,
+}
+
+// IsCompressible reports whether the endpoint at path is a text endpoint
+// that may have pre-compressed siblings.
+func IsCompressible(p string) bool {
+	return compressibleExts[path.Ext(p)]
+}
+
+// CompressedPath returns the path of the sibling of the endpoint at path
+// compressed with the given content coding.
+func CompressedPath(p string, encoding string) string {
+	return p + encodingExts[encoding]
+}
+
+// SplitCompressedPath returns the path of the endpoint that the
+// pre-compressed sibling at path was compressed from and the content coding
+// used. ok is false if path is not a pre-compressed sibling.
+func SplitCompressedPath(p string) (endpointPath string, encoding string, ok bool) {
+	for _, encoding := range Encodings {
+		ext := encodingExts[encoding]
+		if strings.HasSuffix(p, ext) && IsCompressible(strings.TrimSuffix(p, ext)) {
+			return strings.TrimSuffix(p, ext), encoding, true
+		}
+	}
+	return "", "", false
+}
+
+// IsCompressedSibling reports whether path is the pre-compressed sibling of a
+// text endpoint. Compressed siblings are not part of a release; they are
+// excluded from the release checksum & the manifest.
+func IsCompressedSibling(p string) bool {
+	_, _, ok := SplitCompressedPath(p)
+	return ok
+}
@@ -0,0 +1,34 @@
+package catalogapiv1
+
+import (
+	"path"
+
+	"github.com/sensu/catalog-api/internal/diagnostics"
+)
+
+// GET /api/:release_sha256/v1/errors.json
+type ErrorsEndpoint struct {
+	outputPath string
+	data       ReleaseErrors
+}
+
+func (e ErrorsEndpoint) GetOutputPath() string { return e.outputPath }
+func (e ErrorsEndpoint) GetData() interface{}  { return e.data }
+
+// ReleaseErrors lists the integration versions that were excluded from a
+// release because they failed to build.
+type ReleaseErrors struct {
+	Errors []diagnostics.Diagnostic `json:"errors"`
+}
+
+func NewErrorsEndpoint(basePath string, errors ReleaseErrors) ErrorsEndpoint {
+	outputPath := path.Join(
+		basePath,
+		apiVersion,
+		"errors.json")
+
+	return ErrorsEndpoint{
+		outputPath: outputPath,
+		data:       errors,
+	}
+}
@@ -0,0 +1,91 @@
+package catalogapiv1
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+)
+
+const (
+	FacetTags      = "tags"
+	FacetProviders = "providers"
+	FacetClasses   = "classes"
+	FacetPlatforms = "platforms"
+)
+
+// FacetNames lists the facets, the endpoints of whose values are generated within
+// a directory named after the facet at the root of the api version.
+var FacetNames = []string{FacetTags, FacetProviders, FacetClasses, FacetPlatforms}
+
+// IsFacet returns true if name is the name of a facet. Namespaces cannot be
+// named after facets, as their endpoints would collide with the endpoints of
+// the facet values, e.g. tags/tag1.json.
+func IsFacet(name string) bool {
+	for _, facet := range FacetNames {
+		if name == facet {
+			return true
+		}
+	}
+	return false
+}
+
+// GET /api/:generated_sha/v1/facets.json
+type FacetsEndpoint struct {
+	outputPath string
+	data       Facets
+}
+
+func (e FacetsEndpoint) GetOutputPath() string { return e.outputPath }
+func (e FacetsEndpoint) GetData() interface{}  { return e.data }
+
+// Facets contains the number of integrations for each value of each facet.
+type Facets struct {
+	Tags      map[string]int `json:"tags" yaml:"tags"`
+	Providers map[string]int `json:"providers" yaml:"providers"`
+	Classes   map[string]int `json:"classes" yaml:"classes"`
+	Platforms map[string]int `json:"platforms" yaml:"platforms"`
+}
+
+func NewFacetsEndpoint(basePath string, facets Facets) FacetsEndpoint {
+	outputPath := path.Join(
+		basePath,
+		apiVersion,
+		"facets.json")
+
+	return FacetsEndpoint{
+		outputPath: outputPath,
+		data:       facets,
+	}
+}
+
+// GET /api/:generated_sha/v1/:facet/:value.json
+type FacetValueEndpoint struct {
+	outputPath string
+	data       FacetValue
+}
+
+func (e FacetValueEndpoint) GetOutputPath() string { return e.outputPath }
+func (e FacetValueEndpoint) GetData() interface{}  { return e.data }
+
+// FacetValue lists the latest version of each integration that matches a
+// single facet value, e.g. all integrations tagged with "nginx".
+type FacetValue struct {
+	Facet        string               `json:"facet" yaml:"facet"`
+	Value        string               `json:"value" yaml:"value"`
+	Integrations []IntegrationVersion `json:"integrations" yaml:"integrations"`
+}
+
+func NewFacetValueEndpoint(basePath string, fv FacetValue) FacetValueEndpoint {
+	// facet values are user defined, so escape them to prevent values such
+	// as "foo/bar" from creating nested directories
+	outputPath := path.Join(
+		basePath,
+		apiVersion,
+		fv.Facet,
+		fmt.Sprintf("%s.json", url.PathEscape(fv.Value)))
+
+	return FacetValueEndpoint{
+		outputPath: outputPath,
+		data:       fv,
+	}
+}
@@ -153,6 +153,30 @@ func NewIntegrationVersionChangelogEndpoint(basePath string, iv IntegrationVersi
 	}
 }
 
+// GET /api/:generated_sha/v1/integrations/:namespace/:name/:version/CHANGELOG.json
+type IntegrationVersionChangelogJSONEndpoint struct {
+	outputPath string
+	data       catalogv1.Changelog
+}
+
+func (e IntegrationVersionChangelogJSONEndpoint) GetOutputPath() string { return e.outputPath }
+func (e IntegrationVersionChangelogJSONEndpoint) GetData() interface{}  { return e.data }
+
+func NewIntegrationVersionChangelogJSONEndpoint(basePath string, iv IntegrationVersion, data catalogv1.Changelog) IntegrationVersionChangelogJSONEndpoint {
+	outputPath := path.Join(
+		basePath,
+		apiVersion,
+		iv.Integration.Metadata.Namespace,
+		iv.Integration.Metadata.Name,
+		iv.Version,
+		"CHANGELOG.json")
+
+	return IntegrationVersionChangelogJSONEndpoint{
+		outputPath: outputPath,
+		data:       data,
+	}
+}
+
 // GET /api/:generated_sha/v1/integrations/:namespace/:name/:version.json
 type IntegrationVersionEndpoint struct {
 	outputPath string
@@ -165,6 +189,21 @@ func (e IntegrationVersionEndpoint) GetData() interface{}  { return e.data }
 type IntegrationVersion struct {
 	catalogv1.Integration
 	Version string `json:"version" yaml:"version"`
+
+	// ContentSHA256 is the checksum of the files within the version
+	// directory; it is only set by the integration version endpoint
+	ContentSHA256 string `json:"content_sha256,omitempty" yaml:"content_sha256,omitempty"`
+}
+
+// IntegrationVersionDir returns the path of the directory containing the
+// version specific endpoints of an integration version.
+func IntegrationVersionDir(basePath string, iv IntegrationVersion) string {
+	return path.Join(
+		basePath,
+		apiVersion,
+		iv.Integration.Metadata.Namespace,
+		iv.Integration.Metadata.Name,
+		iv.Version)
 }
 
 func NewIntegrationVersionEndpoint(basePath string, iv IntegrationVersion) IntegrationVersionEndpoint {
@@ -233,29 +272,3 @@ func NewIntegrationEndpoint(basePath string, ivs IntegrationWithVersions) Integr
 		data:       ivs,
 	}
 }
-
-// GET /api/:generated_sha/v1/integrations/:namespace.json
-type IntegrationNamespaceEndpoint struct {
-	outputPath string
-	data       IntegrationNamespace
-}
-
-func (e IntegrationNamespaceEndpoint) GetOutputPath() string { return e.outputPath }
-func (e IntegrationNamespaceEndpoint) GetData() interface{}  { return e.data }
-
-type IntegrationNamespace struct {
-	Name         string   `json:"name" yaml:"name"`
-	Integrations []string `json:"integrations" yaml:"integrations"`
-}
-
-func NewIntegrationNamespaceEndpoint(basePath string, ns IntegrationNamespace) IntegrationNamespaceEndpoint {
-	outputPath := path.Join(
-		basePath,
-		apiVersion,
-		fmt.Sprintf("%s.json", ns.Name))
-
-	return IntegrationNamespaceEndpoint{
-		outputPath: outputPath,
-		data:       ns,
-	}
-}
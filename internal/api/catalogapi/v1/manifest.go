@@ -0,0 +1,39 @@
+package catalogapiv1
+
+import (
+	"path"
+)
+
+const ManifestFileName = "manifest.json"
+
+// GET /api/:release_sha256/manifest.json
+type ManifestEndpoint struct {
+	outputPath string
+	data       Manifest
+}
+
+func (e ManifestEndpoint) GetOutputPath() string { return e.outputPath }
+func (e ManifestEndpoint) GetData() interface{}  { return e.data }
+
+// Manifest lists every file of a release, other than the manifest itself.
+// Paths are relative to the release directory.
+type Manifest struct {
+	Files []ManifestFile `json:"files" yaml:"files"`
+}
+
+type ManifestFile struct {
+	Path   string `json:"path" yaml:"path"`
+	SHA256 string `json:"sha256" yaml:"sha256"`
+	Size   int64  `json:"size" yaml:"size"`
+}
+
+func NewManifestEndpoint(basePath string, manifest Manifest) ManifestEndpoint {
+	outputPath := path.Join(
+		basePath,
+		ManifestFileName)
+
+	return ManifestEndpoint{
+		outputPath: outputPath,
+		data:       manifest,
+	}
+}
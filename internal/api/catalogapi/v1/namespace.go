@@ -0,0 +1,115 @@
+package catalogapiv1
+
+import (
+	"fmt"
+	"path"
+
+	catalogv1 "github.com/sensu/catalog-api/internal/api/catalog/v1"
+)
+
+// reservedNamespaces holds the names of the endpoints at the root of the api
+// version, which namespaces cannot take along with the names of the facets.
+var reservedNamespaces = []string{
+	"catalog",
+	"errors",
+	"facets",
+	"namespaces",
+	"search-index",
+}
+
+// IsReservedNamespace returns true if the endpoints of a namespace with the
+// given name would collide with other endpoints.
+func IsReservedNamespace(name string) bool {
+	for _, reserved := range reservedNamespaces {
+		if name == reserved {
+			return true
+		}
+	}
+	return IsFacet(name)
+}
+
+// GET /api/:generated_sha/v1/namespaces.json
+type NamespacesEndpoint struct {
+	outputPath string
+	data       Namespaces
+}
+
+func (e NamespacesEndpoint) GetOutputPath() string { return e.outputPath }
+func (e NamespacesEndpoint) GetData() interface{}  { return e.data }
+
+type Namespaces struct {
+	Namespaces []IntegrationNamespace `json:"namespaces" yaml:"namespaces"`
+}
+
+func NewNamespacesEndpoint(basePath string, namespaces Namespaces) NamespacesEndpoint {
+	outputPath := path.Join(
+		basePath,
+		apiVersion,
+		"namespaces.json")
+
+	return NamespacesEndpoint{
+		outputPath: outputPath,
+		data:       namespaces,
+	}
+}
+
+// GET /api/:generated_sha/v1/integrations/:namespace.json
+type IntegrationNamespaceEndpoint struct {
+	outputPath string
+	data       IntegrationNamespace
+}
+
+func (e IntegrationNamespaceEndpoint) GetOutputPath() string { return e.outputPath }
+func (e IntegrationNamespaceEndpoint) GetData() interface{}  { return e.data }
+
+type IntegrationNamespace struct {
+	Name        string `json:"name" yaml:"name"`
+	DisplayName string `json:"display_name,omitempty" yaml:"display_name,omitempty"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+
+	// Logo is the path of the logo endpoint of the namespace, relative to
+	// the api version, e.g. "nginx/logo.png".
+	Logo         string                    `json:"logo,omitempty" yaml:"logo,omitempty"`
+	Owners       []string                  `json:"owners,omitempty" yaml:"owners,omitempty"`
+	Links        []catalogv1.NamespaceLink `json:"links,omitempty" yaml:"links,omitempty"`
+	Integrations []string                  `json:"integrations" yaml:"integrations"`
+}
+
+func NewIntegrationNamespaceEndpoint(basePath string, ns IntegrationNamespace) IntegrationNamespaceEndpoint {
+	outputPath := path.Join(
+		basePath,
+		apiVersion,
+		fmt.Sprintf("%s.json", ns.Name))
+
+	return IntegrationNamespaceEndpoint{
+		outputPath: outputPath,
+		data:       ns,
+	}
+}
+
+// GET /api/:generated_sha/v1/integrations/:namespace/logo.png
+type IntegrationNamespaceLogoEndpoint struct {
+	outputPath string
+	data       string
+}
+
+func (e IntegrationNamespaceLogoEndpoint) GetOutputPath() string { return e.outputPath }
+func (e IntegrationNamespaceLogoEndpoint) GetData() interface{}  { return e.data }
+
+// IntegrationNamespaceLogoPath returns the path of the logo endpoint of a
+// namespace, relative to the api version.
+func IntegrationNamespaceLogoPath(namespace string) string {
+	return path.Join(namespace, "logo.png")
+}
+
+func NewIntegrationNamespaceLogoEndpoint(basePath string, namespace string, data string) IntegrationNamespaceLogoEndpoint {
+	outputPath := path.Join(
+		basePath,
+		apiVersion,
+		IntegrationNamespaceLogoPath(namespace))
+
+	return IntegrationNamespaceLogoEndpoint{
+		outputPath: outputPath,
+		data:       data,
+	}
+}
@@ -0,0 +1,51 @@
+package catalogapiv1
+
+import (
+	"path"
+)
+
+// GET /api/releases.json
+type ReleasesEndpoint struct {
+	outputPath string
+	data       ReleaseHistory
+}
+
+func (e ReleasesEndpoint) GetOutputPath() string { return e.outputPath }
+func (e ReleasesEndpoint) GetData() interface{}  { return e.data }
+
+// ReleaseHistory lists the published releases that have been retained, most
+// recent first. Current is the checksum of the release that the version
+// endpoint points to.
+type ReleaseHistory struct {
+	Current  string        `json:"current" yaml:"current"`
+	Releases []ReleaseInfo `json:"releases" yaml:"releases"`
+}
+
+// ReleaseInfo describes a published release. Created is the last_updated
+// timestamp of the version endpoint that was published with the release and
+// VersionSignature its signature, if any, so that the version endpoint can be
+// restored when rolling back to the release. Published is the time the
+// release was published at, which differs from Created for reproducible
+// releases, and is what the retention of releases is based on.
+type ReleaseInfo struct {
+	ReleaseSHA256    string     `json:"release_sha256" yaml:"release_sha256"`
+	Created          int64      `json:"created" yaml:"created"`
+	Published        int64      `json:"published" yaml:"published"`
+	SourceCommit     string     `json:"source_commit,omitempty" yaml:"source_commit,omitempty"`
+	Namespaces       int        `json:"namespaces" yaml:"namespaces"`
+	Integrations     int        `json:"integrations" yaml:"integrations"`
+	Versions         int        `json:"versions" yaml:"versions"`
+	PublicKeyID      string     `json:"public_key_id,omitempty" yaml:"public_key_id,omitempty"`
+	VersionSignature *Signature `json:"version_signature,omitempty" yaml:"version_signature,omitempty"`
+}
+
+func NewReleasesEndpoint(basePath string, history ReleaseHistory) ReleasesEndpoint {
+	outputPath := path.Join(
+		basePath,
+		"releases.json")
+
+	return ReleasesEndpoint{
+		outputPath: outputPath,
+		data:       history,
+	}
+}
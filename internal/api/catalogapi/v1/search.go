@@ -0,0 +1,28 @@
+package catalogapiv1
+
+import (
+	"path"
+
+	"github.com/sensu/catalog-api/internal/searchindex"
+)
+
+// GET /api/:generated_sha/v1/search-index.json
+type SearchIndexEndpoint struct {
+	outputPath string
+	data       searchindex.Index
+}
+
+func (e SearchIndexEndpoint) GetOutputPath() string { return e.outputPath }
+func (e SearchIndexEndpoint) GetData() interface{}  { return e.data }
+
+func NewSearchIndexEndpoint(basePath string, index searchindex.Index) SearchIndexEndpoint {
+	outputPath := path.Join(
+		basePath,
+		apiVersion,
+		"search-index.json")
+
+	return SearchIndexEndpoint{
+		outputPath: outputPath,
+		data:       index,
+	}
+}
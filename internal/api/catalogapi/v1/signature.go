@@ -0,0 +1,36 @@
+package catalogapiv1
+
+import (
+	"path"
+)
+
+// SignatureExt is appended to the path of a signed endpoint to determine the
+// path of its detached signature.
+const SignatureExt = ".sig"
+
+// GET /api/version.json.sig
+// GET /api/:release_sha256/manifest.json.sig
+type SignatureEndpoint struct {
+	outputPath string
+	data       Signature
+}
+
+func (e SignatureEndpoint) GetOutputPath() string { return e.outputPath }
+func (e SignatureEndpoint) GetData() interface{}  { return e.data }
+
+// Signature is a detached ed25519 signature of the exact contents of another
+// endpoint. Signature is base64 encoded.
+type Signature struct {
+	Algorithm string `json:"algorithm" yaml:"algorithm"`
+	KeyID     string `json:"key_id" yaml:"key_id"`
+	Signature string `json:"signature" yaml:"signature"`
+}
+
+func NewSignatureEndpoint(signedPath string, signature Signature) SignatureEndpoint {
+	outputPath := path.Clean(signedPath + SignatureExt)
+
+	return SignatureEndpoint{
+		outputPath: outputPath,
+		data:       signature,
+	}
+}
@@ -2,8 +2,17 @@ package catalogapiv1
 
 import (
 	"path"
+	"regexp"
 )
 
+var reReleaseChecksum = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// IsReleaseChecksum returns true if s is a release checksum, i.e. the
+// lowercase hex encoded sha256 that names the directory of a release.
+func IsReleaseChecksum(s string) bool {
+	return reReleaseChecksum.MatchString(s)
+}
+
 // GET /api/version.json
 type VersionEndpoint struct {
 	outputPath string
@@ -16,6 +25,7 @@ func (e VersionEndpoint) GetData() interface{}  { return e.data }
 type ReleaseVersion struct {
 	ReleaseSHA256 string `json:"release_sha256" yaml:"release_sha256"`
 	LastUpdated   int64  `json:"last_updated" yaml:"last_updated"`
+	PublicKeyID   string `json:"public_key_id,omitempty" yaml:"public_key_id,omitempty"`
 }
 
 func NewVersionEndpoint(basePath string, version ReleaseVersion) VersionEndpoint {
@@ -0,0 +1,351 @@
+package catalogdiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	catalogv1 "github.com/sensu/catalog-api/internal/api/catalog/v1"
+	catalogapiv1 "github.com/sensu/catalog-api/internal/api/catalogapi/v1"
+	"github.com/sensu/catalog-api/internal/catalogquery"
+)
+
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeChanged = "changed"
+)
+
+// Report describes the differences between two catalog releases.
+type Report struct {
+	OldSHA256    string              `json:"old_release_sha256"`
+	NewSHA256    string              `json:"new_release_sha256"`
+	Integrations []IntegrationChange `json:"integrations"`
+}
+
+// IntegrationChange describes an integration that was added, removed, or
+// that has versions that were added, removed or changed.
+type IntegrationChange struct {
+	Namespace string          `json:"namespace"`
+	Name      string          `json:"name"`
+	Change    string          `json:"change"`
+	Versions  []VersionChange `json:"versions"`
+}
+
+// VersionChange describes a version of an integration that was added,
+// removed or changed. Details are only included for changed versions.
+type VersionChange struct {
+	Version   string           `json:"version"`
+	Change    string           `json:"change"`
+	Fields    []FieldChange    `json:"fields,omitempty"`
+	Resources []ResourceChange `json:"resources,omitempty"`
+	Assets    []AssetChange    `json:"assets,omitempty"`
+}
+
+// FieldChange describes a changed value. Field is the dot separated path of
+// the value, e.g. "metadata.name" or "spec.interval".
+type FieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old,omitempty"`
+	New   interface{} `json:"new,omitempty"`
+}
+
+// ResourceChange describes a Sensu resource within sensu-resources.json that
+// was added, removed or changed. Resources are identified by their type, api
+// version, namespace & name.
+type ResourceChange struct {
+	Resource string        `json:"resource"`
+	Change   string        `json:"change"`
+	Fields   []FieldChange `json:"fields,omitempty"`
+}
+
+// AssetChange describes a file of an integration version, such as the
+// README, a dashboard or an image, that was added, removed or changed. Path
+// is relative to the directory of the integration version.
+type AssetChange struct {
+	Path   string `json:"path"`
+	Change string `json:"change"`
+}
+
+// HasChanges returns true if the releases differ.
+func (r Report) HasChanges() bool {
+	return len(r.Integrations) > 0
+}
+
+// Diff compares two releases.
+func Diff(oldRelease, newRelease *catalogquery.Release) (Report, error) {
+	report := Report{
+		OldSHA256:    oldRelease.SHA256,
+		NewSHA256:    newRelease.SHA256,
+		Integrations: []IntegrationChange{},
+	}
+
+	refs := map[string]bool{}
+	for _, integration := range oldRelease.Integrations {
+		refs[ref(integration)] = true
+	}
+	for _, integration := range newRelease.Integrations {
+		refs[ref(integration)] = true
+	}
+	sortedRefs := make([]string, 0, len(refs))
+	for r := range refs {
+		sortedRefs = append(sortedRefs, r)
+	}
+	sort.Strings(sortedRefs)
+
+	for _, r := range sortedRefs {
+		namespace, name := splitRef(r)
+		_, inOld := oldRelease.Integration(namespace, name)
+		_, inNew := newRelease.Integration(namespace, name)
+
+		change := IntegrationChange{
+			Namespace: namespace,
+			Name:      name,
+			Change:    ChangeChanged,
+		}
+		switch {
+		case !inOld:
+			change.Change = ChangeAdded
+		case !inNew:
+			change.Change = ChangeRemoved
+		}
+
+		versions, err := diffVersions(oldRelease.Versions(namespace, name), newRelease.Versions(namespace, name))
+		if err != nil {
+			return report, fmt.Errorf("error comparing %s: %w", r, err)
+		}
+		change.Versions = versions
+		if change.Change == ChangeChanged && len(versions) == 0 {
+			continue
+		}
+		report.Integrations = append(report.Integrations, change)
+	}
+
+	return report, nil
+}
+
+func diffVersions(oldVersions, newVersions []catalogquery.Version) ([]VersionChange, error) {
+	changes := []VersionChange{}
+
+	newByVersion := map[string]catalogquery.Version{}
+	for _, v := range newVersions {
+		newByVersion[v.Version] = v
+	}
+	oldByVersion := map[string]catalogquery.Version{}
+	for _, v := range oldVersions {
+		oldByVersion[v.Version] = v
+		if _, ok := newByVersion[v.Version]; !ok {
+			changes = append(changes, VersionChange{Version: v.Version, Change: ChangeRemoved})
+		}
+	}
+
+	for _, newVersion := range newVersions {
+		oldVersion, ok := oldByVersion[newVersion.Version]
+		if !ok {
+			changes = append(changes, VersionChange{Version: newVersion.Version, Change: ChangeAdded})
+			continue
+		}
+
+		change, err := diffVersion(oldVersion, newVersion)
+		if err != nil {
+			return changes, fmt.Errorf("version %s: %w", newVersion.Version, err)
+		}
+		if len(change.Fields) > 0 || len(change.Resources) > 0 || len(change.Assets) > 0 {
+			changes = append(changes, change)
+		}
+	}
+
+	// versions are listed lowest first by the release, however removed
+	// versions must be slotted in between
+	sort.SliceStable(changes, func(i, j int) bool {
+		return lessVersion(changes[i].Version, changes[j].Version)
+	})
+	return changes, nil
+}
+
+func diffVersion(oldVersion, newVersion catalogquery.Version) (VersionChange, error) {
+	change := VersionChange{
+		Version: newVersion.Version,
+		Change:  ChangeChanged,
+	}
+
+	oldIntegration, err := toGeneric(oldVersion.Integration)
+	if err != nil {
+		return change, err
+	}
+	newIntegration, err := toGeneric(newVersion.Integration)
+	if err != nil {
+		return change, err
+	}
+	change.Fields = diffValues("", oldIntegration, newIntegration)
+
+	change.Resources, err = diffResources(oldVersion.Resources, newVersion.Resources)
+	if err != nil {
+		return change, err
+	}
+
+	change.Assets = diffAssets(oldVersion, newVersion)
+	return change, nil
+}
+
+func diffResources(oldResources, newResources catalogv1.Resources) ([]ResourceChange, error) {
+	var changes []ResourceChange
+
+	oldByID := map[string]interface{}{}
+	for _, resource := range oldResources {
+		value, err := toGeneric(resource)
+		if err != nil {
+			return changes, err
+		}
+		oldByID[resourceID(resource)] = value
+	}
+	newByID := map[string]interface{}{}
+	for _, resource := range newResources {
+		value, err := toGeneric(resource)
+		if err != nil {
+			return changes, err
+		}
+		newByID[resourceID(resource)] = value
+	}
+
+	for _, id := range sortedKeys(oldByID, newByID) {
+		oldValue, inOld := oldByID[id]
+		newValue, inNew := newByID[id]
+		switch {
+		case !inOld:
+			changes = append(changes, ResourceChange{Resource: id, Change: ChangeAdded})
+		case !inNew:
+			changes = append(changes, ResourceChange{Resource: id, Change: ChangeRemoved})
+		default:
+			if fields := diffValues("", oldValue, newValue); len(fields) > 0 {
+				changes = append(changes, ResourceChange{Resource: id, Change: ChangeChanged, Fields: fields})
+			}
+		}
+	}
+	return changes, nil
+}
+
+func diffAssets(oldVersion, newVersion catalogquery.Version) []AssetChange {
+	var changes []AssetChange
+
+	if oldVersion.Readme != newVersion.Readme {
+		changes = append(changes, AssetChange{Path: "README.md", Change: ChangeChanged})
+	}
+	if !reflect.DeepEqual(oldVersion.Changelog, newVersion.Changelog) {
+		changes = append(changes, AssetChange{Path: "CHANGELOG.md", Change: ChangeChanged})
+	}
+
+	checksums := func(v catalogquery.Version) map[string]interface{} {
+		sums := map[string]interface{}{}
+		if v.Logo != nil {
+			sums[v.Logo.Name] = v.Logo.SHA256
+		}
+		for _, dashboard := range v.Dashboards {
+			sums["dashboards/"+dashboard.Name] = dashboard.SHA256
+		}
+		for _, image := range v.Images {
+			sums["img/"+image.Name] = image.SHA256
+		}
+		return sums
+	}
+	oldSums, newSums := checksums(oldVersion), checksums(newVersion)
+	for _, path := range sortedKeys(oldSums, newSums) {
+		oldSum, inOld := oldSums[path]
+		newSum, inNew := newSums[path]
+		switch {
+		case !inOld:
+			changes = append(changes, AssetChange{Path: path, Change: ChangeAdded})
+		case !inNew:
+			changes = append(changes, AssetChange{Path: path, Change: ChangeRemoved})
+		case oldSum != newSum:
+			changes = append(changes, AssetChange{Path: path, Change: ChangeChanged})
+		}
+	}
+	return changes
+}
+
+// diffValues recursively compares two values as decoded from JSON. Objects
+// are compared key by key, while any other value, including lists, is
+// compared as a whole.
+func diffValues(path string, oldValue, newValue interface{}) []FieldChange {
+	oldObject, oldIsObject := oldValue.(map[string]interface{})
+	newObject, newIsObject := newValue.(map[string]interface{})
+	if oldIsObject && newIsObject {
+		var changes []FieldChange
+		for _, key := range sortedKeys(oldObject, newObject) {
+			changes = append(changes, diffValues(joinPath(path, key), oldObject[key], newObject[key])...)
+		}
+		return changes
+	}
+
+	if reflect.DeepEqual(oldValue, newValue) {
+		return nil
+	}
+	return []FieldChange{{Field: path, Old: oldValue, New: newValue}}
+}
+
+func resourceID(resource catalogv1.Resource) string {
+	str := func(v interface{}) string {
+		s, _ := v.(string)
+		return s
+	}
+	metadata, _ := resource["metadata"].(map[string]interface{})
+	parts := []string{}
+	for _, part := range []string{str(resource["type"]), str(resource["api_version"]), str(metadata["name"])} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	id := strings.Join(parts, " ")
+	if namespace := str(metadata["namespace"]); namespace != "" {
+		id = fmt.Sprintf("%s (namespace: %s)", id, namespace)
+	}
+	return id
+}
+
+// toGeneric converts a value to its generic JSON representation so that
+// values read from different sources compare equal.
+func toGeneric(v interface{}) (interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling value: %w", err)
+	}
+	var generic interface{}
+	if err := json.Unmarshal(b, &generic); err != nil {
+		return nil, fmt.Errorf("error unmarshaling value: %w", err)
+	}
+	return generic, nil
+}
+
+func sortedKeys(maps ...map[string]interface{}) []string {
+	seen := map[string]bool{}
+	keys := []string{}
+	for _, m := range maps {
+		for key := range m {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func ref(integration catalogapiv1.IntegrationWithVersions) string {
+	return integration.Metadata.Namespace + "/" + integration.Metadata.Name
+}
+
+func splitRef(ref string) (string, string) {
+	parts := strings.SplitN(ref, "/", 2)
+	return parts[0], parts[1]
+}
@@ -0,0 +1,134 @@
+package catalogdiff
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	catalogv1 "github.com/sensu/catalog-api/internal/api/catalog/v1"
+	catalogapiv1 "github.com/sensu/catalog-api/internal/api/catalogapi/v1"
+	"github.com/sensu/catalog-api/internal/catalogquery"
+)
+
+// modifiedRelease returns a copy of the fixture release after applying fn
+// to its integrations & versions.
+func modifiedRelease(sha256 string, fn func(integrations []catalogapiv1.IntegrationWithVersions, versions map[string][]catalogquery.Version) []catalogapiv1.IntegrationWithVersions) *catalogquery.Release {
+	fixture := catalogquery.FixtureRelease()
+	versions := map[string][]catalogquery.Version{}
+	for _, integration := range fixture.Integrations {
+		r := ref(integration)
+		versions[r] = append(versions[r], fixture.Versions(integration.Metadata.Namespace, integration.Metadata.Name)...)
+	}
+	integrations := fn(fixture.Integrations, versions)
+	return catalogquery.NewRelease(sha256, integrations, versions, fixture.SearchIndex)
+}
+
+func TestDiff(t *testing.T) {
+	oldRelease := catalogquery.FixtureRelease()
+	newRelease := modifiedRelease("def456", func(integrations []catalogapiv1.IntegrationWithVersions, versions map[string][]catalogquery.Version) []catalogapiv1.IntegrationWithVersions {
+		// remove an integration
+		delete(versions, "pagerduty/pagerduty-incidents")
+		integrations = append(integrations[:1], integrations[2:]...)
+
+		// remove one version & change another
+		nginx := versions["nginx/nginx-monitoring"]
+		changed := nginx[1]
+		changed.DisplayName = "NGINX"
+		changed.Resources = append(changed.Resources, map[string]interface{}{
+			"type":        "Handler",
+			"api_version": "core/v2",
+			"metadata":    map[string]interface{}{"name": "slack"},
+		})
+		changed.Readme = "# NGINX"
+		changed.Dashboards = []catalogquery.Asset{{Name: "nginx.json", SHA256: "abc"}}
+		versions["nginx/nginx-monitoring"] = []catalogquery.Version{nginx[0], changed, nginx[3]}
+
+		// change a resource of another integration
+		host := versions["system/host-monitoring"][0]
+		host.Resources = catalogv1.Resources{{"type": "CheckConfig", "spec": map[string]interface{}{"interval": 60}}}
+		versions["system/host-monitoring"] = []catalogquery.Version{host}
+		return integrations
+	})
+
+	report, err := Diff(oldRelease, newRelease)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Report{
+		OldSHA256: "abc123",
+		NewSHA256: "def456",
+		Integrations: []IntegrationChange{
+			{
+				Namespace: "nginx",
+				Name:      "nginx-monitoring",
+				Change:    ChangeChanged,
+				Versions: []VersionChange{
+					{
+						Version: "1.2.5",
+						Change:  ChangeChanged,
+						Fields:  []FieldChange{{Field: "display_name", Old: "NGINX Monitoring", New: "NGINX"}},
+						Resources: []ResourceChange{
+							{Resource: "Handler core/v2 slack", Change: ChangeAdded},
+						},
+						Assets: []AssetChange{
+							{Path: "README.md", Change: ChangeChanged},
+							{Path: "dashboards/nginx.json", Change: ChangeAdded},
+						},
+					},
+					{Version: "1.3.0", Change: ChangeRemoved},
+				},
+			},
+			{
+				Namespace: "pagerduty",
+				Name:      "pagerduty-incidents",
+				Change:    ChangeRemoved,
+				Versions:  []VersionChange{{Version: "0.1.0", Change: ChangeRemoved}},
+			},
+			{
+				Namespace: "system",
+				Name:      "host-monitoring",
+				Change:    ChangeChanged,
+				Versions: []VersionChange{
+					{
+						Version: "20220125.0.0",
+						Change:  ChangeChanged,
+						Resources: []ResourceChange{
+							{Resource: "CheckConfig", Change: ChangeChanged, Fields: []FieldChange{{Field: "spec", New: map[string]interface{}{"interval": float64(60)}}}},
+						},
+					},
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(report, want) {
+		t.Errorf("Diff() = %+v, want %+v", report, want)
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteText(&buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		"- pagerduty/pagerduty-incidents",
+		"    ~ 1.2.5",
+		`        display_name: "NGINX Monitoring" => "NGINX"`,
+		"        + resource Handler core/v2 slack",
+		"        + dashboards/nginx.json",
+		`            spec: (none) => {"interval":60}`,
+	} {
+		if !strings.Contains(buf.String(), line+"\n") {
+			t.Errorf("WriteText() missing line %q in:\n%s", line, buf.String())
+		}
+	}
+}
+
+func TestDiff_NoChanges(t *testing.T) {
+	report, err := Diff(catalogquery.FixtureRelease(), catalogquery.FixtureRelease())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.HasChanges() {
+		t.Errorf("Diff() = %+v, want no changes", report)
+	}
+}
@@ -0,0 +1,96 @@
+package catalogdiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	semver "github.com/Masterminds/semver/v3"
+)
+
+var changeSymbols = map[string]string{
+	ChangeAdded:   "+",
+	ChangeRemoved: "-",
+	ChangeChanged: "~",
+}
+
+// WriteText writes a human readable representation of the report, e.g.
+//
+//	~ nginx/nginx-monitoring
+//	    + 1.1.0
+//	    ~ 1.0.0
+//	        display_name: "NGINX" => "NGINX Monitoring"
+//	        ~ resource CheckConfig core/v2 nginx-healthcheck
+//	            spec.interval: 30 => 60
+//	        + dashboards/nginx.json
+func (r Report) WriteText(w io.Writer) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Comparing release %s to %s\n", shortSHA(r.OldSHA256), shortSHA(r.NewSHA256))
+	if !r.HasChanges() {
+		sb.WriteString("\nNo changes\n")
+	}
+
+	for _, integration := range r.Integrations {
+		fmt.Fprintf(&sb, "\n%s %s/%s\n", changeSymbols[integration.Change], integration.Namespace, integration.Name)
+		for _, version := range integration.Versions {
+			fmt.Fprintf(&sb, "    %s %s\n", changeSymbols[version.Change], version.Version)
+			for _, field := range version.Fields {
+				writeFieldChange(&sb, 8, field)
+			}
+			for _, resource := range version.Resources {
+				fmt.Fprintf(&sb, "        %s resource %s\n", changeSymbols[resource.Change], resource.Resource)
+				for _, field := range resource.Fields {
+					writeFieldChange(&sb, 12, field)
+				}
+			}
+			for _, asset := range version.Assets {
+				fmt.Fprintf(&sb, "        %s %s\n", changeSymbols[asset.Change], asset.Path)
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r Report) WriteJSON(w io.Writer) error {
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling diff: %w", err)
+	}
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
+
+func writeFieldChange(sb *strings.Builder, indent int, field FieldChange) {
+	fmt.Fprintf(sb, "%s%s: %s => %s\n", strings.Repeat(" ", indent), field.Field, formatValue(field.Old), formatValue(field.New))
+}
+
+func formatValue(v interface{}) string {
+	if v == nil {
+		return "(none)"
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 12 {
+		return sha[:12]
+	}
+	return sha
+}
+
+func lessVersion(a, b string) bool {
+	va, errA := semver.NewVersion(a)
+	vb, errB := semver.NewVersion(b)
+	if errA != nil || errB != nil {
+		return a < b
+	}
+	return va.LessThan(vb)
+}
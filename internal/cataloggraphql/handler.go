@@ -0,0 +1,139 @@
+package cataloggraphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/rs/zerolog/log"
+	"github.com/sensu/catalog-api/internal/catalogquery"
+)
+
+// maxRequestBytes limits the size of request bodies accepted by the handler.
+const maxRequestBytes = 1 << 20
+
+// Request is a GraphQL request as sent by GraphQL clients, either as the body
+// of a POST request or as the query string of a GET request.
+type Request struct {
+	Query         string                 `json:"query"`
+	Variables     map[string]interface{} `json:"variables"`
+	OperationName string                 `json:"operationName"`
+}
+
+// Handler serves GraphQL requests against the release currently held by the
+// store.
+type Handler struct {
+	schema graphql.Schema
+	store  *catalogquery.Store
+}
+
+func NewHandler(store *catalogquery.Store) (*Handler, error) {
+	schema, err := NewSchema()
+	if err != nil {
+		return nil, fmt.Errorf("error building graphql schema: %w", err)
+	}
+	return &Handler{
+		schema: schema,
+		store:  store,
+	}, nil
+}
+
+// Execute runs a request against the given release. Requests exceeding the
+// depth & field limits are rejected without being executed.
+func Execute(schema graphql.Schema, release *catalogquery.Release, request Request) *graphql.Result {
+	if err := checkLimits(request.Query); err != nil {
+		return errorResult(err)
+	}
+	return graphql.Do(graphql.Params{
+		Schema:         schema,
+		RequestString:  request.Query,
+		VariableValues: request.Variables,
+		OperationName:  request.OperationName,
+		RootObject: map[string]interface{}{
+			rootReleaseKey: release,
+		},
+	})
+}
+
+// GET /catalog-graphql?query=&variables=&operationName=
+// POST /catalog-graphql
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		w.Header().Set("Allow", "GET, POST")
+		writeResult(w, http.StatusMethodNotAllowed, errorResult(fmt.Errorf("method must be GET or POST, got: %s", r.Method)))
+		return
+	}
+
+	request, err := parseRequest(r)
+	if err != nil {
+		writeResult(w, http.StatusBadRequest, errorResult(err))
+		return
+	}
+	if request.Query == "" {
+		writeResult(w, http.StatusBadRequest, errorResult(fmt.Errorf("query must not be empty")))
+		return
+	}
+
+	release := h.store.Release()
+	if release == nil {
+		writeResult(w, http.StatusServiceUnavailable, errorResult(errNoRelease))
+		return
+	}
+
+	writeResult(w, http.StatusOK, Execute(h.schema, release, request))
+}
+
+func parseRequest(r *http.Request) (Request, error) {
+	request := Request{}
+
+	switch r.Method {
+	case http.MethodGet:
+		values := r.URL.Query()
+		request.Query = values.Get("query")
+		request.OperationName = values.Get("operationName")
+		if variables := values.Get("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &request.Variables); err != nil {
+				return request, fmt.Errorf("error parsing variables: %w", err)
+			}
+		}
+	case http.MethodPost:
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestBytes))
+		if err != nil {
+			return request, fmt.Errorf("error reading request body: %w", err)
+		}
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if mediaType == "application/graphql" {
+			request.Query = string(body)
+			break
+		}
+		if err := json.Unmarshal(body, &request); err != nil {
+			return request, fmt.Errorf("error parsing request body: %w", err)
+		}
+	}
+
+	return request, nil
+}
+
+func errorResult(err error) *graphql.Result {
+	return &graphql.Result{
+		Errors: []gqlerrors.FormattedError{gqlerrors.FormatError(err)},
+	}
+}
+
+func writeResult(w http.ResponseWriter, status int, result *graphql.Result) {
+	b, err := json.Marshal(result)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to marshal graphql response")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if _, err := w.Write(b); err != nil {
+		log.Debug().Err(err).Msg("Failed to write response")
+	}
+}
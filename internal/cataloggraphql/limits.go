@@ -0,0 +1,103 @@
+package cataloggraphql
+
+import (
+	"fmt"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+// maxQueryDepth & maxQueryFields bound the cost of the queries that clients,
+// which need not be authenticated, can run against the release. The deepest
+// field of the schema is nested 4 levels deep.
+const (
+	maxQueryDepth  = 10
+	maxQueryFields = 1000
+)
+
+// checkLimits returns an error if the query nests fields more than
+// maxQueryDepth levels deep, selects more than maxQueryFields fields,
+// counting the fields of a fragment every time it is spread, or contains a
+// fragment that spreads itself. Queries that can't be parsed are left to
+// graphql.Do to report.
+func checkLimits(query string) error {
+	doc, err := parser.Parse(parser.ParseParams{Source: query})
+	if err != nil {
+		return nil
+	}
+
+	l := limiter{
+		fragments: map[string]*ast.FragmentDefinition{},
+		spreading: map[string]bool{},
+	}
+	for _, definition := range doc.Definitions {
+		if fragment, ok := definition.(*ast.FragmentDefinition); ok && fragment.Name != nil {
+			l.fragments[fragment.Name.Value] = fragment
+		}
+	}
+	for _, definition := range doc.Definitions {
+		if operation, ok := definition.(*ast.OperationDefinition); ok {
+			if err := l.walk(operation.SelectionSet, 1); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+type limiter struct {
+	fragments map[string]*ast.FragmentDefinition
+
+	// spreading holds the fragments being walked, to detect fragments that
+	// spread themselves
+	spreading map[string]bool
+
+	fields int
+}
+
+func (l *limiter) walk(set *ast.SelectionSet, depth int) error {
+	if set == nil {
+		return nil
+	}
+	if depth > maxQueryDepth {
+		return fmt.Errorf("query must not nest fields more than %d levels deep", maxQueryDepth)
+	}
+	for _, selection := range set.Selections {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			l.fields++
+			if l.fields > maxQueryFields {
+				return fmt.Errorf("query must not select more than %d fields", maxQueryFields)
+			}
+			if err := l.walk(selection.SelectionSet, depth+1); err != nil {
+				return err
+			}
+		case *ast.InlineFragment:
+			if err := l.walk(selection.SelectionSet, depth); err != nil {
+				return err
+			}
+		case *ast.FragmentSpread:
+			if selection.Name == nil {
+				continue
+			}
+			name := selection.Name.Value
+			fragment, ok := l.fragments[name]
+			if !ok {
+				// unknown fragments are rejected by validation
+				continue
+			}
+			if l.spreading[name] {
+				// validating cyclic fragments overflows the stack within
+				// graphql.Do, so they must be rejected beforehand
+				return fmt.Errorf("fragment %s must not spread itself", name)
+			}
+			l.spreading[name] = true
+			err := l.walk(fragment.SelectionSet, depth)
+			delete(l.spreading, name)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,444 @@
+package cataloggraphql
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/graphql-go/graphql"
+	catalogv1 "github.com/sensu/catalog-api/internal/api/catalog/v1"
+	catalogapiv1 "github.com/sensu/catalog-api/internal/api/catalogapi/v1"
+	"github.com/sensu/catalog-api/internal/catalogquery"
+)
+
+// rootReleaseKey is the key of the release within the root object passed to
+// the executor. Resolvers read the release from the root object, rather than
+// from the store, so that every field of a single request is resolved against
+// the same release even if the store is reloaded mid-request.
+const rootReleaseKey = "release"
+
+var errNoRelease = errors.New("no release loaded")
+
+type namespace struct {
+	Name string
+}
+
+// NewSchema builds the GraphQL schema for querying the in-memory release.
+func NewSchema() (graphql.Schema, error) {
+	promptType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Prompt",
+		Fields: graphql.Fields{
+			"type":     &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"name":     &graphql.Field{Type: graphql.String},
+			"title":    &graphql.Field{Type: graphql.String},
+			"body":     &graphql.Field{Type: graphql.String},
+			"required": &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+			"input":    &graphql.Field{Type: jsonScalar},
+		},
+	})
+
+	changelogEntryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "ChangelogEntry",
+		Fields: graphql.Fields{
+			"version":    &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"date":       &graphql.Field{Type: graphql.String},
+			"yanked":     &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+			"added":      &graphql.Field{Type: changelogSection},
+			"changed":    &graphql.Field{Type: changelogSection},
+			"deprecated": &graphql.Field{Type: changelogSection},
+			"removed":    &graphql.Field{Type: changelogSection},
+			"fixed":      &graphql.Field{Type: changelogSection},
+			"security":   &graphql.Field{Type: changelogSection},
+		},
+	})
+
+	imageType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Image",
+		Description: "An image belonging to an integration version. The path is relative to the root of the API.",
+		Fields: graphql.Fields{
+			"name": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"path": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		},
+	})
+
+	dashboardType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Dashboard",
+		Description: "A dashboard belonging to an integration version. The path is relative to the root of the API.",
+		Fields: graphql.Fields{
+			"name": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"path": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"data": &graphql.Field{
+				Type: jsonScalar,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					var data interface{}
+					if err := json.Unmarshal(p.Source.(catalogquery.Asset).Data, &data); err != nil {
+						return nil, fmt.Errorf("error unmarshaling dashboard: %w", err)
+					}
+					return data, nil
+				},
+			},
+		},
+	})
+
+	versionFields := integrationFields(func(source interface{}) catalogv1.Integration {
+		return source.(catalogquery.Version).Integration
+	})
+	versionFields["version"] = &graphql.Field{
+		Type: graphql.NewNonNull(graphql.String),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return p.Source.(catalogquery.Version).Version, nil
+		},
+	}
+	versionFields["prompts"] = &graphql.Field{
+		Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(promptType))),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			if prompts := p.Source.(catalogquery.Version).Prompts; prompts != nil {
+				return prompts, nil
+			}
+			return []catalogv1.Prompt{}, nil
+		},
+	}
+	versionFields["resources"] = &graphql.Field{
+		Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(jsonScalar))),
+		Description: "Sensu resources installed by the integration",
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			if resources := p.Source.(catalogquery.Version).Resources; resources != nil {
+				return resources, nil
+			}
+			return catalogv1.Resources{}, nil
+		},
+	}
+	versionFields["readme"] = &graphql.Field{
+		Type: graphql.NewNonNull(graphql.String),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return p.Source.(catalogquery.Version).Readme, nil
+		},
+	}
+	versionFields["changelog"] = &graphql.Field{
+		Type:        changelogEntryType,
+		Description: "Changelog entry for the version",
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			version := p.Source.(catalogquery.Version)
+			if entry, ok := version.Changelog.Entry(version.Version); ok {
+				return entry, nil
+			}
+			return nil, nil
+		},
+	}
+	versionFields["dashboards"] = &graphql.Field{
+		Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(dashboardType))),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return p.Source.(catalogquery.Version).Dashboards, nil
+		},
+	}
+	versionFields["images"] = &graphql.Field{
+		Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(imageType))),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return p.Source.(catalogquery.Version).Images, nil
+		},
+	}
+	versionFields["logo"] = &graphql.Field{
+		Type: imageType,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			if logo := p.Source.(catalogquery.Version).Logo; logo != nil {
+				return *logo, nil
+			}
+			return nil, nil
+		},
+	}
+
+	versionType := graphql.NewObject(graphql.ObjectConfig{
+		Name:   "IntegrationVersion",
+		Fields: versionFields,
+	})
+
+	integrationFields := integrationFields(func(source interface{}) catalogv1.Integration {
+		return source.(catalogapiv1.IntegrationWithVersions).Integration
+	})
+	integrationFields["latestVersion"] = &graphql.Field{
+		Type: graphql.NewNonNull(graphql.String),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return p.Source.(catalogapiv1.IntegrationWithVersions).Version, nil
+		},
+	}
+	integrationFields["versions"] = &graphql.Field{
+		Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(versionType))),
+		Description: "All versions of the integration, lowest first",
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			release, err := releaseFromParams(p)
+			if err != nil {
+				return nil, err
+			}
+			integration := p.Source.(catalogapiv1.IntegrationWithVersions)
+			return release.Versions(integration.Metadata.Namespace, integration.Metadata.Name), nil
+		},
+	}
+	integrationFields["version"] = &graphql.Field{
+		Type:        versionType,
+		Description: "A single version of the integration. The latest version is returned when neither version nor constraint are given.",
+		Args: graphql.FieldConfigArgument{
+			"version":    &graphql.ArgumentConfig{Type: graphql.String},
+			"constraint": &graphql.ArgumentConfig{Type: graphql.String, Description: "semver constraint, e.g. ^1.2"},
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			release, err := releaseFromParams(p)
+			if err != nil {
+				return nil, err
+			}
+			integration := p.Source.(catalogapiv1.IntegrationWithVersions)
+			namespace, name := integration.Metadata.Namespace, integration.Metadata.Name
+
+			want := integration.Version
+			if v, ok := p.Args["version"].(string); ok {
+				want = v
+			} else if constraint, ok := p.Args["constraint"].(string); ok {
+				resolution, err := release.Resolve(namespace, name, constraint)
+				if errors.Is(err, catalogquery.ErrNoMatchingVersion) {
+					return nil, nil
+				} else if err != nil {
+					return nil, err
+				}
+				want = resolution.Version
+			}
+
+			for _, version := range release.Versions(namespace, name) {
+				if version.Version == want {
+					return version, nil
+				}
+			}
+			return nil, nil
+		},
+	}
+
+	integrationType := graphql.NewObject(graphql.ObjectConfig{
+		Name:   "Integration",
+		Fields: integrationFields,
+	})
+
+	namespaceType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Namespace",
+		Fields: graphql.Fields{
+			"name": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"integrations": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(integrationType))),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					release, err := releaseFromParams(p)
+					if err != nil {
+						return nil, err
+					}
+					return namespaceIntegrations(release, p.Source.(namespace).Name), nil
+				},
+			},
+		},
+	})
+
+	releaseType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Release",
+		Fields: graphql.Fields{
+			"sha256": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		},
+	})
+
+	queryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"release": &graphql.Field{
+				Type: graphql.NewNonNull(releaseType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					release, err := releaseFromParams(p)
+					if err != nil {
+						return nil, err
+					}
+					return map[string]interface{}{"sha256": release.SHA256}, nil
+				},
+			},
+			"namespaces": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(namespaceType))),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					release, err := releaseFromParams(p)
+					if err != nil {
+						return nil, err
+					}
+					namespaces := []namespace{}
+					for _, name := range release.Namespaces() {
+						namespaces = append(namespaces, namespace{Name: name})
+					}
+					return namespaces, nil
+				},
+			},
+			"namespace": &graphql.Field{
+				Type: namespaceType,
+				Args: graphql.FieldConfigArgument{
+					"name": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					release, err := releaseFromParams(p)
+					if err != nil {
+						return nil, err
+					}
+					name := p.Args["name"].(string)
+					for _, ns := range release.Namespaces() {
+						if ns == name {
+							return namespace{Name: name}, nil
+						}
+					}
+					return nil, nil
+				},
+			},
+			"integrations": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(integrationType))),
+				Description: "Integrations matching all of the given filters. Results are sorted by relevance when searching, otherwise by namespace & name.",
+				Args: graphql.FieldConfigArgument{
+					"namespace": &graphql.ArgumentConfig{Type: graphql.String},
+					"tags":      &graphql.ArgumentConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+					"provider":  &graphql.ArgumentConfig{Type: graphql.String},
+					"class":     &graphql.ArgumentConfig{Type: graphql.String},
+					"platform":  &graphql.ArgumentConfig{Type: graphql.String},
+					"search":    &graphql.ArgumentConfig{Type: graphql.String, Description: "full text search"},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					release, err := releaseFromParams(p)
+					if err != nil {
+						return nil, err
+					}
+					return queryIntegrations(release, p.Args), nil
+				},
+			},
+			"integration": &graphql.Field{
+				Type: integrationType,
+				Args: graphql.FieldConfigArgument{
+					"namespace": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					"name":      &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					release, err := releaseFromParams(p)
+					if err != nil {
+						return nil, err
+					}
+					integration, ok := release.Integration(p.Args["namespace"].(string), p.Args["name"].(string))
+					if !ok {
+						return nil, nil
+					}
+					return integration, nil
+				},
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{
+		Query: queryType,
+	})
+}
+
+var (
+	stringList       = graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String)))
+	changelogSection = graphql.NewList(graphql.NewNonNull(graphql.String))
+)
+
+// integrationFields returns the fields shared by the Integration &
+// IntegrationVersion types. get extracts the integration config from the
+// source object of either type.
+func integrationFields(get func(source interface{}) catalogv1.Integration) graphql.Fields {
+	field := func(typ graphql.Output, value func(catalogv1.Integration) interface{}) *graphql.Field {
+		return &graphql.Field{
+			Type: typ,
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return value(get(p.Source)), nil
+			},
+		}
+	}
+	nonNullString := graphql.NewNonNull(graphql.String)
+
+	return graphql.Fields{
+		"namespace": field(nonNullString, func(i catalogv1.Integration) interface{} {
+			return i.Metadata.Namespace
+		}),
+		"name": field(nonNullString, func(i catalogv1.Integration) interface{} {
+			return i.Metadata.Name
+		}),
+		"displayName": field(nonNullString, func(i catalogv1.Integration) interface{} {
+			return i.DisplayName
+		}),
+		"class": field(nonNullString, func(i catalogv1.Integration) interface{} {
+			return i.Class
+		}),
+		"provider": field(nonNullString, func(i catalogv1.Integration) interface{} {
+			return i.Provider
+		}),
+		"shortDescription": field(nonNullString, func(i catalogv1.Integration) interface{} {
+			return i.ShortDescription
+		}),
+		"contributors": field(stringList, func(i catalogv1.Integration) interface{} {
+			return nonNilStrings(i.Contributors)
+		}),
+		"supportedPlatforms": field(stringList, func(i catalogv1.Integration) interface{} {
+			return nonNilStrings(i.SupportedPlatforms)
+		}),
+		"tags": field(stringList, func(i catalogv1.Integration) interface{} {
+			return nonNilStrings(i.Tags)
+		}),
+	}
+}
+
+func queryIntegrations(release *catalogquery.Release, args map[string]interface{}) []catalogapiv1.IntegrationWithVersions {
+	query := catalogquery.Query{
+		Sort:    catalogquery.SortName,
+		Page:    1,
+		PerPage: len(release.Integrations),
+	}
+	if tags, ok := args["tags"].([]interface{}); ok {
+		for _, tag := range tags {
+			query.Tags = append(query.Tags, tag.(string))
+		}
+	}
+	query.Provider, _ = args["provider"].(string)
+	query.Class, _ = args["class"].(string)
+	query.Platform, _ = args["platform"].(string)
+	if search, ok := args["search"].(string); ok && search != "" {
+		query.Text = search
+		query.Sort = catalogquery.SortRelevance
+	}
+	namespace, _ := args["namespace"].(string)
+
+	integrations := []catalogapiv1.IntegrationWithVersions{}
+	for _, integration := range release.Query(query).Integrations {
+		if namespace != "" && integration.Metadata.Namespace != namespace {
+			continue
+		}
+		integrations = append(integrations, integration.IntegrationWithVersions)
+	}
+	return integrations
+}
+
+func namespaceIntegrations(release *catalogquery.Release, namespace string) []catalogapiv1.IntegrationWithVersions {
+	integrations := []catalogapiv1.IntegrationWithVersions{}
+	for _, integration := range release.Integrations {
+		if integration.Metadata.Namespace == namespace {
+			integrations = append(integrations, integration)
+		}
+	}
+	sort.Slice(integrations, func(i, j int) bool {
+		return integrations[i].Metadata.Name < integrations[j].Metadata.Name
+	})
+	return integrations
+}
+
+func releaseFromParams(p graphql.ResolveParams) (*catalogquery.Release, error) {
+	root, ok := p.Info.RootValue.(map[string]interface{})
+	if !ok {
+		return nil, errNoRelease
+	}
+	release, ok := root[rootReleaseKey].(*catalogquery.Release)
+	if !ok || release == nil {
+		return nil, errNoRelease
+	}
+	return release, nil
+}
+
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -0,0 +1,171 @@
+package cataloggraphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sensu/catalog-api/internal/catalogquery"
+)
+
+func execute(t *testing.T, query string, variables map[string]interface{}) string {
+	t.Helper()
+	schema, err := NewSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := Execute(schema, catalogquery.FixtureRelease(), Request{
+		Query:     query,
+		Variables: variables,
+	})
+	if result.HasErrors() {
+		t.Fatalf("Execute() errors = %v", result.Errors)
+	}
+	b, err := json.Marshal(result.Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestSchema(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]interface{}
+		want      string
+	}{
+		{
+			name:  "release",
+			query: `{ release { sha256 } }`,
+			want:  `{"release":{"sha256":"abc123"}}`,
+		},
+		{
+			name:  "namespaces",
+			query: `{ namespaces { name integrations { name } } }`,
+			want:  `{"namespaces":[{"integrations":[{"name":"nginx-monitoring"}],"name":"nginx"},{"integrations":[{"name":"pagerduty-incidents"}],"name":"pagerduty"},{"integrations":[{"name":"host-monitoring"}],"name":"system"}]}`,
+		},
+		{
+			name:  "unknown namespace",
+			query: `{ namespace(name: "foo") { name } }`,
+			want:  `{"namespace":null}`,
+		},
+		{
+			name:  "filter integrations",
+			query: `{ integrations(provider: "alerts", platform: "windows") { namespace name } }`,
+			want:  `{"integrations":[{"name":"host-monitoring","namespace":"system"}]}`,
+		},
+		{
+			name:  "search integrations",
+			query: `{ integrations(search: "monitoring") { name } }`,
+			want:  `{"integrations":[{"name":"nginx-monitoring"},{"name":"host-monitoring"}]}`,
+		},
+		{
+			name:      "integration versions",
+			query:     `query ($ns: String!) { integration(namespace: $ns, name: "nginx-monitoring") { displayName latestVersion versions { version } } }`,
+			variables: map[string]interface{}{"ns": "nginx"},
+			want:      `{"integration":{"displayName":"NGINX Monitoring","latestVersion":"2.0.0-beta.1","versions":[{"version":"1.2.0"},{"version":"1.2.5"},{"version":"1.3.0"},{"version":"2.0.0-beta.1"}]}}`,
+		},
+		{
+			name:  "integration version by constraint",
+			query: `{ integration(namespace: "nginx", name: "nginx-monitoring") { version(constraint: "^1.2") { version dashboards { name path data } images { name } logo { path } } } }`,
+			want:  `{"integration":{"version":{"dashboards":[{"data":{"title":"NGINX"},"name":"nginx.json","path":"/abc123/v1/nginx/nginx-monitoring/1.3.0/dashboards/nginx.json"}],"images":[],"logo":null,"version":"1.3.0"}}}`,
+		},
+		{
+			name:  "integration version prompts & resources",
+			query: `{ integration(namespace: "system", name: "host-monitoring") { version { readme prompts { type name required } resources } } }`,
+			want:  `{"integration":{"version":{"prompts":[{"name":"","required":false,"type":"section"},{"name":"employer","required":false,"type":"question"}],"readme":"# Host Monitoring","resources":[{"type":"CheckConfig"}]}}}`,
+		},
+		{
+			name:  "unknown version",
+			query: `{ integration(namespace: "system", name: "host-monitoring") { version(version: "1.0.0") { version } } }`,
+			want:  `{"integration":{"version":null}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := execute(t, tt.query, tt.variables); got != tt.want {
+				t.Errorf("Execute() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandler(t *testing.T) {
+	store := catalogquery.NewStore(t.TempDir())
+	handler, err := NewHandler(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		body       string
+		wantStatus int
+	}{
+		{name: "no release loaded", method: http.MethodGet, target: "/catalog-graphql?query=%7Brelease%7Bsha256%7D%7D", wantStatus: http.StatusServiceUnavailable},
+		{name: "empty query", method: http.MethodPost, target: "/catalog-graphql", body: `{}`, wantStatus: http.StatusBadRequest},
+		{name: "invalid body", method: http.MethodPost, target: "/catalog-graphql", body: `{`, wantStatus: http.StatusBadRequest},
+		{name: "invalid method", method: http.MethodPut, target: "/catalog-graphql", wantStatus: http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			handler.ServeHTTP(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("ServeHTTP() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if !strings.Contains(w.Body.String(), `"errors"`) {
+				t.Errorf("ServeHTTP() body = %v, want errors", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestExecute_limits(t *testing.T) {
+	// each fragment spreads the previous one twice, doubling the fields
+	fragments := "fragment f0 on Query { release { sha256 } }\n"
+	for i := 1; i <= 10; i++ {
+		fragments += fmt.Sprintf("fragment f%d on Query { ...f%d ...f%d }\n", i, i-1, i-1)
+	}
+
+	tests := []struct {
+		name    string
+		query   string
+		wantErr string
+	}{
+		{
+			name:    "too deep",
+			query:   strings.Repeat("{ namespaces ", maxQueryDepth+1) + strings.Repeat("}", maxQueryDepth+1),
+			wantErr: "query must not nest fields more than 10 levels deep",
+		},
+		{
+			name:    "too many fields",
+			query:   "{ ...f10 }\n" + fragments,
+			wantErr: "query must not select more than 1000 fields",
+		},
+		{
+			name:    "cyclic fragment",
+			query:   "{ ...f } fragment f on Query { ...f }",
+			wantErr: "fragment f must not spread itself",
+		},
+	}
+	schema, err := NewSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Execute(schema, catalogquery.FixtureRelease(), Request{Query: tt.query})
+			if len(result.Errors) == 0 || result.Errors[0].Message != tt.wantErr {
+				t.Errorf("Execute() errors = %v, want %s", result.Errors, tt.wantErr)
+			}
+		})
+	}
+}
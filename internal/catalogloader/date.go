@@ -0,0 +1,71 @@
+package catalogloader
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// SourceDater is implemented by loaders that can determine the date of the
+// catalog they load integrations from, which can be used in place of the
+// current time to produce reproducible releases.
+type SourceDater interface {
+	SourceDate() (time.Time, error)
+}
+
+// SourceDate returns the date of the most recent integration version tag, as
+// determined by the tagger date of annotated tags or the committer date of
+// the tagged commit. The zero time is returned if there are no tags.
+func (l GitLoader) SourceDate() (time.Time, error) {
+	latest := time.Time{}
+
+	integrations, err := l.LoadIntegrations()
+	if err != nil {
+		return latest, err
+	}
+	for _, integration := range integrations {
+		date, err := l.tagDate(plumbing.NewHash(integration.GitRef))
+		if err != nil {
+			return latest, fmt.Errorf("error determining date of git tag %s: %w", integration.GitTag, err)
+		}
+		if date.After(latest) {
+			latest = date
+		}
+	}
+	return latest, nil
+}
+
+func (l GitLoader) tagDate(hash plumbing.Hash) (time.Time, error) {
+	if tag, err := l.repo.TagObject(hash); err == nil {
+		return tag.Tagger.When, nil
+	}
+	commit, err := l.repo.CommitObject(hash)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return commit.Committer.When, nil
+}
+
+// SourceDate returns the committer date of the commit checked out within the
+// repository or the date of the most recent integration version tag,
+// whichever is more recent.
+func (l SnapshotLoader) SourceDate() (time.Time, error) {
+	latest, err := l.gitLoader.SourceDate()
+	if err != nil {
+		return latest, err
+	}
+
+	head, err := l.gitLoader.repo.Head()
+	if err != nil {
+		return latest, fmt.Errorf("error determining git HEAD: %w", err)
+	}
+	commit, err := l.gitLoader.repo.CommitObject(head.Hash())
+	if err != nil {
+		return latest, fmt.Errorf("error loading git HEAD commit: %w", err)
+	}
+	if commit.Committer.When.After(latest) {
+		latest = commit.Committer.When
+	}
+	return latest, nil
+}
@@ -0,0 +1,73 @@
+package catalogloader
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+func TestSourceDate(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	commit := func(message string, when time.Time) plumbing.Hash {
+		hash, err := worktree.Commit(message, &git.CommitOptions{
+			Author: &object.Signature{Name: "test", Email: "test@example.com", When: when},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return hash
+	}
+
+	// a lightweight tag dated by its commit, an annotated tag dated by its
+	// tagger & an untagged commit at HEAD
+	first := commit("release 1.0.0", time.Unix(1000, 0))
+	if _, err := repo.CreateTag("example_ns/example/1.0.0", first, nil); err != nil {
+		t.Fatal(err)
+	}
+	second := commit("release 1.1.0", time.Unix(2000, 0))
+	if _, err := repo.CreateTag("example_ns/example/1.1.0", second, &git.CreateTagOptions{
+		Tagger:  &object.Signature{Name: "test", Email: "test@example.com", When: time.Unix(3000, 0)},
+		Message: "release 1.1.0",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	commit("unreleased", time.Unix(4000, 0))
+
+	atFirst, err := NewGitLoaderAtRevision(repo, "integrations", first.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		loader SourceDater
+		want   int64
+	}{
+		{name: "git", loader: NewGitLoader(repo, "integrations"), want: 3000},
+		{name: "git at revision", loader: atFirst, want: 1000},
+		{name: "snapshot", loader: SnapshotLoader{gitLoader: NewGitLoader(repo, "integrations")}, want: 4000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.loader.SourceDate()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Unix() != tt.want {
+				t.Errorf("SourceDate() = %v, want %v", got.Unix(), tt.want)
+			}
+		})
+	}
+}
@@ -3,11 +3,13 @@ package catalogloader
 import (
 	"errors"
 	"fmt"
+	"path"
 	"regexp"
 	"strconv"
 
 	git "github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/rs/zerolog/log"
 	"github.com/sensu/catalog-api/internal/integrationloader"
 	"github.com/sensu/catalog-api/internal/types"
@@ -24,13 +26,74 @@ var (
 type GitLoader struct {
 	repo                *git.Repository
 	integrationsDirName string
+
+	// revision is the git revision that the metadata of namespaces is loaded
+	// from
+	revision string
+
+	// reachable holds the commits reachable from the revision the loader was
+	// created at; all tags are loaded when nil
+	reachable map[plumbing.Hash]bool
+
+	// released holds the commits reachable from the revision passed to
+	// Since; tags pointing to them are skipped
+	released map[plumbing.Hash]bool
 }
 
 func NewGitLoader(repo *git.Repository, integrationsDirName string) GitLoader {
 	return GitLoader{
 		repo:                repo,
 		integrationsDirName: integrationsDirName,
+		revision:            "HEAD",
+	}
+}
+
+// NewGitLoaderAtRevision returns a loader that only loads integration
+// versions whose tags point to a commit reachable from the given revision,
+// i.e. the integration versions that had been released as of the revision.
+func NewGitLoaderAtRevision(repo *git.Repository, integrationsDirName string, revision string) (GitLoader, error) {
+	loader := NewGitLoader(repo, integrationsDirName)
+	reachable, err := reachableCommits(repo, revision)
+	if err != nil {
+		return loader, err
 	}
+	loader.reachable = reachable
+	loader.revision = revision
+	return loader, nil
+}
+
+// Since returns a copy of the loader that skips integration versions whose
+// tags point to a commit reachable from the given revision, i.e. it only
+// loads the integration versions that were tagged since the revision.
+func (l GitLoader) Since(revision string) (GitLoader, error) {
+	released, err := reachableCommits(l.repo, revision)
+	if err != nil {
+		return l, err
+	}
+	l.released = released
+	return l, nil
+}
+
+// reachableCommits returns the set of commits reachable from the revision.
+func reachableCommits(repo *git.Repository, revision string) (map[plumbing.Hash]bool, error) {
+	hash, err := repo.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+		return nil, fmt.Errorf("error resolving git revision %s: %w", revision, err)
+	}
+	commit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return nil, fmt.Errorf("error loading commit for git revision %s: %w", revision, err)
+	}
+
+	reachable := map[plumbing.Hash]bool{}
+	err = object.NewCommitPreorderIter(commit, nil, nil).ForEach(func(c *object.Commit) error {
+		reachable[c.Hash] = true
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error walking history of git revision %s: %w", revision, err)
+	}
+	return reachable, nil
 }
 
 func (l GitLoader) NewIntegrationLoader(integration types.IntegrationVersion) integrationloader.Loader {
@@ -39,6 +102,13 @@ func (l GitLoader) NewIntegrationLoader(integration types.IntegrationVersion) in
 	return integrationloader.NewGitLoader(l.repo, tagName, integrationPath)
 }
 
+// NewNamespaceLoader returns a loader of the namespace directory as of the
+// revision the loader was created at, or HEAD.
+func (l GitLoader) NewNamespaceLoader(namespace string) integrationloader.Loader {
+	namespacePath := path.Join(l.integrationsDirName, namespace)
+	return integrationloader.NewGitLoader(l.repo, l.revision, namespacePath)
+}
+
 func (l GitLoader) LoadIntegrations() (types.Integrations, error) {
 	integrations := types.Integrations{}
 
@@ -60,6 +130,21 @@ func (l GitLoader) LoadIntegrations() (types.Integrations, error) {
 			return nil
 		}
 
+		if l.reachable != nil || l.released != nil {
+			hash, err := l.repo.ResolveRevision(plumbing.Revision(tagRef.Name().String()))
+			if err != nil {
+				return fmt.Errorf("error resolving git tag - tag: %s, err: %w", tagRef.Name().Short(), err)
+			}
+			if l.reachable != nil && !l.reachable[*hash] {
+				logger.Debug().Str("reason", "tag is not reachable from revision").Msg("Skipping integration version")
+				return nil
+			}
+			if l.released[*hash] {
+				logger.Debug().Str("reason", "tag was released before revision").Msg("Skipping integration version")
+				return nil
+			}
+		}
+
 		integrations = append(integrations, iv)
 
 		logger.Info().
@@ -72,7 +157,8 @@ func (l GitLoader) LoadIntegrations() (types.Integrations, error) {
 		return nil
 	})
 
-	return integrations, nil
+	// the order in which tags are iterated depends on the repository storage
+	return integrations.Sorted(), nil
 }
 
 func getIntegrationVersionFromGitTag(tagRef *plumbing.Reference) (types.IntegrationVersion, error) {
@@ -1,12 +1,19 @@
 package catalogloader
 
 import (
+	"errors"
+	"io/fs"
 	"reflect"
 	"testing"
+	"time"
 
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-billy/v5/util"
 	git "github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/sensu/catalog-api/internal/integrationloader"
 	"github.com/sensu/catalog-api/internal/types"
 )
 
@@ -194,3 +201,187 @@ func Test_getIntegrationVersionFromGitTag(t *testing.T) {
 		})
 	}
 }
+
+func TestNewGitLoaderAtRevision(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// commit & tag a version of the integration for each of the versions
+	commits := []plumbing.Hash{}
+	for _, version := range []string{"1.0.0", "1.1.0"} {
+		hash, err := worktree.Commit("release "+version, &git.CommitOptions{
+			Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Unix(0, 0)},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := repo.CreateTag("example_ns/example/"+version, hash, nil); err != nil {
+			t.Fatal(err)
+		}
+		commits = append(commits, hash)
+	}
+
+	tests := []struct {
+		name     string
+		revision string
+		want     []string
+		wantErr  bool
+	}{
+		{name: "first commit", revision: commits[0].String(), want: []string{"1.0.0"}},
+		{name: "head", revision: "HEAD", want: []string{"1.0.0", "1.1.0"}},
+		{name: "tag", revision: "example_ns/example/1.0.0", want: []string{"1.0.0"}},
+		{name: "unknown revision", revision: "foo", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := NewGitLoaderAtRevision(repo, "integrations", tt.revision)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewGitLoaderAtRevision() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			integrations, err := l.LoadIntegrations()
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := []string{}
+			for _, integration := range integrations {
+				got = append(got, integration.SemVer())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GitLoader.LoadIntegrations() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGitLoader_Since(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// commit & tag a version of the integration for each of the versions
+	commits := []plumbing.Hash{}
+	for _, version := range []string{"1.0.0", "1.1.0", "1.2.0"} {
+		hash, err := worktree.Commit("release "+version, &git.CommitOptions{
+			Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Unix(0, 0)},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := repo.CreateTag("example_ns/example/"+version, hash, nil); err != nil {
+			t.Fatal(err)
+		}
+		commits = append(commits, hash)
+	}
+
+	tests := []struct {
+		name     string
+		revision string
+		want     []string
+		wantErr  bool
+	}{
+		{name: "first commit", revision: commits[0].String(), want: []string{"1.1.0", "1.2.0"}},
+		{name: "tag", revision: "example_ns/example/1.1.0", want: []string{"1.2.0"}},
+		{name: "head", revision: "HEAD", want: []string{}},
+		{name: "unknown revision", revision: "foo", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := NewGitLoader(repo, "integrations").Since(tt.revision)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GitLoader.Since() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			integrations, err := l.LoadIntegrations()
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := []string{}
+			for _, integration := range integrations {
+				got = append(got, integration.SemVer())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GitLoader.LoadIntegrations() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGitLoader_NewNamespaceLoader(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// commit a revision of the namespace metadata for each display name
+	commits := []plumbing.Hash{}
+	for _, displayName := range []string{"Example", "Example Namespace"} {
+		namespaceYAML := "type: Namespace\napi_version: catalog/v1\nmetadata:\n  name: example_ns\nspec:\n  display_name: " + displayName + "\n"
+		if err := util.WriteFile(worktree.Filesystem, "integrations/example_ns/namespace.yaml", []byte(namespaceYAML), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := worktree.Add("integrations/example_ns/namespace.yaml"); err != nil {
+			t.Fatal(err)
+		}
+		hash, err := worktree.Commit("update namespace", &git.CommitOptions{
+			Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Unix(0, 0)},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		commits = append(commits, hash)
+	}
+
+	atFirstCommit, err := NewGitLoaderAtRevision(repo, "integrations", commits[0].String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		loader    GitLoader
+		namespace string
+		want      string
+		wantErr   bool
+	}{
+		{name: "head", loader: NewGitLoader(repo, "integrations"), namespace: "example_ns", want: "Example Namespace"},
+		{name: "revision", loader: atFirstCommit, namespace: "example_ns", want: "Example"},
+		{name: "namespace without metadata", loader: NewGitLoader(repo, "integrations"), namespace: "foo", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			namespace, err := integrationloader.LoadNamespace(tt.loader.NewNamespaceLoader(tt.namespace))
+			if tt.wantErr {
+				if !errors.Is(err, fs.ErrNotExist) {
+					t.Errorf("LoadNamespace() error = %v, want fs.ErrNotExist", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if namespace.DisplayName != tt.want {
+				t.Errorf("LoadNamespace() display name = %v, want %v", namespace.DisplayName, tt.want)
+			}
+		})
+	}
+}
@@ -8,4 +8,8 @@ import (
 type Loader interface {
 	LoadIntegrations() (types.Integrations, error)
 	NewIntegrationLoader(integration types.IntegrationVersion) integrationloader.Loader
+
+	// NewNamespaceLoader returns a loader of the files within the directory
+	// of a namespace, e.g. its namespace.yaml.
+	NewNamespaceLoader(namespace string) integrationloader.Loader
 }
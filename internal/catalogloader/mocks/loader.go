@@ -52,3 +52,19 @@ func (_m *Loader) NewIntegrationLoader(integration types.IntegrationVersion) int
 
 	return r0
 }
+
+// NewNamespaceLoader provides a mock function with given fields: namespace
+func (_m *Loader) NewNamespaceLoader(namespace string) integrationloader.Loader {
+	ret := _m.Called(namespace)
+
+	var r0 integrationloader.Loader
+	if rf, ok := ret.Get(0).(func(string) integrationloader.Loader); ok {
+		r0 = rf(namespace)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(integrationloader.Loader)
+		}
+	}
+
+	return r0
+}
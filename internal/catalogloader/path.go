@@ -33,6 +33,10 @@ func (l PathLoader) NewIntegrationLoader(integration types.IntegrationVersion) i
 	return integrationloader.NewPathLoader(integrationPath)
 }
 
+func (l PathLoader) NewNamespaceLoader(namespace string) integrationloader.Loader {
+	return integrationloader.NewPathLoader(path.Join(l.IntegrationsAbsPath(), namespace))
+}
+
 func (l PathLoader) LoadIntegrations() (types.Integrations, error) {
 	integrations := types.Integrations{}
 
@@ -18,6 +18,18 @@ func NewSnapshotLoader(repo *git.Repository, repoPath string, integrationsDirNam
 	}
 }
 
+// Since returns a copy of the loader that only loads the integration versions
+// that were tagged since the given revision, along with the integrations
+// within the catalog directory.
+func (l SnapshotLoader) Since(revision string) (SnapshotLoader, error) {
+	gitLoader, err := l.gitLoader.Since(revision)
+	if err != nil {
+		return l, err
+	}
+	l.gitLoader = gitLoader
+	return l, nil
+}
+
 func (l SnapshotLoader) NewIntegrationLoader(integration types.IntegrationVersion) integrationloader.Loader {
 	switch integration.Source {
 	case "git":
@@ -28,6 +40,12 @@ func (l SnapshotLoader) NewIntegrationLoader(integration types.IntegrationVersio
 	panic("invalid source field in integration")
 }
 
+// NewNamespaceLoader returns a loader of the namespace directory within the
+// catalog directory, which holds the current metadata of the namespace.
+func (l SnapshotLoader) NewNamespaceLoader(namespace string) integrationloader.Loader {
+	return l.pathLoader.NewNamespaceLoader(namespace)
+}
+
 func (l SnapshotLoader) LoadIntegrations() (types.Integrations, error) {
 	integrations := types.Integrations{}
 
@@ -0,0 +1,63 @@
+// Package catalogmanagertest generates releases from fixture integrations for
+// the tests of packages that consume generated releases.
+package catalogmanagertest
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	catalogv1 "github.com/sensu/catalog-api/internal/api/catalog/v1"
+	mockcatalogloader "github.com/sensu/catalog-api/internal/catalogloader/mocks"
+	"github.com/sensu/catalog-api/internal/catalogmanager"
+	"github.com/sensu/catalog-api/internal/integrationloader"
+	mockintegrationloader "github.com/sensu/catalog-api/internal/integrationloader/mocks"
+	"github.com/sensu/catalog-api/internal/types"
+	"github.com/stretchr/testify/mock"
+)
+
+// GenerateRelease generates a release containing the given versions of
+// fixture integrations and returns the release directory, which contains the
+// version endpoint. The staging & release directories of config are replaced
+// by temporary directories; other options, e.g. the signing key, are kept.
+func GenerateRelease(tb testing.TB, config catalogmanager.Config, integrations types.Integrations) string {
+	tb.Helper()
+
+	config.StagingDir = filepath.Join(tb.TempDir(), "staging")
+	config.ReleaseDir = tb.TempDir()
+	m, err := catalogmanager.New(config, NewFixtureLoader(integrations))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if err := os.Mkdir(config.StagingDir, 0700); err != nil {
+		tb.Fatal(err)
+	}
+	if err := m.ProcessCatalog(); err != nil {
+		tb.Fatal(err)
+	}
+	return config.ReleaseDir
+}
+
+// NewFixtureLoader returns a catalog loader of the given versions of fixture
+// integrations, each with an image & a dashboard, within namespaces without
+// metadata.
+func NewFixtureLoader(integrations types.Integrations) *mockcatalogloader.Loader {
+	cl := mockcatalogloader.Loader{}
+	cl.On("LoadIntegrations").Return(integrations, nil)
+	for _, integration := range integrations {
+		il := mockintegrationloader.Loader{}
+		il.On("LoadConfig").Return(catalogv1.FixtureIntegration(integration.Namespace, integration.Name), nil)
+		il.On("LoadResources").Return(`[{"api_version": "core/v2"}]`, nil)
+		il.On("LoadLogo").Return("png data", nil)
+		il.On("LoadReadme").Return("readme markdown", nil)
+		il.On("LoadChangelog").Return(catalogv1.FixtureChangelogMarkdown(integration.SemVer()), nil)
+		il.On("LoadImages").Return(integrationloader.Images{"image.png": "png data"}, nil)
+		il.On("LoadDashboards").Return(integrationloader.Dashboards{"dashboard.json": "{}"}, nil)
+		cl.On("NewIntegrationLoader", integration).Return(&il)
+	}
+	nl := mockintegrationloader.Loader{}
+	nl.On("GetFileContentsAsBytes", integrationloader.NamespaceConfigName).Return(nil, fs.ErrNotExist)
+	cl.On("NewNamespaceLoader", mock.Anything).Return(&nl)
+	return &cl
+}
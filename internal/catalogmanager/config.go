@@ -1,9 +1,14 @@
 package catalogmanager
 
 import (
+	"crypto/ed25519"
 	"errors"
 	"fmt"
+	"time"
 
+	catalogv1 "github.com/sensu/catalog-api/internal/api/catalog/v1"
+	catalogapiv1 "github.com/sensu/catalog-api/internal/api/catalogapi/v1"
+	"github.com/sensu/catalog-api/internal/output"
 	"github.com/sensu/catalog-api/internal/util"
 )
 
@@ -11,21 +16,80 @@ type Config struct {
 	StagingDir          string
 	ReleaseDir          string
 	IntegrationsDirName string
+
+	// Taxonomy holds the providers, classes, platforms & tags that
+	// integrations are validated against.
+	Taxonomy catalogv1.Taxonomy
+
+	// SigningKey, if set, is used to sign the release manifest & the version
+	// endpoint.
+	SigningKey ed25519.PrivateKey
+
+	// Timestamp, if set, is used as the last_updated timestamp of the
+	// release instead of the current time, e.g. to produce reproducible
+	// releases.
+	Timestamp time.Time
+
+	// Compress writes gzip & brotli compressed siblings of the text endpoints
+	// of the release, which are not covered by the release checksum.
+	Compress bool
+
+	// Observer, if set, is notified of the duration of each phase of
+	// ProcessCatalog.
+	Observer Observer
+
+	// KeepGoing excludes integration versions that fail to build from the
+	// release instead of failing the whole release. The failures are
+	// recorded in the errors endpoint of the release.
+	KeepGoing bool
+
+	// Output, if set, is the output that the release is written to instead of
+	// ReleaseDir. The release is then generated in memory rather than within
+	// StagingDir, so that it is never written to the local filesystem.
+	Output output.Output
+
+	// MaxExcluded is the number of integrations whose versions may be
+	// excluded in keep-going mode before ProcessCatalog fails regardless. Zero
+	// means no limit.
+	MaxExcluded int
+}
+
+func (c Config) timestamp() time.Time {
+	if c.Timestamp.IsZero() {
+		return time.Now()
+	}
+	return c.Timestamp
 }
 
 func (c Config) validate() error {
-	if c.StagingDir == "" {
+	if c.Output == nil && c.StagingDir == "" {
 		return errors.New("staging dir must not be empty")
 	}
-	if c.ReleaseDir == "" {
+	if c.Output == nil && c.ReleaseDir == "" {
 		return errors.New("release dir must not be empty")
 	}
+	if c.MaxExcluded < 0 {
+		return errors.New("max excluded must not be negative")
+	}
 	return nil
 }
 
+// outputs returns the output that the release is generated within & the
+// output that it is written to once its checksum is known.
+func (c Config) outputs() (output.Staging, output.Output) {
+	if c.Output != nil {
+		return output.NewMemory(), c.Output
+	}
+	return output.NewDir(c.StagingDir), output.NewDir(c.ReleaseDir)
+}
+
 func (c Config) StagingChecksum() (string, error) {
+	return stagingChecksum(output.NewDir(c.StagingDir))
+}
+
+func stagingChecksum(staging output.Staging) (string, error) {
 	// calculate the sha256 checksum of the generated api
-	checksum, err := util.CalculateDirChecksum(c.StagingDir, "staging")
+	checksum, err := util.CalculateFSChecksum(staging, ".", "staging", catalogapiv1.IsCompressedSibling)
 	if err != nil {
 		return "", fmt.Errorf("error calculating checksum of staging dir: %w", err)
 	}
@@ -1,5 +1,121 @@
 package catalogmanager
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sensu/catalog-api/internal/diagnostics"
+	"github.com/sensu/catalog-api/internal/position"
+)
 
 var ErrUnmatchedGitTag = errors.New("unmatched git tag")
+
+// Rules of the validation failures reported by ValidateCatalog.
+const (
+	RuleConfigLoad       = "config-load"
+	RuleConfigInvalid    = "config-invalid"
+	RuleResourcesLoad    = "resources-load"
+	RuleLogoLoad         = "logo-load"
+	RuleReadmeLoad       = "readme-load"
+	RuleChangelogLoad    = "changelog-load"
+	RuleChangelogInvalid = "changelog-invalid"
+	RuleImagesLoad       = "images-load"
+	RuleNamespaceInvalid = "namespace-invalid"
+
+	// RuleBuildFailed is the rule of integration versions that ProcessCatalog
+	// excluded from a release in keep-going mode.
+	RuleBuildFailed = "build-failed"
+)
+
+// ValidationError is a validation failure of an integration version, or of a
+// namespace if Integration & Version are empty.
+type ValidationError struct {
+	Namespace   string
+	Integration string
+	Version     string
+
+	// Rule identifies the check that failed, e.g. RuleLogoLoad, and File is
+	// the path of the file that failed it, relative to the catalog
+	// repository.
+	Rule string
+	File string
+
+	// Message describes what failed, e.g. "Failed to load logo".
+	Message string
+	Err     error
+}
+
+// Diagnostic returns the validation failure as a diagnostic, positioned at
+// the offending line of the file if it is known.
+func (e ValidationError) Diagnostic() diagnostics.Diagnostic {
+	d := diagnostics.Diagnostic{
+		Severity:  diagnostics.SeverityError,
+		RuleID:    e.Rule,
+		Namespace: e.Namespace,
+		Name:      e.Integration,
+		Version:   e.Version,
+		File:      e.File,
+		Message:   e.Message + ": " + e.Err.Error(),
+	}
+	if posErr, ok := position.Find(e.Err); ok && posErr.IsValid() {
+		d.Line = posErr.Line
+		d.Column = posErr.Column
+		if posErr.File != "" {
+			// the file is reported by the diagnostic itself
+			d.Message = e.Message + ": " + posErr.Err.Error()
+		}
+	}
+	return d
+}
+
+func (e ValidationError) Error() string {
+	if e.Integration == "" {
+		return fmt.Sprintf("%s: %s: %s", e.Namespace, e.Message, e.Err)
+	}
+	return fmt.Sprintf("%s/%s %s: %s: %s", e.Namespace, e.Integration, e.Version, e.Message, e.Err)
+}
+
+func (e ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationErrors holds every validation failure of a catalog.
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	return "one or more integrations failed validation"
+}
+
+// Diagnostics returns the validation failures as diagnostics.
+func (e ValidationErrors) Diagnostics() []diagnostics.Diagnostic {
+	diags := []diagnostics.Diagnostic{}
+	for _, failure := range e {
+		diags = append(diags, failure.Diagnostic())
+	}
+	return diags
+}
+
+// Integrations returns the number of distinct integrations that the failures
+// belong to.
+func (e ValidationErrors) Integrations() int {
+	integrations := map[string]bool{}
+	for _, failure := range e {
+		integrations[failure.Namespace+"/"+failure.Integration] = true
+	}
+	return len(integrations)
+}
+
+// PartialReleaseError is returned by ProcessCatalog in keep-going mode when
+// the release was generated without one or more integration versions that
+// failed to build.
+type PartialReleaseError struct {
+	Excluded ValidationErrors
+}
+
+func (e PartialReleaseError) Error() string {
+	return fmt.Sprintf("%d integration version(s) were excluded from the release", len(e.Excluded))
+}
+
+func (e PartialReleaseError) Unwrap() error {
+	return e.Excluded
+}
@@ -0,0 +1,59 @@
+package catalogmanager
+
+import (
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/sensu/catalog-api/internal/output"
+	"github.com/sensu/catalog-api/internal/position"
+	"github.com/sensu/catalog-api/internal/types"
+)
+
+// excludeFailing processes every integration version & returns the versions
+// that were processed successfully, along with the failures of those that
+// were not. Each version is processed in memory & only written to the staging
+// output if it succeeds, so that the versions are processed once. Excluding a
+// version that would have been the latest version of an integration makes the
+// previous valid version the latest one.
+func (m CatalogManager) excludeFailing(integrations types.Integrations) (types.Integrations, ValidationErrors, error) {
+	valid := types.Integrations{}
+	failures := ValidationErrors{}
+	for _, version := range integrations {
+		scratch := m
+		scratch.staging = output.NewMemory()
+		if err := scratch.ProcessIntegrationVersion(version); err != nil {
+			log.Warn().
+				Err(err).
+				Str("namespace", version.Namespace).
+				Str("integration", version.Name).
+				Str("version", version.SemVer()).
+				Msg("Excluding integration version from release")
+			failures = append(failures, m.buildFailure(version, err))
+			continue
+		}
+		if err := output.WriteTree(m.staging, scratch.staging, ""); err != nil {
+			return nil, nil, fmt.Errorf("error writing integration version to staging dir: %w", err)
+		}
+		valid = append(valid, version)
+	}
+	return valid, failures, nil
+}
+
+// buildFailure describes an integration version that failed to build,
+// attributed to the file that caused the failure if it is known.
+func (m CatalogManager) buildFailure(version types.IntegrationVersion, err error) ValidationError {
+	file := version.Path(m.config.IntegrationsDirName)
+	if posErr, ok := position.Find(err); ok && posErr.File != "" {
+		file = posErr.File
+	}
+	return ValidationError{
+		Namespace:   version.Namespace,
+		Integration: version.Name,
+		Version:     version.SemVer(),
+		Rule:        RuleBuildFailed,
+		File:        file,
+		Message:     "Failed to build integration version",
+		Err:         err,
+	}
+}
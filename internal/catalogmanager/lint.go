@@ -0,0 +1,59 @@
+package catalogmanager
+
+import (
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/sensu/catalog-api/internal/diagnostics"
+	"github.com/sensu/catalog-api/internal/lint"
+	"github.com/sensu/catalog-api/internal/types"
+)
+
+// LintIntegrations lints the given integration versions & returns the
+// findings as diagnostics. Integration versions whose config fails to load
+// are skipped, as they are reported by ValidateCatalog. Findings on tagged
+// integration versions are downgraded to warnings, as the tags can no longer
+// be changed.
+func (m CatalogManager) LintIntegrations(linter lint.Linter, integrations types.Integrations) ([]diagnostics.Diagnostic, error) {
+	diags := []diagnostics.Diagnostic{}
+	for _, integration := range integrations.Sorted() {
+		integrationLoader := m.loader.NewIntegrationLoader(integration)
+
+		config, err := integrationLoader.LoadConfig()
+		if err != nil {
+			log.Debug().
+				Err(err).
+				Str("namespace", integration.Namespace).
+				Str("integration", integration.Name).
+				Str("version", integration.SemVer()).
+				Msg("Skipping lint of integration version")
+			continue
+		}
+
+		// missing readmes are reported by ValidateCatalog
+		readme, _ := integrationLoader.LoadReadme()
+
+		files, err := integrationLoader.ListFiles()
+		if err != nil {
+			return nil, fmt.Errorf("error listing files of integration %s: %w", integration, err)
+		}
+
+		findings := linter.Lint(lint.Subject{
+			Namespace: integration.Namespace,
+			Name:      integration.Name,
+			Version:   integration.SemVer(),
+			Dir:       integration.Path(m.config.IntegrationsDirName),
+			Config:    config,
+			Readme:    readme,
+			Files:     files,
+		})
+		if integration.GitTag != "" {
+			for i := range findings {
+				findings[i].Severity = diagnostics.SeverityWarning
+			}
+		}
+		diags = append(diags, findings...)
+	}
+	return diags, nil
+}
@@ -1,22 +1,42 @@
 package catalogmanager
 
 import (
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io/fs"
-	"os/exec"
+	"os"
 	"path"
+	"path/filepath"
+	"time"
 
 	"github.com/rs/zerolog/log"
 
+	catalogv1 "github.com/sensu/catalog-api/internal/api/catalog/v1"
 	catalogapiv1 "github.com/sensu/catalog-api/internal/api/catalogapi/v1"
 	"github.com/sensu/catalog-api/internal/catalogloader"
+	"github.com/sensu/catalog-api/internal/catalogsign"
 	"github.com/sensu/catalog-api/internal/endpoints"
+	"github.com/sensu/catalog-api/internal/integrationloader"
+	"github.com/sensu/catalog-api/internal/output"
+	"github.com/sensu/catalog-api/internal/position"
 	"github.com/sensu/catalog-api/internal/types"
+	"github.com/sensu/catalog-api/internal/util"
 )
 
 type CatalogManager struct {
 	config Config
 	loader catalogloader.Loader
+
+	// staging is the output that the release is generated within, release
+	// the output it is written to once its checksum is known
+	staging output.Staging
+	release output.Output
+
+	// versionsProcessed is set once the endpoints of every integration
+	// version have been generated, e.g. by excludeFailing
+	versionsProcessed bool
 }
 
 func (m CatalogManager) GetConfig() Config {
@@ -32,34 +52,72 @@ func New(config Config, loader catalogloader.Loader) (CatalogManager, error) {
 	if err := config.validate(); err != nil {
 		return m, fmt.Errorf("catalog manager config validation failed: %w", err)
 	}
+	m.staging, m.release = config.outputs()
 
 	return m, nil
 }
 
 func (m CatalogManager) ProcessCatalog() error {
+	start := time.Now()
 	integrations, err := m.loader.LoadIntegrations()
 	if err != nil {
 		return fmt.Errorf("error loading integrations: %w", err)
 	}
+	start = m.observePhase(PhaseLoad, start)
+
+	// the order in which integrations are loaded is not guaranteed; sort them
+	// so that identical inputs always produce identical endpoints
+	integrations = integrations.Sorted()
+
+	excluded := ValidationErrors{}
+	if m.config.KeepGoing {
+		integrations, excluded, err = m.excludeFailing(integrations)
+		if err != nil {
+			return err
+		}
+		if n := excluded.Integrations(); m.config.MaxExcluded > 0 && n > m.config.MaxExcluded {
+			return fmt.Errorf("%d integrations failed to build, more than the maximum of %d: %w", n, m.config.MaxExcluded, excluded)
+		}
+
+		// the versions that were not excluded have been generated already
+		m.versionsProcessed = true
+	}
 
 	integrationsByNamespace := integrations.ByNamespace()
 	latestNsIntegrations := map[string][]catalogapiv1.IntegrationVersion{}
-	for namespace, nsIntegrations := range integrationsByNamespace {
+	namespaces := []catalogapiv1.IntegrationNamespace{}
+	searchIndex := newSearchIndexBuilder()
+	for _, namespace := range integrationsByNamespace.Namespaces() {
+		nsIntegrations := integrationsByNamespace[namespace]
 		if err := m.ProcessNamespace(namespace, nsIntegrations); err != nil {
 			return err
 		}
 
-		for _, versions := range nsIntegrations.ByName() {
-			latest := versions.LatestVersion()
+		ns, err := m.ProcessNamespaceMetadata(namespace, nsIntegrations)
+		if err != nil {
+			return err
+		}
+		namespaces = append(namespaces, ns)
+
+		byName := nsIntegrations.ByName()
+		for _, name := range byName.Names() {
+			latest := byName[name].LatestVersion()
 			integrationLoader := m.loader.NewIntegrationLoader(latest)
 
+			configFile := m.integrationFile(latest, integrationloader.ConfigName)
 			config, err := integrationLoader.LoadConfig()
 			if err != nil {
-				return err
+				return position.InFile(configFile, err)
 			}
-			if err := config.Validate(); err != nil {
+			if err := config.ValidateWith(m.config.Taxonomy); err != nil {
+				return position.InFile(configFile, err)
+			}
+
+			readme, err := integrationLoader.LoadReadme()
+			if err != nil {
 				return err
 			}
+			addSearchDocument(searchIndex, config, readme)
 
 			// Set prompts & resource_patches fields to empty strings to prevent
 			// them from being shown in the catalog endpoint.
@@ -75,69 +133,139 @@ func (m CatalogManager) ProcessCatalog() error {
 		}
 	}
 
-	if err := endpoints.GenerateCatalogEndpoint(m.config.StagingDir, latestNsIntegrations); err != nil {
+	start = m.observePhase(PhaseIntegrations, start)
+
+	if err := endpoints.GenerateCatalogEndpoint(m.staging, latestNsIntegrations); err != nil {
 		return fmt.Errorf("error generating catalog endpoint: %w", err)
 	}
 
-	checksum, err := m.config.StagingChecksum()
+	if err := endpoints.GenerateNamespacesEndpoint(m.staging, namespaces); err != nil {
+		return fmt.Errorf("error generating namespaces endpoint: %w", err)
+	}
+
+	if err := endpoints.GenerateFacetEndpoints(m.staging, latestNsIntegrations); err != nil {
+		return fmt.Errorf("error generating facet endpoints: %w", err)
+	}
+
+	if err := endpoints.GenerateSearchIndexEndpoint(m.staging, searchIndex.Build()); err != nil {
+		return fmt.Errorf("error generating search index endpoint: %w", err)
+	}
+
+	if m.config.KeepGoing {
+		releaseErrors := catalogapiv1.ReleaseErrors{Errors: excluded.Diagnostics()}
+		if err := endpoints.GenerateErrorsEndpoint(m.staging, releaseErrors); err != nil {
+			return fmt.Errorf("error generating errors endpoint: %w", err)
+		}
+	}
+	start = m.observePhase(PhaseCatalog, start)
+
+	if err := endpoints.GenerateManifestEndpoint(m.staging); err != nil {
+		return fmt.Errorf("error generating manifest endpoint: %w", err)
+	}
+
+	// the manifest signature is part of the release & is therefore covered by
+	// the release checksum
+	publicKeyID := ""
+	if m.config.SigningKey != nil {
+		publicKeyID = catalogsign.KeyID(m.config.SigningKey.Public().(ed25519.PublicKey))
+		if err := endpoints.SignEndpoint(m.staging, catalogapiv1.ManifestFileName, m.config.SigningKey); err != nil {
+			return fmt.Errorf("error signing manifest endpoint: %w", err)
+		}
+	}
+
+	start = m.observePhase(PhaseManifest, start)
+
+	checksum, err := stagingChecksum(m.staging)
 	if err != nil {
 		return err
 	}
+	start = m.observePhase(PhaseChecksum, start)
 
-	// copy the staging dir to the release dir
-	dstPath := path.Join(m.config.ReleaseDir, checksum)
-	cmd := exec.Command("cp", "-R", m.config.StagingDir, dstPath)
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("error copying staging files to release dir: %w", err)
+	if m.config.Compress {
+		if err := endpoints.GenerateCompressedEndpoints(m.staging); err != nil {
+			return fmt.Errorf("error generating compressed endpoints: %w", err)
+		}
+		start = m.observePhase(PhaseCompress, start)
 	}
 
-	if err := endpoints.GenerateVersionEndpoint(m.config.ReleaseDir, checksum); err != nil {
+	if err := m.writeRelease(checksum); err != nil {
+		return err
+	}
+	start = m.observePhase(PhaseCopy, start)
+
+	// the version endpoint is written after the release it points to so that
+	// it never points at an incomplete release
+	version := catalogapiv1.ReleaseVersion{
+		ReleaseSHA256: checksum,
+		LastUpdated:   m.config.timestamp().Unix(),
+		PublicKeyID:   publicKeyID,
+	}
+	root := output.NewMemory()
+	if err := endpoints.GenerateVersionEndpoint(root, version); err != nil {
 		return fmt.Errorf("error generating version endpoint: %w", err)
 	}
+	if m.config.SigningKey != nil {
+		versionPath := catalogapiv1.NewVersionEndpoint("", version).GetOutputPath()
+		if err := endpoints.SignEndpoint(root, versionPath, m.config.SigningKey); err != nil {
+			return fmt.Errorf("error signing version endpoint: %w", err)
+		}
+	}
+	if err := output.WriteTree(m.release, root, ""); err != nil {
+		return fmt.Errorf("error writing version endpoint: %w", err)
+	}
+	m.observePhase(PhaseVersion, start)
 
+	if len(excluded) > 0 {
+		return PartialReleaseError{Excluded: excluded}
+	}
 	return nil
 }
 
+// integrationFile returns the path of a file of an integration version,
+// relative to the catalog repository.
+func (m CatalogManager) integrationFile(version types.IntegrationVersion, name string) string {
+	return path.Join(version.Path(m.config.IntegrationsDirName), name)
+}
+
 func (m CatalogManager) ProcessNamespace(namespace string, integrations types.Integrations) error {
 	for integration, versions := range integrations.ByName() {
 		if err := m.ProcessIntegrationVersions(namespace, integration, versions); err != nil {
 			return err
 		}
-		for _, version := range versions {
-			if err := m.ProcessIntegrationVersion(version); err != nil {
-				return err
-			}
-		}
 	}
 	return nil
 }
 
 func (m CatalogManager) ProcessIntegrationVersions(namespace string, integrationName string, integrations types.Integrations) error {
-	for _, integration := range integrations {
-		if err := m.ProcessIntegrationVersion(integration); err != nil {
-			log.Err(err).
-				Str("namespace", namespace).
-				Str("integration", integrationName).
-				Str("version", integration.SemVer()).
-				Msg("Failed to process integration version")
-			return fmt.Errorf("error processing integration version: %w", err)
+	// the versions have already been generated in keep-going mode
+	if !m.versionsProcessed {
+		for _, integration := range integrations {
+			if err := m.ProcessIntegrationVersion(integration); err != nil {
+				log.Err(err).
+					Str("namespace", namespace).
+					Str("integration", integrationName).
+					Str("version", integration.SemVer()).
+					Msg("Failed to process integration version")
+				return fmt.Errorf("error processing integration version: %w", err)
+			}
 		}
 	}
 
-	if err := endpoints.GenerateIntegrationVersionsEndpoint(m.config.StagingDir, namespace, integrationName, integrations); err != nil {
+	if err := endpoints.GenerateIntegrationVersionsEndpoint(m.staging, namespace, integrationName, integrations); err != nil {
 		return fmt.Errorf("error generating integration versions endpoint: %w", err)
 	}
 
 	latestVersion := integrations.LatestVersion()
 	integrationLoader := m.loader.NewIntegrationLoader(latestVersion)
+	configFile := m.integrationFile(latestVersion, integrationloader.ConfigName)
 	integrationConfig, err := integrationLoader.LoadConfig()
 	if err != nil {
-		return err
+		return position.InFile(configFile, err)
 	}
-	if err := integrationConfig.Validate(); err != nil {
-		return err
+	if err := integrationConfig.ValidateWith(m.config.Taxonomy); err != nil {
+		return position.InFile(configFile, err)
 	}
-	if err := endpoints.GenerateIntegrationEndpoint(m.config.StagingDir, integrationConfig, integrations); err != nil {
+	if err := endpoints.GenerateIntegrationEndpoint(m.staging, integrationConfig, integrations); err != nil {
 		return fmt.Errorf("error generating integration endpoint: %w", err)
 	}
 
@@ -147,17 +275,18 @@ func (m CatalogManager) ProcessIntegrationVersions(namespace string, integration
 func (m CatalogManager) ProcessIntegrationVersion(version types.IntegrationVersion) error {
 	integrationLoader := m.loader.NewIntegrationLoader(version)
 
+	configFile := m.integrationFile(version, integrationloader.ConfigName)
 	config, err := integrationLoader.LoadConfig()
 	if err != nil {
-		return err
+		return position.InFile(configFile, err)
 	}
-	if err := config.Validate(); err != nil {
-		return fmt.Errorf("integration config: %w", err)
+	if err := config.ValidateWith(m.config.Taxonomy); err != nil {
+		return fmt.Errorf("integration config: %w", position.InFile(configFile, err))
 	}
 
 	resourcesJSON, err := integrationLoader.LoadResources()
 	if err != nil {
-		return err
+		return position.InFile(m.integrationFile(version, integrationloader.ResourcesName), err)
 	}
 
 	logo, err := integrationLoader.LoadLogo()
@@ -177,24 +306,31 @@ func (m CatalogManager) ProcessIntegrationVersion(version types.IntegrationVersi
 	if err != nil {
 		return err
 	}
-
-	if err := endpoints.GenerateIntegrationVersionEndpoint(m.config.StagingDir, config, version); err != nil {
-		return fmt.Errorf("error generating integration version endpoint: %w", err)
+	structuredChangelog, err := catalogv1.ChangelogFromMarkdown([]byte(changelog))
+	if err != nil {
+		return fmt.Errorf("error parsing changelog: %w", err)
 	}
-	if err := endpoints.GenerateIntegrationVersionResourcesEndpoint(m.config.StagingDir, config, version, resourcesJSON); err != nil {
+	if err := validateChangelog(structuredChangelog, version); err != nil {
+		return err
+	}
+
+	if err := endpoints.GenerateIntegrationVersionResourcesEndpoint(m.staging, config, version, resourcesJSON); err != nil {
 		return fmt.Errorf("error generating integration version resources endpoint: %w", err)
 	}
 	if logo != "" {
-		if err := endpoints.GenerateIntegrationVersionLogoEndpoint(m.config.StagingDir, config, version, logo); err != nil {
+		if err := endpoints.GenerateIntegrationVersionLogoEndpoint(m.staging, config, version, logo); err != nil {
 			return fmt.Errorf("error generating integration version logo endpoint: %w", err)
 		}
 	}
-	if err := endpoints.GenerateIntegrationVersionReadmeEndpoint(m.config.StagingDir, config, version, readme); err != nil {
+	if err := endpoints.GenerateIntegrationVersionReadmeEndpoint(m.staging, config, version, readme); err != nil {
 		return fmt.Errorf("error generating integration version readme endpoint: %w", err)
 	}
-	if err := endpoints.GenerateIntegrationVersionChangelogEndpoint(m.config.StagingDir, config, version, changelog); err != nil {
+	if err := endpoints.GenerateIntegrationVersionChangelogEndpoint(m.staging, config, version, changelog); err != nil {
 		return fmt.Errorf("error generating integration version changelog endpoint: %w", err)
 	}
+	if err := endpoints.GenerateIntegrationVersionChangelogJSONEndpoint(m.staging, config, version, structuredChangelog); err != nil {
+		return fmt.Errorf("error generating integration version changelog json endpoint: %w", err)
+	}
 
 	// iterate through each .jpg file in the img directory and create an
 	// endpoint for it
@@ -203,7 +339,7 @@ func (m CatalogManager) ProcessIntegrationVersion(version types.IntegrationVersi
 		return fmt.Errorf("error loading integration images: %w", err)
 	}
 	for imageName, imageData := range images {
-		if err := endpoints.GenerateIntegrationVersionImageEndpoint(m.config.StagingDir, config, version, imageName, imageData); err != nil {
+		if err := endpoints.GenerateIntegrationVersionImageEndpoint(m.staging, config, version, imageName, imageData); err != nil {
 			return fmt.Errorf("error generating integration version image endpoint: %w", err)
 		}
 	}
@@ -215,10 +351,117 @@ func (m CatalogManager) ProcessIntegrationVersion(version types.IntegrationVersi
 		return fmt.Errorf("error loading integration images: %w", err)
 	}
 	for dashboardName, dashboardData := range dashboards {
-		if err := endpoints.GenerateIntegrationVersionDashboardEndpoint(m.config.StagingDir, config, version, dashboardName, dashboardData); err != nil {
+		if err := endpoints.GenerateIntegrationVersionDashboardEndpoint(m.staging, config, version, dashboardName, dashboardData); err != nil {
 			return fmt.Errorf("error generating integration version dashboard endpoint: %w", err)
 		}
 	}
 
+	// the version endpoint includes a digest of all of the files generated
+	// above
+	if err := endpoints.GenerateIntegrationVersionEndpoint(m.staging, config, version); err != nil {
+		return fmt.Errorf("error generating integration version endpoint: %w", err)
+	}
+
 	return nil
 }
+
+// writeRelease writes the staged release to the release output, within the
+// directory named after its checksum. Releases are content addressed, so a
+// release that has already been written is left as is.
+func (m CatalogManager) writeRelease(checksum string) error {
+	if dir, ok := m.release.(*output.Dir); ok {
+		return m.writeReleaseDir(dir, checksum)
+	}
+
+	exists, err := releaseExists(m.release, checksum)
+	if err != nil {
+		return fmt.Errorf("error reading release: %w", err)
+	}
+	if exists {
+		return nil
+	}
+	if err := output.WriteTree(m.release, m.staging, checksum); err != nil {
+		return fmt.Errorf("error copying staging files to release dir: %w", err)
+	}
+	return nil
+}
+
+// writeReleaseDir writes the staged release to a temporary directory within
+// the release dir & renames it into place, so that the version endpoint can
+// never point at an incomplete release, even if a previous run was
+// interrupted while copying.
+func (m CatalogManager) writeReleaseDir(dir *output.Dir, checksum string) error {
+	dstPath := filepath.Join(dir.Path(), checksum)
+	if _, err := os.Stat(dstPath); err == nil {
+		return nil
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("error reading release dir: %w", err)
+	}
+	if err := os.MkdirAll(dir.Path(), 0700); err != nil {
+		return fmt.Errorf("error creating release dir: %w", err)
+	}
+
+	if staging, ok := m.staging.(*output.Dir); ok {
+		if err := util.CopyDirAtomic(staging.Path(), dstPath); err != nil {
+			return fmt.Errorf("error copying staging dir to release dir: %w", err)
+		}
+		return nil
+	}
+
+	tmpPath, err := os.MkdirTemp(dir.Path(), "."+checksum+".tmp*")
+	if err != nil {
+		return fmt.Errorf("error creating temporary release dir: %w", err)
+	}
+	if err := output.WriteTree(output.NewDir(tmpPath), m.staging, ""); err != nil {
+		_ = os.RemoveAll(tmpPath)
+		return fmt.Errorf("error copying staging files to release dir: %w", err)
+	}
+	if err := util.MoveDir(tmpPath, dstPath); err != nil {
+		_ = os.RemoveAll(tmpPath)
+		return fmt.Errorf("error moving release into place: %w", err)
+	}
+	return nil
+}
+
+// releaseExists reports whether the release with the given checksum has
+// been completely written to out, i.e. whether its manifest is present & every
+// file it lists matches its digest. The manifest is written after the other
+// files of the release, so an interrupted write is detected & written again.
+// Outputs that can't be read back are always written to.
+func releaseExists(out output.Output, checksum string) (bool, error) {
+	fsys, ok := out.(fs.FS)
+	if !ok {
+		return false, nil
+	}
+	releaseFS, err := fs.Sub(fsys, checksum)
+	if err != nil {
+		return false, err
+	}
+
+	b, err := fs.ReadFile(releaseFS, catalogapiv1.ManifestFileName)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	manifest := catalogapiv1.Manifest{}
+	if err := json.Unmarshal(b, &manifest); err != nil {
+		return false, nil
+	}
+
+	digests, err := util.CalculateFSFileDigests(releaseFS, catalogapiv1.ManifestFileName)
+	if err != nil {
+		return false, err
+	}
+	actual := map[string]util.FileDigest{}
+	for _, digest := range digests {
+		actual[digest.Path] = digest
+	}
+	for _, file := range manifest.Files {
+		digest, ok := actual[file.Path]
+		if !ok || digest.Size != file.Size || digest.SHA256 != file.SHA256 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -3,12 +3,16 @@ package catalogmanager
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io/fs"
 	"io/ioutil"
 	"os"
 	"path"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/rs/zerolog"
 	log "github.com/rs/zerolog/log"
@@ -16,9 +20,13 @@ import (
 	catalogapiv1 "github.com/sensu/catalog-api/internal/api/catalogapi/v1"
 	"github.com/sensu/catalog-api/internal/catalogloader"
 	mockcatalogloader "github.com/sensu/catalog-api/internal/catalogloader/mocks"
+	"github.com/sensu/catalog-api/internal/endpoints"
 	"github.com/sensu/catalog-api/internal/integrationloader"
 	mockintegrationloader "github.com/sensu/catalog-api/internal/integrationloader/mocks"
+	"github.com/sensu/catalog-api/internal/lint"
+	"github.com/sensu/catalog-api/internal/output"
 	"github.com/sensu/catalog-api/internal/types"
+	"github.com/sensu/catalog-api/internal/util"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -31,12 +39,14 @@ func newCatalogManager(tb testing.TB) CatalogManager {
 	stagingDir := tb.TempDir()
 	releaseDir := tb.TempDir()
 
-	return CatalogManager{
+	m := CatalogManager{
 		config: Config{
 			StagingDir: stagingDir,
 			ReleaseDir: releaseDir,
 		},
 	}
+	m.staging, m.release = m.config.outputs()
+	return m
 }
 
 func defaultIntegrations() types.Integrations {
@@ -49,39 +59,83 @@ func defaultIntegrations() types.Integrations {
 }
 
 func setupEndpointTest(tb testing.TB, integrations types.Integrations) (CatalogManager, error) {
+	m := newCatalogManager(tb)
+	m.loader = newFixtureLoader(integrations)
+
+	if err := m.ProcessCatalog(); err != nil {
+		return m, err
+	}
+	return m, nil
+}
+
+func newFixtureLoader(integrations types.Integrations) *mockcatalogloader.Loader {
 	cl := mockcatalogloader.Loader{}
 	cl.On("LoadIntegrations").Return(integrations, nil)
 
 	for _, integration := range integrations {
-		config := catalogv1.FixtureIntegration(integration.Namespace, integration.Name)
-		images := integrationloader.Images{
-			"image_1.png": "images png data 1",
-			"image_2.png": "images png data 2",
-		}
-		dashboards := integrationloader.Dashboards{
-			"dashboard_1.json": "{\"foo\":\"bar\"}",
-			"dashboard_2.json": "{\"baz\":\"kaz\"}",
+		cl.On("NewIntegrationLoader", integration).Return(newFixtureIntegrationLoader(integration, nil))
+	}
+	for _, namespace := range integrations.ByNamespace().Namespaces() {
+		namespaceYAML := ""
+		if namespace == "example_ns" {
+			namespaceYAML = fixtureNamespaceYAML
 		}
-
-		il := mockintegrationloader.Loader{}
-		il.On("LoadConfig").Return(config, nil)
-		il.On("LoadResources").Return(`[{"api_version": "core/v2"}]`, nil)
-		il.On("LoadLogo").Return("png data", nil)
-		il.On("LoadReadme").Return("readme markdown", nil)
-		il.On("LoadChangelog").Return("changelog markdown", nil)
-		il.On("LoadImages").Return(images, nil)
-		il.On("LoadDashboards").Return(dashboards, nil)
-
-		cl.On("NewIntegrationLoader", integration).Return(&il)
+		cl.On("NewNamespaceLoader", namespace).Return(newFixtureNamespaceLoader(namespaceYAML))
 	}
+	return &cl
+}
 
-	m := newCatalogManager(tb)
-	m.loader = &cl
+const fixtureNamespaceYAML = `---
+type: Namespace
+api_version: catalog/v1
+metadata:
+  name: example_ns
+spec:
+  display_name: Example Namespace
+  description: Integrations of the example namespace
+  logo: logo.png
+  owners: ["@artem"]
+  links:
+    - title: Website
+      url: https://example.com
+`
+
+// newFixtureNamespaceLoader returns a loader of a namespace directory that
+// contains namespaceYAML as its metadata file, or no metadata file if
+// namespaceYAML is empty.
+func newFixtureNamespaceLoader(namespaceYAML string) *mockintegrationloader.Loader {
+	nl := mockintegrationloader.Loader{}
+	if namespaceYAML == "" {
+		nl.On("GetFileContentsAsBytes", integrationloader.NamespaceConfigName).Return(nil, fs.ErrNotExist)
+	} else {
+		nl.On("GetFileContentsAsBytes", integrationloader.NamespaceConfigName).Return([]byte(namespaceYAML), nil)
+	}
+	nl.On("GetFileContentsAsString", "logo.png").Return("namespace png data", nil)
+	return &nl
+}
 
-	if err := m.ProcessCatalog(); err != nil {
-		return m, err
-	}
-	return m, nil
+// newFixtureIntegrationLoader returns a loader of a valid integration
+// version. If resourcesErr is not nil, loading its resources fails with it.
+func newFixtureIntegrationLoader(integration types.IntegrationVersion, resourcesErr error) *mockintegrationloader.Loader {
+	config := catalogv1.FixtureIntegration(integration.Namespace, integration.Name)
+	images := integrationloader.Images{
+		"image_1.png": "images png data 1",
+		"image_2.png": "images png data 2",
+	}
+	dashboards := integrationloader.Dashboards{
+		"dashboard_1.json": "{\"foo\":\"bar\"}",
+		"dashboard_2.json": "{\"baz\":\"kaz\"}",
+	}
+
+	il := mockintegrationloader.Loader{}
+	il.On("LoadConfig").Return(config, nil)
+	il.On("LoadResources").Return(`[{"api_version": "core/v2"}]`, resourcesErr)
+	il.On("LoadLogo").Return("png data", nil)
+	il.On("LoadReadme").Return("readme markdown", nil)
+	il.On("LoadChangelog").Return(catalogv1.FixtureChangelogMarkdown(integration.SemVer()), nil)
+	il.On("LoadImages").Return(images, nil)
+	il.On("LoadDashboards").Return(dashboards, nil)
+	return &il
 }
 
 // endpoint: /version.json
@@ -114,6 +168,232 @@ func TestVersionEndpoint(t *testing.T) {
 	}
 }
 
+// building the same integrations twice, loaded in a different order, must
+// produce byte-identical releases
+func TestProcessCatalog_Reproducible(t *testing.T) {
+	build := func(integrations types.Integrations) map[string]string {
+		m := newCatalogManager(t)
+		m.config.Timestamp = time.Unix(1643664852, 0)
+		m.loader = newFixtureLoader(integrations)
+		if err := m.ProcessCatalog(); err != nil {
+			t.Fatal(err)
+		}
+
+		files := map[string]string{}
+		err := filepath.WalkDir(m.config.ReleaseDir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			b, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(m.config.ReleaseDir, path)
+			files[rel] = string(b)
+			return err
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return files
+	}
+
+	integrations := defaultIntegrations()
+	reversed := types.Integrations{}
+	for i := len(integrations) - 1; i >= 0; i-- {
+		reversed = append(reversed, integrations[i])
+	}
+
+	first, second := build(integrations), build(reversed)
+	if len(first) == 0 {
+		t.Fatal("no files were generated")
+	}
+	if len(first) != len(second) {
+		t.Fatalf("builds generated %d & %d files", len(first), len(second))
+	}
+	for path, contents := range first {
+		if second[path] != contents {
+			t.Errorf("%s differs between builds:\n%s\n%s", path, contents, second[path])
+		}
+	}
+	if !strings.Contains(first["version.json"], `"last_updated":1643664852`) {
+		t.Errorf("version.json = %s, want last_updated of 1643664852", first["version.json"])
+	}
+}
+
+// outputRecorder records the files written to an output, in order.
+type outputRecorder struct {
+	names []string
+	files map[string]string
+}
+
+func (r *outputRecorder) WriteFile(name string, data []byte) error {
+	r.names = append(r.names, name)
+	r.files[name] = string(data)
+	return nil
+}
+
+func (r *outputRecorder) Close() error { return nil }
+func (r *outputRecorder) Abort() error { return nil }
+
+func TestProcessCatalog_Output(t *testing.T) {
+	out := &outputRecorder{files: map[string]string{}}
+	m, err := New(Config{Output: out, Timestamp: time.Unix(1643664852, 0)}, newFixtureLoader(defaultIntegrations()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.ProcessCatalog(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := newCatalogManager(t)
+	want.config.Timestamp = m.config.Timestamp
+	want.loader = newFixtureLoader(defaultIntegrations())
+	if err := want.ProcessCatalog(); err != nil {
+		t.Fatal(err)
+	}
+	checksum, err := want.config.StagingChecksum()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(out.names) == 0 || out.names[len(out.names)-1] != "version.json" {
+		t.Errorf("version.json must be written last, got %v", out.names)
+	}
+	for _, name := range out.names {
+		if name != "version.json" && !strings.HasPrefix(name, checksum+"/") {
+			t.Errorf("%s was written outside of release %s", name, checksum)
+		}
+		b, err := os.ReadFile(filepath.Join(want.config.ReleaseDir, filepath.FromSlash(name)))
+		if err != nil {
+			t.Errorf("%s was not generated within the release dir: %v", name, err)
+			continue
+		}
+		if out.files[name] != string(b) {
+			t.Errorf("%s differs from the release dir:\n%s\n%s", name, out.files[name], b)
+		}
+	}
+}
+
+func TestProcessCatalog_PartialRelease(t *testing.T) {
+	config := Config{Output: output.NewMemory(), Timestamp: time.Unix(1643664852, 0)}
+	m, err := New(config, newFixtureLoader(defaultIntegrations()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.ProcessCatalog(); err != nil {
+		t.Fatal(err)
+	}
+	complete := config.Output.(*output.Memory)
+	checksum, err := stagingChecksum(m.staging)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a previous run was interrupted after writing the manifest & a corrupt
+	// catalog endpoint
+	partial := output.NewMemory()
+	for _, name := range []string{catalogapiv1.ManifestFileName, "v1/catalog.json"} {
+		if err := partial.WriteFile(checksum+"/"+name, []byte("{}")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	manifest, err := fs.ReadFile(complete, checksum+"/"+catalogapiv1.ManifestFileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := partial.WriteFile(checksum+"/"+catalogapiv1.ManifestFileName, manifest); err != nil {
+		t.Fatal(err)
+	}
+
+	config.Output = partial
+	m, err = New(config, newFixtureLoader(defaultIntegrations()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.ProcessCatalog(); err != nil {
+		t.Fatal(err)
+	}
+	want, err := util.CalculateFSFileDigests(complete)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := util.CalculateFSFileDigests(partial)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ProcessCatalog() did not complete the partial release:\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestProcessCatalog_OutputDir(t *testing.T) {
+	releaseDir := t.TempDir()
+	m, err := New(Config{Output: output.NewDir(releaseDir)}, newFixtureLoader(defaultIntegrations()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.ProcessCatalog(); err != nil {
+		t.Fatal(err)
+	}
+	checksum, err := stagingChecksum(m.staging)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the release is renamed into place, leaving no temporary directory behind
+	entries, err := os.ReadDir(releaseDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := []string{}
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	want := []string{checksum, "version.json"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("release dir entries = %v, want %v", names, want)
+	}
+	got, err := util.CalculateDirChecksum(filepath.Join(releaseDir, checksum), "staging", catalogapiv1.IsCompressedSibling)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != checksum {
+		t.Errorf("release checksum = %s, want %s", got, checksum)
+	}
+}
+
+type phaseRecorder []string
+
+func (r *phaseRecorder) ObservePhase(phase string, duration time.Duration) {
+	*r = append(*r, phase)
+}
+
+func TestProcessCatalog_Observer(t *testing.T) {
+	phases := phaseRecorder{}
+	m := newCatalogManager(t)
+	m.config.Compress = true
+	m.config.Observer = &phases
+	m.loader = newFixtureLoader(defaultIntegrations())
+	if err := m.ProcessCatalog(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		PhaseLoad,
+		PhaseIntegrations,
+		PhaseCatalog,
+		PhaseManifest,
+		PhaseChecksum,
+		PhaseCompress,
+		PhaseCopy,
+		PhaseVersion,
+	}
+	if strings.Join(phases, ",") != strings.Join(want, ",") {
+		t.Errorf("observed phases = %v, want %v", phases, want)
+	}
+}
+
 // endpoint: /:release_sha256/v1/catalog.json
 func TestCatalogEndpoint(t *testing.T) {
 	integrations := defaultIntegrations()
@@ -272,6 +552,269 @@ func TestCatalogEndpoint(t *testing.T) {
 	}
 }
 
+// endpoint: /:release_sha256/v1/facets.json
+func TestFacetsEndpoint(t *testing.T) {
+	integrations := defaultIntegrations()
+	m, err := setupEndpointTest(t, integrations)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checksum, err := m.config.StagingChecksum()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	endpoint := path.Join(m.config.ReleaseDir, checksum, "v1", "facets.json")
+	b, err := ioutil.ReadFile(endpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	facets := catalogapiv1.Facets{}
+	if err := json.Unmarshal(b, &facets); err != nil {
+		t.Fatal(err)
+	}
+
+	want := catalogapiv1.Facets{
+		Tags:      map[string]int{"tag1": 3, "tag2": 3},
+		Providers: map[string]int{"alerts": 3},
+		Classes:   map[string]int{"community": 3},
+		Platforms: map[string]int{"linux": 3, "darwin": 3},
+	}
+	if !reflect.DeepEqual(facets, want) {
+		t.Errorf("facets mismatch: got = %v, want %v", facets, want)
+	}
+}
+
+// endpoint: /:release_sha256/v1/:facet/:value.json
+func TestFacetValueEndpoint(t *testing.T) {
+	integrations := defaultIntegrations()
+	m, err := setupEndpointTest(t, integrations)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checksum, err := m.config.StagingChecksum()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		facet string
+		value string
+	}{
+		{facet: "tags", value: "tag1"},
+		{facet: "providers", value: "alerts"},
+		{facet: "classes", value: "community"},
+		{facet: "platforms", value: "darwin"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.facet, func(t *testing.T) {
+			endpoint := path.Join(m.config.ReleaseDir, checksum, "v1", tt.facet, tt.value+".json")
+			b, err := ioutil.ReadFile(endpoint)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fv := catalogapiv1.FacetValue{}
+			if err := json.Unmarshal(b, &fv); err != nil {
+				t.Fatal(err)
+			}
+			if fv.Facet != tt.facet || fv.Value != tt.value {
+				t.Errorf("facet mismatch: got = %s/%s, want %s/%s", fv.Facet, fv.Value, tt.facet, tt.value)
+			}
+
+			got := []string{}
+			for _, iv := range fv.Integrations {
+				got = append(got, iv.Metadata.Namespace+"/"+iv.Metadata.Name+"/"+iv.Version)
+			}
+			want := []string{"example_ns/example/1.3.0", "example_ns/other/4.5.9", "foo/bar/0.1.0"}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("integrations mismatch: got = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+// The endpoints of a namespace named after a facet, e.g. tags/tag1.json,
+// would overwrite the endpoints of the facet values.
+func TestFacetValueEndpoint_ReservedNamespace(t *testing.T) {
+	for _, facet := range catalogapiv1.FacetNames {
+		t.Run(facet, func(t *testing.T) {
+			want := fmt.Sprintf("namespace name %s is reserved by the catalog api", facet)
+
+			integrations := append(defaultIntegrations(), types.FixtureIntegrationVersion(facet, "tag1", 1, 0, 0))
+			_, err := setupEndpointTest(t, integrations)
+			if err == nil || !strings.Contains(err.Error(), want) {
+				t.Errorf("ProcessCatalog() error = %v, want %s", err, want)
+			}
+
+			// the facet endpoints are never generated alongside such a
+			// namespace, even if it was not rejected beforehand
+			nsIntegrations := map[string][]catalogapiv1.IntegrationVersion{facet: {}}
+			err = endpoints.GenerateFacetEndpoints(output.NewMemory(), nsIntegrations)
+			if err == nil || err.Error() != want {
+				t.Errorf("GenerateFacetEndpoints() error = %v, want %s", err, want)
+			}
+		})
+	}
+}
+
+// endpoint: /:release_sha256/v1/search-index.json
+func TestSearchIndexEndpoint(t *testing.T) {
+	integrations := defaultIntegrations()
+	m, err := setupEndpointTest(t, integrations)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checksum, err := m.config.StagingChecksum()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	endpoint := path.Join(m.config.ReleaseDir, checksum, "v1", "search-index.json")
+	b, err := ioutil.ReadFile(endpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	index := struct {
+		Fields        []string        `json:"fields"`
+		FieldVectors  [][]interface{} `json:"fieldVectors"`
+		InvertedIndex [][]interface{} `json:"invertedIndex"`
+	}{}
+	if err := json.Unmarshal(b, &index); err != nil {
+		t.Fatal(err)
+	}
+
+	wantFields := []string{"display_name", "tags", "short_description", "provider", "class", "contributors", "readme"}
+	if !reflect.DeepEqual(index.Fields, wantFields) {
+		t.Errorf("fields mismatch: got = %v, want %v", index.Fields, wantFields)
+	}
+
+	// one field vector per field for each of the latest integrations
+	refs := map[string]bool{}
+	for _, vector := range index.FieldVectors {
+		refs[vector[0].(string)] = true
+	}
+	for _, ref := range []string{"display_name/foo/bar", "readme/example_ns/example", "tags/example_ns/other"} {
+		if !refs[ref] {
+			t.Errorf("field vector not found: %s", ref)
+		}
+	}
+	if got, want := len(index.FieldVectors), 3*len(wantFields); got != want {
+		t.Errorf("field vector count mismatch: got = %v, want %v", got, want)
+	}
+
+	terms := map[string]bool{}
+	for _, entry := range index.InvertedIndex {
+		terms[entry[0].(string)] = true
+	}
+	for _, term := range []string{"bar", "tag1", "lorem", "artem", "readm", "markdown"} {
+		if !terms[term] {
+			t.Errorf("term not found in inverted index: %s", term)
+		}
+	}
+}
+
+// endpoint: /:release_sha256/v1/namespaces.json
+func TestNamespacesEndpoint(t *testing.T) {
+	integrations := defaultIntegrations()
+	m, err := setupEndpointTest(t, integrations)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checksum, err := m.config.StagingChecksum()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	endpoint := path.Join(m.config.ReleaseDir, checksum, "v1", "namespaces.json")
+	b, err := ioutil.ReadFile(endpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	namespaces := catalogapiv1.Namespaces{}
+	if err := json.Unmarshal(b, &namespaces); err != nil {
+		t.Fatal(err)
+	}
+
+	want := catalogapiv1.Namespaces{
+		Namespaces: []catalogapiv1.IntegrationNamespace{
+			{
+				Name:         "example_ns",
+				DisplayName:  "Example Namespace",
+				Description:  "Integrations of the example namespace",
+				Logo:         "example_ns/logo.png",
+				Owners:       []string{"@artem"},
+				Links:        []catalogv1.NamespaceLink{{Title: "Website", URL: "https://example.com"}},
+				Integrations: []string{"example", "other"},
+			},
+			{
+				Name:         "foo",
+				Integrations: []string{"bar"},
+			},
+		},
+	}
+	if !reflect.DeepEqual(namespaces, want) {
+		t.Errorf("namespaces mismatch: got = %+v, want %+v", namespaces, want)
+	}
+}
+
+// endpoint: /:release_sha256/v1/:namespace.json
+func TestIntegrationNamespaceEndpoint(t *testing.T) {
+	integrations := defaultIntegrations()
+	m, err := setupEndpointTest(t, integrations)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checksum, err := m.config.StagingChecksum()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	endpoint := path.Join(m.config.ReleaseDir, checksum, "v1", "foo.json")
+	b, err := ioutil.ReadFile(endpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	namespace := catalogapiv1.IntegrationNamespace{}
+	if err := json.Unmarshal(b, &namespace); err != nil {
+		t.Fatal(err)
+	}
+	want := catalogapiv1.IntegrationNamespace{
+		Name:         "foo",
+		Integrations: []string{"bar"},
+	}
+	if !reflect.DeepEqual(namespace, want) {
+		t.Errorf("namespace mismatch: got = %+v, want %+v", namespace, want)
+	}
+}
+
+// endpoint: /:release_sha256/v1/:namespace/logo.png
+func TestIntegrationNamespaceLogoEndpoint(t *testing.T) {
+	integrations := defaultIntegrations()
+	m, err := setupEndpointTest(t, integrations)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checksum, err := m.config.StagingChecksum()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	endpoint := path.Join(m.config.ReleaseDir, checksum, "v1", "example_ns", "logo.png")
+	b, err := ioutil.ReadFile(endpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(b), "namespace png data"; got != want {
+		t.Errorf("logo mismatch: got = %v, want %v", got, want)
+	}
+}
+
 // endpoint: /:release_sha256/v1/:namespace/:integration.json
 func TestIntegrationEndpoint(t *testing.T) {
 	integrations := defaultIntegrations()
@@ -643,13 +1186,51 @@ func TestIntegrationVersionChangelogEndpoint(t *testing.T) {
 	}
 
 	got := string(b)
-	want := "changelog markdown"
+	want := catalogv1.FixtureChangelogMarkdown("1.2.3")
 	if !reflect.DeepEqual(got, want) {
 		t.Errorf("changelog mismatch: got = %v, want %v",
 			got, want)
 	}
 }
 
+// endpoint: /:release_sha256/v1/:namespace/:name/:version/CHANGELOG.json
+func TestIntegrationVersionChangelogJSONEndpoint(t *testing.T) {
+	integrations := defaultIntegrations()
+	m, err := setupEndpointTest(t, integrations)
+	if err := m.ProcessCatalog(); err != nil {
+		t.Fatal(err)
+	}
+
+	checksum, err := m.config.StagingChecksum()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	endpoint := path.Join(m.config.ReleaseDir, checksum, "v1", "example_ns", "example", "1.2.3", "CHANGELOG.json")
+	b, err := ioutil.ReadFile(endpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changelog := catalogv1.Changelog{}
+	if err := json.Unmarshal(b, &changelog); err != nil {
+		t.Fatal(err)
+	}
+
+	want := catalogv1.Changelog{
+		Entries: []catalogv1.ChangelogEntry{
+			{
+				Version: "1.2.3",
+				Date:    "2022-01-25",
+				Added:   []string{"Release 1.2.3"},
+			},
+		},
+	}
+	if !reflect.DeepEqual(changelog, want) {
+		t.Errorf("changelog mismatch: got = %v, want %v",
+			changelog, want)
+	}
+}
+
 // endpoint: /:release_sha256/v1/:namespace/:name/:version/logo.png
 func TestIntegrationVersionLogoEndpoint(t *testing.T) {
 	integrations := defaultIntegrations()
@@ -987,6 +1568,35 @@ func TestCatalogManager_ProcessCatalog(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "error when changelog has no entry for version",
+			fields: fields{
+				config: Config{
+					StagingDir: t.TempDir(),
+					ReleaseDir: t.TempDir(),
+				},
+				loader: func() catalogloader.Loader {
+					config := catalogv1.FixtureIntegration("foo", "bar")
+					integrations := types.Integrations{
+						types.FixtureIntegrationVersion("example_ns", "example", 1, 2, 3),
+					}
+
+					il := mockintegrationloader.Loader{}
+					il.On("LoadConfig").Return(config, nil)
+					il.On("LoadResources").Return("", nil)
+					il.On("LoadLogo").Return("", nil)
+					il.On("LoadReadme").Return("", nil)
+					il.On("LoadChangelog").Return(catalogv1.FixtureChangelogMarkdown("1.2.2"), nil)
+
+					cl := mockcatalogloader.Loader{}
+					cl.On("LoadIntegrations").Return(integrations, nil)
+					cl.On("NewIntegrationLoader", mock.Anything).Return(&il)
+
+					return &cl
+				}(),
+			},
+			wantErr: true,
+		},
 		{
 			name: "error when load images fails",
 			fields: fields{
@@ -1005,7 +1615,7 @@ func TestCatalogManager_ProcessCatalog(t *testing.T) {
 					il.On("LoadResources").Return("", nil)
 					il.On("LoadLogo").Return("", nil)
 					il.On("LoadReadme").Return("", nil)
-					il.On("LoadChangelog").Return("", nil)
+					il.On("LoadChangelog").Return(catalogv1.FixtureChangelogMarkdown("1.2.3"), nil)
 					il.On("LoadImages").Return(integrationloader.Images{}, errors.New("read error"))
 
 					cl := mockcatalogloader.Loader{}
@@ -1035,7 +1645,7 @@ func TestCatalogManager_ProcessCatalog(t *testing.T) {
 					il.On("LoadResources").Return("", nil)
 					il.On("LoadLogo").Return("", nil)
 					il.On("LoadReadme").Return("", nil)
-					il.On("LoadChangelog").Return("", nil)
+					il.On("LoadChangelog").Return(catalogv1.FixtureChangelogMarkdown("1.2.3"), nil)
 					il.On("LoadImages").Return(integrationloader.Images{}, nil)
 					il.On("LoadDashboards").Return(integrationloader.Dashboards{}, errors.New("read error"))
 
@@ -1066,13 +1676,14 @@ func TestCatalogManager_ProcessCatalog(t *testing.T) {
 					il.On("LoadResources").Return("", nil)
 					il.On("LoadLogo").Return("", nil)
 					il.On("LoadReadme").Return("", nil)
-					il.On("LoadChangelog").Return("", nil)
+					il.On("LoadChangelog").Return(catalogv1.FixtureChangelogMarkdown("1.2.3"), nil)
 					il.On("LoadImages").Return(integrationloader.Images{}, nil)
 					il.On("LoadDashboards").Return(integrationloader.Dashboards{}, nil)
 
 					cl := mockcatalogloader.Loader{}
 					cl.On("LoadIntegrations").Return(integrations, nil)
 					cl.On("NewIntegrationLoader", mock.Anything).Return(&il)
+					cl.On("NewNamespaceLoader", mock.Anything).Return(newFixtureNamespaceLoader(""))
 
 					return &cl
 				}(),
@@ -1086,9 +1697,319 @@ func TestCatalogManager_ProcessCatalog(t *testing.T) {
 				config: tt.fields.config,
 				loader: tt.fields.loader,
 			}
+			m.staging, m.release = m.config.outputs()
 			if err := m.ProcessCatalog(); (err != nil) != tt.wantErr {
 				t.Errorf("CatalogManager.ProcessCatalog() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
+
+func TestCatalogManager_ValidateCatalog(t *testing.T) {
+	integration := types.FixtureIntegrationVersion("example_ns", "example", 1, 2, 3)
+
+	il := mockintegrationloader.Loader{}
+	il.On("LoadConfig").Return(catalogv1.FixtureIntegration("example_ns", "example"), nil)
+	il.On("LoadResources").Return(`[{"api_version": "core/v2"}]`, nil)
+	il.On("LoadLogo").Return("", errors.New("logo error"))
+	il.On("LoadReadme").Return("readme markdown", nil)
+	il.On("LoadChangelog").Return(catalogv1.FixtureChangelogMarkdown(integration.SemVer()), nil)
+	il.On("LoadImages").Return(integrationloader.Images{}, nil)
+
+	cl := mockcatalogloader.Loader{}
+	cl.On("LoadIntegrations").Return(types.Integrations{integration}, nil)
+	cl.On("NewIntegrationLoader", integration).Return(&il)
+	cl.On("NewNamespaceLoader", "example_ns").Return(newFixtureNamespaceLoader(fixtureNamespaceYAML))
+
+	m := newCatalogManager(t)
+	m.config.IntegrationsDirName = "integrations"
+	m.loader = &cl
+	err := m.ValidateCatalog()
+
+	var failures ValidationErrors
+	if !errors.As(err, &failures) {
+		t.Fatalf("ValidateCatalog() error = %v, want ValidationErrors", err)
+	}
+	if len(failures) != 1 {
+		t.Fatalf("ValidateCatalog() returned %d failures, want 1: %v", len(failures), failures)
+	}
+	want := "example_ns/example 1.2.3: Failed to load logo: logo error"
+	if got := failures[0].Error(); got != want {
+		t.Errorf("failure = %q, want %q", got, want)
+	}
+	diagnostic := failures[0].Diagnostic()
+	if diagnostic.RuleID != RuleLogoLoad || diagnostic.File != "integrations/example_ns/example/logo.png" {
+		t.Errorf("diagnostic = %+v, want the logo-load rule & the logo file", diagnostic)
+	}
+}
+
+func TestCatalogManager_ValidateCatalog_Namespace(t *testing.T) {
+	tests := []struct {
+		name          string
+		namespace     string
+		namespaceYAML string
+		wantErr       string
+		wantLine      int
+	}{
+		{
+			name:          "valid namespace",
+			namespace:     "example_ns",
+			namespaceYAML: fixtureNamespaceYAML,
+		},
+		{
+			name:      "namespace without metadata",
+			namespace: "example_ns",
+		},
+		{
+			name:          "name of another namespace",
+			namespace:     "example_ns",
+			namespaceYAML: strings.Replace(fixtureNamespaceYAML, "name: example_ns", "name: other_ns", 1),
+			wantErr:       "example_ns: Failed to validate namespace: integrations/example_ns/namespace.yaml:5:3: name must match the namespace directory example_ns, got: other_ns",
+			wantLine:      5,
+		},
+		{
+			name:          "invalid link",
+			namespace:     "example_ns",
+			namespaceYAML: strings.Replace(fixtureNamespaceYAML, "https://example.com", "example.com", 1),
+			wantErr:       "example_ns: Failed to validate namespace: integrations/example_ns/namespace.yaml:13:7: link url must be an http or https url, got: example.com",
+			wantLine:      13,
+		},
+		{
+			name:      "reserved namespace name",
+			namespace: "catalog",
+			wantErr:   "catalog: Failed to validate namespace: namespace name catalog is reserved by the catalog api",
+		},
+		{
+			name:      "facet namespace name",
+			namespace: "tags",
+			wantErr:   "tags: Failed to validate namespace: namespace name tags is reserved by the catalog api",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			integration := types.FixtureIntegrationVersion(tt.namespace, "example", 1, 2, 3)
+			il := newFixtureIntegrationLoader(integration, nil)
+			il.On("GetFileContentsAsString", mock.Anything).Return("", nil)
+			il.On("ListFiles").Return([]string{}, nil)
+
+			cl := mockcatalogloader.Loader{}
+			cl.On("LoadIntegrations").Return(types.Integrations{integration}, nil)
+			cl.On("NewIntegrationLoader", integration).Return(il)
+			cl.On("NewNamespaceLoader", tt.namespace).Return(newFixtureNamespaceLoader(tt.namespaceYAML))
+
+			m := newCatalogManager(t)
+			m.config.IntegrationsDirName = "integrations"
+			m.loader = &cl
+			err := m.ValidateCatalog()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("ValidateCatalog() error = %v, want nil", err)
+				}
+				return
+			}
+
+			var failures ValidationErrors
+			if !errors.As(err, &failures) || len(failures) != 1 {
+				t.Fatalf("ValidateCatalog() error = %v, want 1 failure", err)
+			}
+			if got := failures[0].Error(); got != tt.wantErr {
+				t.Errorf("failure = %q, want %q", got, tt.wantErr)
+			}
+			diagnostic := failures[0].Diagnostic()
+			if diagnostic.RuleID != RuleNamespaceInvalid || diagnostic.Line != tt.wantLine {
+				t.Errorf("diagnostic = %+v, want the namespace-invalid rule at line %d", diagnostic, tt.wantLine)
+			}
+		})
+	}
+}
+
+func TestCatalogManager_ProcessCatalog_KeepGoing(t *testing.T) {
+	broken := types.FixtureIntegrationVersion("example_ns", "example", 1, 3, 0)
+	brokenOther := types.FixtureIntegrationVersion("example_ns", "other", 4, 5, 9)
+
+	newLoader := func(failing ...types.IntegrationVersion) *mockcatalogloader.Loader {
+		isFailing := map[types.IntegrationVersion]bool{}
+		for _, integration := range failing {
+			isFailing[integration] = true
+		}
+		cl := mockcatalogloader.Loader{}
+		cl.On("LoadIntegrations").Return(defaultIntegrations(), nil)
+		for _, integration := range defaultIntegrations() {
+			var err error
+			if isFailing[integration] {
+				err = errors.New("invalid resources")
+			}
+			cl.On("NewIntegrationLoader", integration).Return(newFixtureIntegrationLoader(integration, err))
+		}
+		cl.On("NewNamespaceLoader", mock.Anything).Return(newFixtureNamespaceLoader(""))
+		return &cl
+	}
+
+	tests := []struct {
+		name         string
+		keepGoing    bool
+		maxExcluded  int
+		failing      []types.IntegrationVersion
+		wantExcluded int
+		wantErr      bool
+	}{
+		{
+			name:         "keep going excludes failing versions",
+			keepGoing:    true,
+			failing:      []types.IntegrationVersion{broken},
+			wantExcluded: 1,
+		},
+		{
+			name:         "keep going without failures",
+			keepGoing:    true,
+			wantExcluded: 0,
+		},
+		{
+			name:        "keep going fails once too many integrations are excluded",
+			keepGoing:   true,
+			maxExcluded: 1,
+			failing:     []types.IntegrationVersion{broken, brokenOther},
+			wantErr:     true,
+		},
+
+		{
+			name:    "failing version fails the release without keep going",
+			failing: []types.IntegrationVersion{broken},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newCatalogManager(t)
+			m.config.IntegrationsDirName = "integrations"
+			m.config.KeepGoing = tt.keepGoing
+			m.config.MaxExcluded = tt.maxExcluded
+			m.loader = newLoader(tt.failing...)
+
+			err := m.ProcessCatalog()
+			versionPath := catalogapiv1.NewVersionEndpoint(m.config.ReleaseDir, catalogapiv1.ReleaseVersion{}).GetOutputPath()
+			if tt.wantErr {
+				var partial PartialReleaseError
+				if err == nil || errors.As(err, &partial) {
+					t.Fatalf("ProcessCatalog() error = %v, want a failed release", err)
+				}
+				if _, err := os.Stat(versionPath); !errors.Is(err, fs.ErrNotExist) {
+					t.Errorf("version endpoint was generated for a failed release")
+				}
+				return
+			}
+
+			var partial PartialReleaseError
+			if tt.wantExcluded == 0 && err != nil {
+				t.Fatalf("ProcessCatalog() error = %v, want nil", err)
+			}
+			if tt.wantExcluded > 0 {
+				if !errors.As(err, &partial) {
+					t.Fatalf("ProcessCatalog() error = %v, want PartialReleaseError", err)
+				}
+				if len(partial.Excluded) != tt.wantExcluded {
+					t.Errorf("excluded %d integration versions, want %d", len(partial.Excluded), tt.wantExcluded)
+				}
+			}
+			if _, err := os.Stat(versionPath); err != nil {
+				t.Errorf("version endpoint was not generated: %v", err)
+			}
+
+			b, err := os.ReadFile(catalogapiv1.NewErrorsEndpoint(m.config.StagingDir, catalogapiv1.ReleaseErrors{}).GetOutputPath())
+			if err != nil {
+				t.Fatal(err)
+			}
+			releaseErrors := catalogapiv1.ReleaseErrors{}
+			if err := json.Unmarshal(b, &releaseErrors); err != nil {
+				t.Fatal(err)
+			}
+			if len(releaseErrors.Errors) != tt.wantExcluded {
+				t.Fatalf("errors endpoint lists %d errors, want %d", len(releaseErrors.Errors), tt.wantExcluded)
+			}
+			if tt.wantExcluded == 0 {
+				return
+			}
+			if got := releaseErrors.Errors[0]; got.RuleID != RuleBuildFailed || got.Version != "1.3.0" {
+				t.Errorf("errors endpoint error = %+v, want a build failure of version 1.3.0", got)
+			}
+
+			// the previous valid version becomes the latest version
+			integration := catalogapiv1.IntegrationWithVersions{
+				Integration: catalogv1.FixtureIntegration("example_ns", "example"),
+			}
+			b, err = os.ReadFile(catalogapiv1.NewIntegrationEndpoint(m.config.StagingDir, integration).GetOutputPath())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal(b, &integration); err != nil {
+				t.Fatal(err)
+			}
+			if integration.Version != "1.2.3" || !reflect.DeepEqual(integration.Versions, []string{"1.2.3"}) {
+				t.Errorf("integration version = %s, versions = %v, want 1.2.3 only", integration.Version, integration.Versions)
+			}
+		})
+	}
+}
+
+func TestValidationErrors_Integrations(t *testing.T) {
+	failures := ValidationErrors{
+		{Namespace: "example_ns", Integration: "example", Version: "1.2.3"},
+		{Namespace: "example_ns", Integration: "example", Version: "1.3.0"},
+		{Namespace: "example_ns", Integration: "other", Version: "4.5.9"},
+		{Namespace: "other_ns", Integration: "example", Version: "1.2.3"},
+	}
+	if got := failures.Integrations(); got != 3 {
+		t.Errorf("ValidationErrors.Integrations() = %d, want 3", got)
+	}
+}
+
+func TestCatalogManager_LintIntegrations(t *testing.T) {
+	integration := types.FixtureIntegrationVersion("example_ns", "example", 1, 2, 3)
+	broken := types.FixtureIntegrationVersion("example_ns", "broken", 0, 1, 0)
+	working := types.FixtureIntegrationVersion("example_ns", "example", 99991231, 0, 0)
+	working.GitTag = ""
+	working.GitRef = ""
+	working.Source = "path"
+
+	config := catalogv1.FixtureIntegration("example_ns", "example")
+	config.Tags = []string{"Example"}
+	il := mockintegrationloader.Loader{}
+	il.On("LoadConfig").Return(config, nil)
+	il.On("LoadReadme").Return("readme markdown", nil)
+	il.On("ListFiles").Return([]string{integrationloader.ConfigName, "notes.txt"}, nil)
+
+	brokenLoader := mockintegrationloader.Loader{}
+	brokenLoader.On("LoadConfig").Return(catalogv1.Integration{}, errors.New("config error"))
+
+	cl := mockcatalogloader.Loader{}
+	cl.On("NewIntegrationLoader", integration).Return(&il)
+	cl.On("NewIntegrationLoader", broken).Return(&brokenLoader)
+	cl.On("NewIntegrationLoader", working).Return(&il)
+
+	linter, err := lint.New(lint.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := newCatalogManager(t)
+	m.config.IntegrationsDirName = "integrations"
+	m.loader = &cl
+	diags, err := m.LintIntegrations(linter, types.Integrations{integration, broken, working})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := []string{}
+	for _, d := range diags {
+		got = append(got, fmt.Sprintf("%s %s %s %s", d.Version, d.Severity, d.RuleID, d.File))
+	}
+	// findings on the tagged version are downgraded to warnings
+	want := []string{
+		"1.2.3 warning stray-files integrations/example_ns/example/notes.txt",
+		"1.2.3 warning tag-format integrations/example_ns/example/sensu-integration.yaml",
+		"99991231.0.0 warning stray-files integrations/example_ns/example/notes.txt",
+		"99991231.0.0 error tag-format integrations/example_ns/example/sensu-integration.yaml",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LintIntegrations() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,84 @@
+package catalogmanager
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+
+	catalogv1 "github.com/sensu/catalog-api/internal/api/catalog/v1"
+	catalogapiv1 "github.com/sensu/catalog-api/internal/api/catalogapi/v1"
+	"github.com/sensu/catalog-api/internal/endpoints"
+	"github.com/sensu/catalog-api/internal/integrationloader"
+	"github.com/sensu/catalog-api/internal/position"
+	"github.com/sensu/catalog-api/internal/types"
+)
+
+// ProcessNamespaceMetadata generates the endpoints of a namespace, which
+// describe the namespace & list its integrations, and returns the namespace
+// as listed by the namespaces endpoint.
+func (m CatalogManager) ProcessNamespaceMetadata(namespace string, integrations types.Integrations) (catalogapiv1.IntegrationNamespace, error) {
+	metadata, logo, err := m.loadNamespace(namespace)
+	if err != nil {
+		return catalogapiv1.IntegrationNamespace{}, err
+	}
+
+	ns := catalogapiv1.IntegrationNamespace{
+		Name:         namespace,
+		DisplayName:  metadata.DisplayName,
+		Description:  metadata.Description,
+		Owners:       metadata.Owners,
+		Links:        metadata.Links,
+		Integrations: integrations.ByName().Names(),
+	}
+	if logo != "" {
+		if err := endpoints.GenerateIntegrationNamespaceLogoEndpoint(m.staging, namespace, logo); err != nil {
+			return ns, fmt.Errorf("error generating integration namespace logo endpoint: %w", err)
+		}
+		ns.Logo = catalogapiv1.IntegrationNamespaceLogoPath(namespace)
+	}
+	if err := endpoints.GenerateIntegrationNamespaceEndpoint(m.staging, ns); err != nil {
+		return ns, fmt.Errorf("error generating integration namespace endpoint: %w", err)
+	}
+	return ns, nil
+}
+
+// loadNamespace loads & validates the metadata of a namespace along with the
+// contents of its logo, if any. The zero value is returned if the namespace
+// has no metadata file.
+func (m CatalogManager) loadNamespace(namespace string) (catalogv1.Namespace, string, error) {
+	if catalogapiv1.IsReservedNamespace(namespace) {
+		return catalogv1.Namespace{}, "", fmt.Errorf("namespace name %s is reserved by the catalog api", namespace)
+	}
+
+	loader := m.loader.NewNamespaceLoader(namespace)
+	configFile := m.namespaceFile(namespace, integrationloader.NamespaceConfigName)
+	metadata, err := integrationloader.LoadNamespace(loader)
+	if errors.Is(err, fs.ErrNotExist) {
+		return catalogv1.Namespace{}, "", nil
+	} else if err != nil {
+		return metadata, "", position.InFile(configFile, err)
+	}
+	if err := metadata.Validate(); err != nil {
+		return metadata, "", position.InFile(configFile, err)
+	}
+	if metadata.Metadata.Name != namespace {
+		err := fmt.Errorf("name must match the namespace directory %s, got: %s", namespace, metadata.Metadata.Name)
+		return metadata, "", position.InFile(configFile, position.At(metadata.Positions.Lookup("metadata.name"), err))
+	}
+
+	logo := ""
+	if metadata.Logo != "" {
+		logo, err = loader.GetFileContentsAsString(metadata.Logo)
+		if err != nil {
+			return metadata, "", fmt.Errorf("error loading namespace logo %s: %w", m.namespaceFile(namespace, metadata.Logo), err)
+		}
+	}
+	return metadata, logo, nil
+}
+
+// namespaceFile returns the path of a file of a namespace, relative to the
+// catalog repository.
+func (m CatalogManager) namespaceFile(namespace string, name string) string {
+	return path.Join(m.config.IntegrationsDirName, namespace, name)
+}
@@ -0,0 +1,31 @@
+package catalogmanager
+
+import "time"
+
+// Phases of ProcessCatalog reported to an Observer.
+const (
+	PhaseLoad         = "load"
+	PhaseIntegrations = "integrations"
+	PhaseCatalog      = "catalog"
+	PhaseManifest     = "manifest"
+	PhaseChecksum     = "checksum"
+	PhaseCompress     = "compress"
+	PhaseCopy         = "copy"
+	PhaseVersion      = "version"
+)
+
+// Observer is notified of the duration of each completed phase of
+// ProcessCatalog.
+type Observer interface {
+	ObservePhase(phase string, duration time.Duration)
+}
+
+// observePhase reports the duration of phase, which began at start, to the
+// configured observer & returns the start time of the next phase.
+func (m CatalogManager) observePhase(phase string, start time.Time) time.Time {
+	now := time.Now()
+	if m.config.Observer != nil {
+		m.config.Observer.ObservePhase(phase, now.Sub(start))
+	}
+	return now
+}
@@ -0,0 +1,42 @@
+package catalogmanager
+
+import (
+	"path"
+	"strings"
+
+	catalogv1 "github.com/sensu/catalog-api/internal/api/catalog/v1"
+	"github.com/sensu/catalog-api/internal/searchindex"
+)
+
+// searchFields returns the integration fields included in the search index
+// along with their relative weights.
+func searchFields() []searchindex.Field {
+	return []searchindex.Field{
+		{Name: "display_name", Boost: 10},
+		{Name: "tags", Boost: 5},
+		{Name: "short_description", Boost: 3},
+		{Name: "provider", Boost: 2},
+		{Name: "class", Boost: 1},
+		{Name: "contributors", Boost: 1},
+		{Name: "readme", Boost: 1},
+	}
+}
+
+func newSearchIndexBuilder() *searchindex.Builder {
+	return searchindex.NewBuilder(searchFields()...)
+}
+
+// addSearchDocument adds an integration to the search index. Documents are
+// referenced by "<namespace>/<name>", which maps to the integration endpoint.
+func addSearchDocument(builder *searchindex.Builder, integration catalogv1.Integration, readme string) {
+	ref := path.Join(integration.Metadata.Namespace, integration.Metadata.Name)
+	builder.Add(ref, map[string]string{
+		"display_name":      integration.DisplayName,
+		"tags":              strings.Join(integration.Tags, " "),
+		"short_description": integration.ShortDescription,
+		"provider":          integration.Provider,
+		"class":             integration.Class,
+		"contributors":      strings.Join(integration.Contributors, " "),
+		"readme":            readme,
+	})
+}
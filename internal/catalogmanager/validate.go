@@ -1,80 +1,140 @@
 package catalogmanager
 
 import (
-	"errors"
 	"fmt"
+	"path"
 
 	"github.com/rs/zerolog/log"
+
+	catalogv1 "github.com/sensu/catalog-api/internal/api/catalog/v1"
+	"github.com/sensu/catalog-api/internal/integrationloader"
+	"github.com/sensu/catalog-api/internal/position"
+	"github.com/sensu/catalog-api/internal/types"
 )
 
 func (m CatalogManager) ValidateCatalog() error {
+	_, err := m.ValidateIntegrations()
+	return err
+}
+
+// ValidateIntegrations validates every integration version loaded by the
+// catalog loader & returns the validated integration versions. The error
+// holds the ValidationErrors of the versions that failed validation, if any.
+func (m CatalogManager) ValidateIntegrations() (types.Integrations, error) {
 	integrations, err := m.loader.LoadIntegrations()
 	if err != nil {
-		return fmt.Errorf("error loading integrations from catalog: %w", err)
+		return nil, fmt.Errorf("error loading integrations from catalog: %w", err)
 	}
+	integrations = integrations.Sorted()
 
 	// loop through the list of namespaces & integrations, and unmarshal the
 	// configs & resource files
-	validationFailed := false
-	for namespace, integrations := range integrations.ByNamespace() {
-		for _, integration := range integrations {
+	failures := ValidationErrors{}
+	byNamespace := integrations.ByNamespace()
+	for _, namespace := range byNamespace.Namespaces() {
+		// load & validate the namespace metadata
+		if _, _, err := m.loadNamespace(namespace); err != nil {
+			file := m.namespaceFile(namespace, integrationloader.NamespaceConfigName)
+			err = position.InFile(file, err)
+			log.Debug().Err(err).Str("namespace", namespace).Msg("Failed to validate namespace")
+			failures = append(failures, ValidationError{
+				Namespace: namespace,
+				Rule:      RuleNamespaceInvalid,
+				File:      file,
+				Message:   "Failed to validate namespace",
+				Err:       err,
+			})
+		}
+
+		for _, integration := range byNamespace[namespace] {
 			integrationLoader := m.loader.NewIntegrationLoader(integration)
 
 			logger := log.With().
 				Str("namespace", namespace).
 				Str("integration", integration.Name).
 				Logger()
+			integrationPath := integration.Path(m.config.IntegrationsDirName)
+			fail := func(err error, rule string, file string, msg string) {
+				file = path.Join(integrationPath, file)
+				err = position.InFile(file, err)
+				logger.Debug().Err(err).Msg(msg)
+				failures = append(failures, ValidationError{
+					Namespace:   namespace,
+					Integration: integration.Name,
+					Version:     integration.SemVer(),
+					Rule:        rule,
+					File:        file,
+					Message:     msg,
+					Err:         err,
+				})
+			}
 
 			// load & validate the integration config
 			integrationConfig, err := integrationLoader.LoadConfig()
 			if err != nil {
-				logger.Err(err).Msg("Failed to load integration config")
-				validationFailed = true
+				fail(err, RuleConfigLoad, integrationloader.ConfigName, "Failed to load integration config")
 			}
-			if err := integrationConfig.Validate(); err != nil {
-				logger.Err(err).Msg("Failed to validate integration config")
-				validationFailed = true
+			if err := integrationConfig.ValidateWith(m.config.Taxonomy); err != nil {
+				fail(err, RuleConfigInvalid, integrationloader.ConfigName, "Failed to validate integration config")
 			}
 
 			// load & validate sensu resources
 			// TODO(jk): call resouces.Validate() once it's implemented
 			if _, err = integrationLoader.LoadResources(); err != nil {
-				logger.Err(err).Msg("Failed to load resources file")
-				validationFailed = true
+				fail(err, RuleResourcesLoad, integrationloader.ResourcesName, "Failed to load resources file")
 			}
 
 			// load & validate logo
 			_, err = integrationLoader.LoadLogo()
 			if err != nil {
-				logger.Err(err).Msg("Failed to load logo")
-				validationFailed = true
+				fail(err, RuleLogoLoad, integrationloader.LogoName, "Failed to load logo")
 			}
 
 			// load & validate readme
 			_, err = integrationLoader.LoadReadme()
 			if err != nil {
-				logger.Err(err).Msg("Failed to load readme")
-				validationFailed = true
+				fail(err, RuleReadmeLoad, integrationloader.ReadmeName, "Failed to load readme")
 			}
 
 			// load & validate changelog
-			_, err = integrationLoader.LoadReadme()
+			changelog, err := integrationLoader.LoadChangelog()
 			if err != nil {
-				logger.Err(err).Msg("Failed to load changelog")
-				validationFailed = true
+				fail(err, RuleChangelogLoad, integrationloader.ChangelogName, "Failed to load changelog")
+			} else if err := validateChangelogMarkdown(changelog, integration); err != nil {
+				fail(err, RuleChangelogInvalid, integrationloader.ChangelogName, "Failed to validate changelog")
 			}
 
 			// load & validate images
 			_, err = integrationLoader.LoadImages()
 			if err != nil {
-				logger.Err(err).Msg("Failed to load images")
-				validationFailed = true
+				fail(err, RuleImagesLoad, integrationloader.ImagesDirName, "Failed to load images")
 			}
 		}
 	}
 
-	if validationFailed {
-		return errors.New("one or more integrations failed validation")
+	if len(failures) > 0 {
+		return integrations, failures
+	}
+	return integrations, nil
+}
+
+func validateChangelogMarkdown(changelog string, version types.IntegrationVersion) error {
+	structuredChangelog, err := catalogv1.ChangelogFromMarkdown([]byte(changelog))
+	if err != nil {
+		return fmt.Errorf("error parsing changelog: %w", err)
+	}
+	return validateChangelog(structuredChangelog, version)
+}
+
+// validateChangelog ensures that a tagged integration version has a matching
+// changelog entry. Integration versions loaded from a path have no tag, and
+// therefore no version to match against, so they are not checked.
+func validateChangelog(changelog catalogv1.Changelog, version types.IntegrationVersion) error {
+	if version.GitTag == "" {
+		return nil
+	}
+	if _, ok := changelog.Entry(version.SemVer()); !ok {
+		return fmt.Errorf("changelog has no entry for version %s", version.SemVer())
 	}
 	return nil
 }
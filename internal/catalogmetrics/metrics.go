@@ -0,0 +1,156 @@
+// Package catalogmetrics collects the Prometheus metrics of the catalog api
+// server: served requests, catalog builds & the release being served.
+package catalogmetrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sensu/catalog-api/internal/catalogquery"
+)
+
+const namespace = "catalog_api"
+
+// Build results used as the value of the result label of builds_total.
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+// Metrics holds the collectors of the server within its own registry, so
+// that multiple servers, e.g. in tests, do not share metrics.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	requests        *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	builds          *prometheus.CounterVec
+	buildDuration   prometheus.Histogram
+	phaseDuration   *prometheus.HistogramVec
+	releaseInfo     *prometheus.GaugeVec
+	integrations    *prometheus.GaugeVec
+	versions        *prometheus.GaugeVec
+}
+
+// New creates the metrics of a server. clients, if not nil, reports the
+// number of connected websocket clients.
+func New(clients func() int) *Metrics {
+	buildBuckets := prometheus.ExponentialBuckets(0.01, 2, 14)
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Number of HTTP requests served, by endpoint class & status code.",
+		}, []string{"class", "code"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Duration of HTTP requests, by endpoint class.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"class"}),
+		builds: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "builds_total",
+			Help:      "Number of catalog builds, by result.",
+		}, []string{"result"}),
+		buildDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "build_duration_seconds",
+			Help:      "Duration of catalog builds.",
+			Buckets:   buildBuckets,
+		}),
+		phaseDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "build_phase_duration_seconds",
+			Help:      "Duration of the phases of catalog builds, by phase.",
+			Buckets:   buildBuckets,
+		}, []string{"phase"}),
+		releaseInfo: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "release_info",
+			Help:      "Checksum of the release being served; always 1.",
+		}, []string{"release_sha256"}),
+		integrations: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "integrations",
+			Help:      "Number of integrations within the release being served, by namespace.",
+		}, []string{"namespace"}),
+		versions: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "integration_versions",
+			Help:      "Number of integration versions within the release being served, by namespace.",
+		}, []string{"namespace"}),
+	}
+
+	m.registry.MustRegister(
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		m.requests,
+		m.requestDuration,
+		m.builds,
+		m.buildDuration,
+		m.phaseDuration,
+		m.releaseInfo,
+		m.integrations,
+		m.versions,
+	)
+	if clients != nil {
+		m.registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "websocket_clients",
+			Help:      "Number of connected websocket clients.",
+		}, func() float64 {
+			return float64(clients())
+		}))
+	}
+	return m
+}
+
+// Handler serves the metrics in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// ObserveRequest records a served request.
+func (m *Metrics) ObserveRequest(class string, status int, duration time.Duration) {
+	m.requests.WithLabelValues(class, strconv.Itoa(status)).Inc()
+	m.requestDuration.WithLabelValues(class).Observe(duration.Seconds())
+}
+
+// ObservePhase records the duration of a phase of a catalog build. It
+// implements catalogmanager.Observer.
+func (m *Metrics) ObservePhase(phase string, duration time.Duration) {
+	m.phaseDuration.WithLabelValues(phase).Observe(duration.Seconds())
+}
+
+// ObserveBuild records a catalog build, which failed if err is not nil.
+func (m *Metrics) ObserveBuild(duration time.Duration, err error) {
+	result := ResultSuccess
+	if err != nil {
+		result = ResultFailure
+	}
+	m.builds.WithLabelValues(result).Inc()
+	m.buildDuration.Observe(duration.Seconds())
+}
+
+// SetRelease records the release being served; the zero summary records that
+// no release is being served.
+func (m *Metrics) SetRelease(summary catalogquery.Summary) {
+	m.releaseInfo.Reset()
+	m.integrations.Reset()
+	m.versions.Reset()
+	if summary.SHA256 == "" {
+		return
+	}
+
+	m.releaseInfo.WithLabelValues(summary.SHA256).Set(1)
+	for namespace, count := range summary.Integrations {
+		m.integrations.WithLabelValues(namespace).Set(float64(count))
+		// namespaces without versions are reported too
+		m.versions.WithLabelValues(namespace).Set(float64(summary.Versions[namespace]))
+	}
+}
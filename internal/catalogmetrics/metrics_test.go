@@ -0,0 +1,76 @@
+package catalogmetrics
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sensu/catalog-api/internal/catalogquery"
+	"github.com/sensu/catalog-api/internal/searchindex"
+)
+
+func scrape(t *testing.T, m *Metrics) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	b, err := io.ReadAll(rec.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestMetrics(t *testing.T) {
+	m := New(func() int { return 3 })
+	m.ObserveRequest("release", 200, 10*time.Millisecond)
+	m.ObserveRequest("release", 200, 20*time.Millisecond)
+	m.ObserveRequest("version", 304, time.Millisecond)
+	m.ObservePhase("checksum", time.Second)
+	m.ObserveBuild(time.Second, nil)
+	m.ObserveBuild(time.Second, errors.New("build failed"))
+	m.ObserveBuild(time.Second, errors.New("build failed"))
+	m.SetRelease(catalogquery.FixtureRelease().Summary())
+
+	body := scrape(t, m)
+	for _, want := range []string{
+		`catalog_api_http_requests_total{class="release",code="200"} 2`,
+		`catalog_api_http_requests_total{class="version",code="304"} 1`,
+		`catalog_api_http_request_duration_seconds_count{class="release"} 2`,
+		`catalog_api_build_phase_duration_seconds_count{phase="checksum"} 1`,
+		`catalog_api_builds_total{result="success"} 1`,
+		`catalog_api_builds_total{result="failure"} 2`,
+		`catalog_api_build_duration_seconds_count 3`,
+		`catalog_api_release_info{release_sha256="abc123"} 1`,
+		`catalog_api_integrations{namespace="nginx"} 1`,
+		`catalog_api_integration_versions{namespace="nginx"} 4`,
+		`catalog_api_integration_versions{namespace="system"} 1`,
+		`catalog_api_websocket_clients 3`,
+		`go_goroutines`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics do not contain %q", want)
+		}
+	}
+}
+
+func TestMetrics_SetRelease(t *testing.T) {
+	m := New(nil)
+	m.SetRelease(catalogquery.FixtureRelease().Summary())
+
+	// a previously served release must not be reported once replaced
+	release := catalogquery.NewRelease("def456", nil, nil, searchindex.Index{})
+	m.SetRelease(release.Summary())
+
+	body := scrape(t, m)
+	if !strings.Contains(body, `catalog_api_release_info{release_sha256="def456"} 1`) {
+		t.Errorf("metrics do not contain the current release:\n%s", body)
+	}
+	for _, unwanted := range []string{"abc123", `namespace="nginx"`, "websocket_clients"} {
+		if strings.Contains(body, unwanted) {
+			t.Errorf("metrics contain %q", unwanted)
+		}
+	}
+}
@@ -0,0 +1,266 @@
+package catalogpublish
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	catalogapiv1 "github.com/sensu/catalog-api/internal/api/catalogapi/v1"
+	"github.com/sensu/catalog-api/internal/catalogquery"
+	"github.com/sensu/catalog-api/internal/endpoints"
+	"github.com/sensu/catalog-api/internal/output"
+	"github.com/sensu/catalog-api/internal/util"
+)
+
+var ErrReleaseNotFound = errors.New("release not found")
+
+type Config struct {
+	// ReleasesDir is the directory releases are published to. It contains the
+	// version & releases endpoints along with a directory for every retained
+	// release.
+	ReleasesDir string
+
+	// KeepLast retains the given number of most recent releases. KeepWithin
+	// retains releases that were created within the given duration. A release
+	// is retained if either applies, and every release is retained if neither
+	// is set. The current release is always retained.
+	KeepLast   int
+	KeepWithin time.Duration
+}
+
+func (c Config) validate() error {
+	if c.ReleasesDir == "" {
+		return errors.New("releases dir must not be empty")
+	}
+	if c.KeepLast < 0 {
+		return errors.New("number of releases to keep must not be negative")
+	}
+	if c.KeepWithin < 0 {
+		return errors.New("duration to keep releases for must not be negative")
+	}
+	return nil
+}
+
+type Publisher struct {
+	config Config
+}
+
+func New(config Config) (Publisher, error) {
+	p := Publisher{
+		config: config,
+	}
+
+	if err := config.validate(); err != nil {
+		return p, fmt.Errorf("publisher config validation failed: %w", err)
+	}
+
+	return p, nil
+}
+
+// History returns the release history of the releases dir. An empty history
+// is returned if no release has been published yet.
+func (p Publisher) History() (catalogapiv1.ReleaseHistory, error) {
+	history := catalogapiv1.ReleaseHistory{
+		Releases: []catalogapiv1.ReleaseInfo{},
+	}
+	historyPath := catalogapiv1.NewReleasesEndpoint(p.config.ReleasesDir, history).GetOutputPath()
+	b, err := os.ReadFile(historyPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return history, nil
+	} else if err != nil {
+		return history, fmt.Errorf("error reading release history: %w", err)
+	}
+	if err := json.Unmarshal(b, &history); err != nil {
+		return history, fmt.Errorf("error unmarshaling release history: %w", err)
+	}
+	return history, nil
+}
+
+// Publish moves the release generated within dir, i.e. the release that the
+// version endpoint within dir points to, to the releases dir, records it in
+// the release history, points the version endpoint of the releases dir at it
+// and removes releases that are no longer retained.
+//
+// The release is completely written & synced before the version endpoint is
+// replaced, so a crash while publishing leaves the version endpoint pointing
+// at either the previous or the new release, both of which are complete.
+func (p Publisher) Publish(dir string, sourceCommit string) (catalogapiv1.ReleaseInfo, error) {
+	now := time.Now()
+	info := catalogapiv1.ReleaseInfo{
+		Published:    now.Unix(),
+		SourceCommit: sourceCommit,
+	}
+
+	version := catalogapiv1.ReleaseVersion{}
+	versionPath := catalogapiv1.NewVersionEndpoint(dir, version).GetOutputPath()
+	if err := readJSON(versionPath, &version); err != nil {
+		return info, fmt.Errorf("error reading version endpoint: %w", err)
+	}
+	info.ReleaseSHA256 = version.ReleaseSHA256
+	info.Created = version.LastUpdated
+	info.PublicKeyID = version.PublicKeyID
+
+	signature := catalogapiv1.Signature{}
+	sigPath := catalogapiv1.NewSignatureEndpoint(versionPath, signature).GetOutputPath()
+	if err := readJSON(sigPath, &signature); err == nil {
+		info.VersionSignature = &signature
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return info, fmt.Errorf("error reading version signature: %w", err)
+	}
+
+	release, err := catalogquery.LoadReleaseChecksum(dir, info.ReleaseSHA256)
+	if err != nil {
+		return info, fmt.Errorf("error loading release: %w", err)
+	}
+	info.Namespaces = len(release.Namespaces())
+	info.Integrations = len(release.Integrations)
+	for _, integration := range release.Integrations {
+		info.Versions += len(release.Versions(integration.Metadata.Namespace, integration.Metadata.Name))
+	}
+
+	history, err := p.History()
+	if err != nil {
+		return info, err
+	}
+
+	// releases are content addressed, so a release that has been published
+	// before does not need to be moved again
+	dstPath := filepath.Join(p.config.ReleasesDir, info.ReleaseSHA256)
+	if _, err := os.Stat(dstPath); errors.Is(err, fs.ErrNotExist) {
+		if err := util.MoveDir(filepath.Join(dir, info.ReleaseSHA256), dstPath); err != nil {
+			return info, fmt.Errorf("error moving release to releases dir: %w", err)
+		}
+	} else if err != nil {
+		return info, err
+	}
+
+	releases := []catalogapiv1.ReleaseInfo{info}
+	for _, r := range history.Releases {
+		if r.ReleaseSHA256 != info.ReleaseSHA256 {
+			releases = append(releases, r)
+		}
+	}
+	history.Releases = releases
+	history.Current = info.ReleaseSHA256
+
+	return info, p.update(history, info, now)
+}
+
+// Rollback points the version endpoint of the releases dir at a previously
+// published release that is still retained.
+func (p Publisher) Rollback(sha256 string) error {
+	history, err := p.History()
+	if err != nil {
+		return err
+	}
+
+	for _, info := range history.Releases {
+		if info.ReleaseSHA256 != sha256 {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(p.config.ReleasesDir, sha256)); err != nil {
+			return fmt.Errorf("error reading release directory: %w", err)
+		}
+
+		history.Current = sha256
+		if err := endpoints.GenerateReleasesEndpoint(p.output(), history); err != nil {
+			return fmt.Errorf("error generating releases endpoint: %w", err)
+		}
+		return p.restoreVersion(info)
+	}
+
+	return fmt.Errorf("%w: %s", ErrReleaseNotFound, sha256)
+}
+
+// update records the release history, applying the retention policy, points
+// the version endpoint at the current release and then removes the
+// directories of releases that are no longer retained.
+func (p Publisher) update(history catalogapiv1.ReleaseHistory, current catalogapiv1.ReleaseInfo, now time.Time) error {
+	retained, removed := p.retain(history, now)
+	history.Releases = retained
+
+	if err := endpoints.GenerateReleasesEndpoint(p.output(), history); err != nil {
+		return fmt.Errorf("error generating releases endpoint: %w", err)
+	}
+	if err := p.restoreVersion(current); err != nil {
+		return err
+	}
+
+	for _, info := range removed {
+		log.Debug().Str("release_sha256", info.ReleaseSHA256).Msg("Removing release")
+		if err := os.RemoveAll(filepath.Join(p.config.ReleasesDir, info.ReleaseSHA256)); err != nil {
+			return fmt.Errorf("error removing release: %w", err)
+		}
+	}
+	return nil
+}
+
+// retain splits the releases of history into those that are retained and
+// those that are not.
+func (p Publisher) retain(history catalogapiv1.ReleaseHistory, now time.Time) (retained []catalogapiv1.ReleaseInfo, removed []catalogapiv1.ReleaseInfo) {
+	retained = []catalogapiv1.ReleaseInfo{}
+	keepAll := p.config.KeepLast == 0 && p.config.KeepWithin == 0
+	for i, info := range history.Releases {
+		age := now.Sub(time.Unix(info.Published, 0))
+		switch {
+		case keepAll,
+			info.ReleaseSHA256 == history.Current,
+			i < p.config.KeepLast,
+			p.config.KeepWithin > 0 && age < p.config.KeepWithin:
+			retained = append(retained, info)
+		default:
+			removed = append(removed, info)
+		}
+	}
+	return retained, removed
+}
+
+// restoreVersion generates the version endpoint, and its signature, exactly
+// as they were generated along with the release.
+func (p Publisher) restoreVersion(info catalogapiv1.ReleaseInfo) error {
+	version := catalogapiv1.ReleaseVersion{
+		ReleaseSHA256: info.ReleaseSHA256,
+		LastUpdated:   info.Created,
+		PublicKeyID:   info.PublicKeyID,
+	}
+	versionName := catalogapiv1.NewVersionEndpoint("", version).GetOutputPath()
+
+	// the signature of the version endpoint is updated first, as verifying a
+	// stale signature fails rather than passing for a different release
+	if info.VersionSignature != nil {
+		if err := endpoints.GenerateSignatureEndpoint(p.output(), versionName, *info.VersionSignature); err != nil {
+			return fmt.Errorf("error generating version signature endpoint: %w", err)
+		}
+	} else {
+		versionPath := catalogapiv1.NewVersionEndpoint(p.config.ReleasesDir, version).GetOutputPath()
+		sigPath := catalogapiv1.NewSignatureEndpoint(versionPath, catalogapiv1.Signature{}).GetOutputPath()
+		if err := os.Remove(sigPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("error removing version signature endpoint: %w", err)
+		}
+	}
+
+	if err := endpoints.GenerateVersionEndpoint(p.output(), version); err != nil {
+		return fmt.Errorf("error generating version endpoint: %w", err)
+	}
+	return nil
+}
+
+// output returns the output that the endpoints of the releases dir are
+// written to.
+func (p Publisher) output() output.Output {
+	return output.NewDir(p.config.ReleasesDir)
+}
+
+func readJSON(path string, v interface{}) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
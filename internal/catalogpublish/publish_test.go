@@ -0,0 +1,218 @@
+package catalogpublish
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	catalogapiv1 "github.com/sensu/catalog-api/internal/api/catalogapi/v1"
+	"github.com/sensu/catalog-api/internal/catalogmanager"
+	"github.com/sensu/catalog-api/internal/catalogmanager/catalogmanagertest"
+	"github.com/sensu/catalog-api/internal/catalogsign"
+	"github.com/sensu/catalog-api/internal/catalogverify"
+	"github.com/sensu/catalog-api/internal/types"
+)
+
+// generateRelease generates a release containing the given versions of a
+// fixture integration and returns the release directory. The release is
+// signed if signingKey is not nil.
+func generateRelease(t *testing.T, signingKey ed25519.PrivateKey, versions ...types.IntegrationVersion) string {
+	t.Helper()
+	config := catalogmanager.Config{SigningKey: signingKey}
+	return catalogmanagertest.GenerateRelease(t, config, types.Integrations(versions))
+}
+
+func currentRelease(t *testing.T, releasesDir string) string {
+	t.Helper()
+	version := catalogapiv1.ReleaseVersion{}
+	if err := readJSON(filepath.Join(releasesDir, "version.json"), &version); err != nil {
+		t.Fatal(err)
+	}
+	return version.ReleaseSHA256
+}
+
+func TestPublisher_Publish(t *testing.T) {
+	releasesDir := t.TempDir()
+	p, err := New(Config{ReleasesDir: releasesDir, KeepLast: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKey, privateKey, err := catalogsign.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var published []catalogapiv1.ReleaseInfo
+	for i := 0; i < 3; i++ {
+		versions := []types.IntegrationVersion{}
+		for minor := 0; minor <= i; minor++ {
+			versions = append(versions, types.FixtureIntegrationVersion("example_ns", "example", 1, minor, 0))
+		}
+		var signingKey ed25519.PrivateKey
+		if i == 2 {
+			signingKey = privateKey
+		}
+		info, err := p.Publish(generateRelease(t, signingKey, versions...), "abc123")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Namespaces != 1 || info.Integrations != 1 || info.Versions != i+1 {
+			t.Errorf("Publish() counts = %d/%d/%d, want 1/1/%d", info.Namespaces, info.Integrations, info.Versions, i+1)
+		}
+		if info.SourceCommit != "abc123" {
+			t.Errorf("Publish() source commit = %v, want abc123", info.SourceCommit)
+		}
+		if got := currentRelease(t, releasesDir); got != info.ReleaseSHA256 {
+			t.Errorf("version.json release = %v, want %v", got, info.ReleaseSHA256)
+		}
+		published = append(published, info)
+	}
+
+	// only the last 2 releases are retained
+	history, err := p.History()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := catalogapiv1.ReleaseHistory{
+		Current:  published[2].ReleaseSHA256,
+		Releases: []catalogapiv1.ReleaseInfo{published[2], published[1]},
+	}
+	if !reflect.DeepEqual(history, want) {
+		t.Errorf("History() = %+v, want %+v", history, want)
+	}
+	if _, err := os.Stat(filepath.Join(releasesDir, published[0].ReleaseSHA256)); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("release %s was not removed", published[0].ReleaseSHA256)
+	}
+	if published[2].VersionSignature == nil {
+		t.Fatalf("Publish() did not record the version signature")
+	}
+
+	// roll back to the unsigned release
+	if err := p.Rollback(published[1].ReleaseSHA256); err != nil {
+		t.Fatal(err)
+	}
+	if got := currentRelease(t, releasesDir); got != published[1].ReleaseSHA256 {
+		t.Errorf("version.json release = %v, want %v", got, published[1].ReleaseSHA256)
+	}
+	if _, err := os.Stat(filepath.Join(releasesDir, "version.json.sig")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("version.json.sig of the signed release was not removed")
+	}
+	if result, err := catalogverify.Verify(releasesDir, nil); err != nil || !result.OK() {
+		t.Errorf("Verify() = %v, %v after rollback", result.Failures, err)
+	}
+
+	// roll forward to the signed release, whose signature must still verify
+	if err := p.Rollback(published[2].ReleaseSHA256); err != nil {
+		t.Fatal(err)
+	}
+	if result, err := catalogverify.Verify(releasesDir, publicKey); err != nil || !result.OK() {
+		t.Errorf("Verify() = %v, %v after rollback", result.Failures, err)
+	}
+
+	if err := p.Rollback(published[0].ReleaseSHA256); !errors.Is(err, ErrReleaseNotFound) {
+		t.Errorf("Rollback() of a removed release error = %v, want %v", err, ErrReleaseNotFound)
+	}
+}
+
+func TestPublisher_Publish_reproducible(t *testing.T) {
+	releasesDir := t.TempDir()
+	p, err := New(Config{ReleasesDir: releasesDir, KeepWithin: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// reproducible releases are dated by their source, which may be long
+	// before they are published
+	config := catalogmanager.Config{Timestamp: time.Unix(0, 0)}
+	var published []catalogapiv1.ReleaseInfo
+	for minor := 0; minor < 2; minor++ {
+		version := types.FixtureIntegrationVersion("example_ns", "example", 1, minor, 0)
+		dir := catalogmanagertest.GenerateRelease(t, config, types.Integrations{version})
+		info, err := p.Publish(dir, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Created != 0 {
+			t.Errorf("Publish() created = %v, want 0", info.Created)
+		}
+		if age := time.Since(time.Unix(info.Published, 0)); age < 0 || age > time.Minute {
+			t.Errorf("Publish() published = %v, want the current time", info.Published)
+		}
+		published = append(published, info)
+	}
+
+	// both releases were published within the last hour
+	history, err := p.History()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history.Releases) != 2 {
+		t.Errorf("History() = %+v, want both releases", history.Releases)
+	}
+	if _, err := os.Stat(filepath.Join(releasesDir, published[0].ReleaseSHA256)); err != nil {
+		t.Errorf("release %s was removed: %v", published[0].ReleaseSHA256, err)
+	}
+}
+
+func TestPublisher_retain(t *testing.T) {
+	now := time.Unix(1000000, 0)
+	hoursAgo := func(h int) int64 {
+		return now.Add(-time.Duration(h) * time.Hour).Unix()
+	}
+	history := catalogapiv1.ReleaseHistory{
+		Current: "d",
+		Releases: []catalogapiv1.ReleaseInfo{
+			{ReleaseSHA256: "a", Published: hoursAgo(1)},
+			{ReleaseSHA256: "b", Published: hoursAgo(2)},
+			{ReleaseSHA256: "c", Published: hoursAgo(30)},
+			{ReleaseSHA256: "d", Published: hoursAgo(40)},
+			{ReleaseSHA256: "e", Published: hoursAgo(50)},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		config Config
+		want   []string
+	}{
+		{
+			name: "keep all",
+			want: []string{"a", "b", "c", "d", "e"},
+		},
+		{
+			name:   "keep last",
+			config: Config{KeepLast: 1},
+			want:   []string{"a", "d"},
+		},
+		{
+			name:   "keep within",
+			config: Config{KeepWithin: 24 * time.Hour},
+			want:   []string{"a", "b", "d"},
+		},
+		{
+			name:   "keep last or within",
+			config: Config{KeepLast: 3, KeepWithin: 24 * time.Hour},
+			want:   []string{"a", "b", "c", "d"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := Publisher{config: tt.config}
+			retained, removed := p.retain(history, now)
+			got := []string{}
+			for _, info := range retained {
+				got = append(got, info.ReleaseSHA256)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("retain() = %v, want %v", got, tt.want)
+			}
+			if len(retained)+len(removed) != len(history.Releases) {
+				t.Errorf("retain() removed = %v", removed)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package catalogquery
+
+import (
+	"encoding/json"
+
+	catalogv1 "github.com/sensu/catalog-api/internal/api/catalog/v1"
+	catalogapiv1 "github.com/sensu/catalog-api/internal/api/catalogapi/v1"
+	"github.com/sensu/catalog-api/internal/searchindex"
+)
+
+// FixtureRelease returns a release containing three integrations, one of
+// which has multiple versions including a prerelease.
+func FixtureRelease() *Release {
+	nginx := catalogv1.FixtureIntegration("nginx", "nginx-monitoring")
+	nginx.DisplayName = "NGINX Monitoring"
+	nginx.Tags = []string{"http", "webserver"}
+	nginx.Provider = "monitoring"
+
+	host := catalogv1.FixtureIntegration("system", "host-monitoring")
+	host.DisplayName = "Host Monitoring"
+	host.SupportedPlatforms = []string{"linux", "darwin", "windows"}
+
+	pagerduty := catalogv1.FixtureIntegration("pagerduty", "pagerduty-incidents")
+	pagerduty.DisplayName = "PagerDuty"
+	pagerduty.Class = "supported"
+	pagerduty.Provider = "incidents"
+
+	integrations := []catalogapiv1.IntegrationWithVersions{
+		{Integration: nginx, Version: "2.0.0-beta.1", Versions: []string{"1.2.0", "1.2.5", "1.3.0", "2.0.0-beta.1"}},
+		{Integration: host, Version: "20220125.0.0", Versions: []string{"20220125.0.0"}},
+		{Integration: pagerduty, Version: "0.1.0", Versions: []string{"0.1.0"}},
+	}
+
+	builder := searchindex.NewBuilder(searchindex.Field{Name: "display_name", Boost: 10}, searchindex.Field{Name: "tags"})
+	for _, integration := range integrations {
+		builder.Add(integrationRef(integration), map[string]string{
+			"display_name": integration.DisplayName,
+		})
+	}
+
+	versions := map[string][]Version{}
+	for _, integration := range integrations {
+		ref := integrationRef(integration)
+		for _, v := range integration.Versions {
+			iv := catalogapiv1.IntegrationVersion{
+				Integration: integration.Integration,
+				Version:     v,
+			}
+			versions[ref] = append(versions[ref], Version{
+				IntegrationVersion: iv,
+				Resources:          catalogv1.Resources{{"type": "CheckConfig"}},
+				Readme:             "# " + integration.DisplayName,
+				Dashboards:         []Asset{},
+				Images:             []Asset{},
+			})
+		}
+	}
+	nginx130 := &versions["nginx/nginx-monitoring"][2]
+	nginx130.Dashboards = []Asset{{
+		Name: "nginx.json",
+		Path: catalogapiv1.NewIntegrationVersionDashboardEndpoint("/abc123", nginx130.IntegrationVersion, "nginx.json", "").GetOutputPath(),
+		Data: json.RawMessage(`{"title":"NGINX"}`),
+	}}
+	nginx130.Dashboards[0].SHA256 = checksum(nginx130.Dashboards[0].Data)
+
+	return NewRelease("abc123", integrations, versions, builder.Build())
+}
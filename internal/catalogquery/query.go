@@ -0,0 +1,205 @@
+package catalogquery
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	catalogapiv1 "github.com/sensu/catalog-api/internal/api/catalogapi/v1"
+)
+
+const (
+	SortRelevance   = "relevance"
+	SortName        = "name"
+	SortDisplayName = "display_name"
+
+	defaultPerPage = 20
+	maxPerPage     = 100
+)
+
+// Query filters, searches, sorts & paginates the integrations of a release.
+// All of the filters must match for an integration to be included.
+type Query struct {
+	Tags       []string
+	Provider   string
+	Class      string
+	Platform   string
+	Text       string
+	Sort       string
+	Descending bool
+	Page       int
+	PerPage    int
+}
+
+// ParseQuery builds a query from the query string parameters tag, provider,
+// class, platform, q, sort, page & per_page. The tag parameter may be
+// specified multiple times. Sort may be prefixed with "-" to reverse the
+// order.
+func ParseQuery(values url.Values) (Query, error) {
+	query := Query{
+		Tags:     values["tag"],
+		Provider: values.Get("provider"),
+		Class:    values.Get("class"),
+		Platform: values.Get("platform"),
+		Text:     strings.TrimSpace(values.Get("q")),
+		Page:     1,
+		PerPage:  defaultPerPage,
+	}
+
+	query.Sort = values.Get("sort")
+	if strings.HasPrefix(query.Sort, "-") {
+		query.Sort = strings.TrimPrefix(query.Sort, "-")
+		query.Descending = true
+	}
+	if query.Sort == "" {
+		query.Sort = SortName
+		if query.Text != "" {
+			query.Sort = SortRelevance
+		}
+	}
+	if !isValidSort(query.Sort) {
+		return query, fmt.Errorf("sort must be one of %s, got: %s", validSorts(), query.Sort)
+	}
+
+	if page := values.Get("page"); page != "" {
+		n, err := strconv.Atoi(page)
+		if err != nil || n < 1 {
+			return query, fmt.Errorf("page must be a positive integer, got: %s", page)
+		}
+		query.Page = n
+	}
+	if perPage := values.Get("per_page"); perPage != "" {
+		n, err := strconv.Atoi(perPage)
+		if err != nil || n < 1 || n > maxPerPage {
+			return query, fmt.Errorf("per_page must be an integer between 1 and %d, got: %s", maxPerPage, perPage)
+		}
+		query.PerPage = n
+	}
+
+	return query, nil
+}
+
+type Result struct {
+	ReleaseSHA256 string              `json:"release_sha256"`
+	Total         int                 `json:"total"`
+	Page          int                 `json:"page"`
+	PerPage       int                 `json:"per_page"`
+	Integrations  []ResultIntegration `json:"integrations"`
+}
+
+type ResultIntegration struct {
+	catalogapiv1.IntegrationWithVersions
+	Score float64 `json:"score,omitempty"`
+}
+
+func (r *Release) Query(query Query) Result {
+	var scores map[string]float64
+	if query.Text != "" {
+		scores = r.SearchIndex.Search(query.Text)
+	}
+
+	matches := []ResultIntegration{}
+	for _, integration := range r.Integrations {
+		if !query.matches(integration) {
+			continue
+		}
+		match := ResultIntegration{
+			IntegrationWithVersions: integration,
+		}
+		if scores != nil {
+			score, ok := scores[integrationRef(integration)]
+			if !ok {
+				continue
+			}
+			match.Score = score
+		}
+		matches = append(matches, match)
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if query.Descending {
+			a, b = b, a
+		}
+		switch query.Sort {
+		case SortRelevance:
+			if a.Score != b.Score {
+				// higher scores first
+				return a.Score > b.Score
+			}
+		case SortDisplayName:
+			an, bn := strings.ToLower(a.DisplayName), strings.ToLower(b.DisplayName)
+			if an != bn {
+				return an < bn
+			}
+		}
+		return integrationRef(a.IntegrationWithVersions) < integrationRef(b.IntegrationWithVersions)
+	})
+
+	result := Result{
+		ReleaseSHA256: r.SHA256,
+		Total:         len(matches),
+		Page:          query.Page,
+		PerPage:       query.PerPage,
+		Integrations:  []ResultIntegration{},
+	}
+	// pages past the last one are empty; the page is compared before it is
+	// multiplied so that a large page can't overflow the offset
+	if query.Page-1 < len(matches)/query.PerPage+1 {
+		start := (query.Page - 1) * query.PerPage
+		if start < len(matches) {
+			end := start + query.PerPage
+			if end > len(matches) {
+				end = len(matches)
+			}
+			result.Integrations = matches[start:end]
+		}
+	}
+	return result
+}
+
+func (q Query) matches(integration catalogapiv1.IntegrationWithVersions) bool {
+	for _, tag := range q.Tags {
+		if !contains(integration.Tags, tag) {
+			return false
+		}
+	}
+	if q.Provider != "" && integration.Provider != q.Provider {
+		return false
+	}
+	if q.Class != "" && integration.Class != q.Class {
+		return false
+	}
+	if q.Platform != "" && !contains(integration.SupportedPlatforms, q.Platform) {
+		return false
+	}
+	return true
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func validSorts() []string {
+	return []string{
+		SortRelevance,
+		SortName,
+		SortDisplayName,
+	}
+}
+
+func isValidSort(sort string) bool {
+	for _, s := range validSorts() {
+		if s == sort {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,249 @@
+package catalogquery
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	catalogapiv1 "github.com/sensu/catalog-api/internal/api/catalogapi/v1"
+)
+
+func resultRefs(result Result) []string {
+	refs := []string{}
+	for _, integration := range result.Integrations {
+		refs = append(refs, integrationRef(integration.IntegrationWithVersions))
+	}
+	return refs
+}
+
+func TestRelease_Query(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		want      []string
+		wantTotal int
+		wantErr   bool
+	}{
+		{
+			name:      "no filters",
+			query:     "",
+			want:      []string{"nginx/nginx-monitoring", "pagerduty/pagerduty-incidents", "system/host-monitoring"},
+			wantTotal: 3,
+		},
+		{
+			name:      "tag",
+			query:     "tag=http",
+			want:      []string{"nginx/nginx-monitoring"},
+			wantTotal: 1,
+		},
+		{
+			name:      "multiple tags must all match",
+			query:     "tag=http&tag=tag1",
+			want:      []string{},
+			wantTotal: 0,
+		},
+		{
+			name:      "provider, class & platform",
+			query:     "provider=alerts&class=community&platform=windows",
+			want:      []string{"system/host-monitoring"},
+			wantTotal: 1,
+		},
+		{
+			name:      "full text search sorted by relevance",
+			query:     "q=monitoring",
+			want:      []string{"nginx/nginx-monitoring", "system/host-monitoring"},
+			wantTotal: 2,
+		},
+		{
+			name:      "sort by display name descending",
+			query:     "sort=-display_name",
+			want:      []string{"pagerduty/pagerduty-incidents", "nginx/nginx-monitoring", "system/host-monitoring"},
+			wantTotal: 3,
+		},
+		{
+			name:      "pagination",
+			query:     "page=2&per_page=2",
+			want:      []string{"system/host-monitoring"},
+			wantTotal: 3,
+		},
+		{
+			name:      "page out of range",
+			query:     "page=3&per_page=2",
+			want:      []string{},
+			wantTotal: 3,
+		},
+		{
+			name:      "page overflowing the offset",
+			query:     "page=4611686018427387905&per_page=2",
+			want:      []string{},
+			wantTotal: 3,
+		},
+		{
+			name:    "invalid sort",
+			query:   "sort=foo",
+			wantErr: true,
+		},
+		{
+			name:    "invalid page",
+			query:   "page=0",
+			wantErr: true,
+		},
+	}
+	release := FixtureRelease()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			query, err := ParseQuery(values)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseQuery() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			result := release.Query(query)
+			if result.Total != tt.wantTotal {
+				t.Errorf("Release.Query() total = %v, want %v", result.Total, tt.wantTotal)
+			}
+			if got := resultRefs(result); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Release.Query() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRelease_QueryRelevance(t *testing.T) {
+	query, err := ParseQuery(url.Values{"q": {"nginx monitoring"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := FixtureRelease().Query(query)
+	want := []string{"nginx/nginx-monitoring", "system/host-monitoring"}
+	if got := resultRefs(result); !reflect.DeepEqual(got, want) {
+		t.Errorf("Release.Query() = %v, want %v", got, want)
+	}
+}
+
+func TestRelease_Resolve(t *testing.T) {
+	tests := []struct {
+		name       string
+		namespace  string
+		constraint string
+		want       string
+		wantErr    error
+	}{
+		{name: "latest", namespace: "nginx", constraint: "", want: "2.0.0-beta.1"},
+		{name: "any stable", namespace: "nginx", constraint: "*", want: "1.3.0"},
+		{name: "caret", namespace: "nginx", constraint: "^1.2", want: "1.3.0"},
+		{name: "tilde", namespace: "nginx", constraint: "~1.2.0", want: "1.2.5"},
+		{name: "prerelease", namespace: "nginx", constraint: ">= 2.0.0-0", want: "2.0.0-beta.1"},
+		{name: "no match", namespace: "nginx", constraint: "^3", wantErr: ErrNoMatchingVersion},
+		{name: "not found", namespace: "foo", constraint: "", wantErr: ErrIntegrationNotFound},
+	}
+	release := FixtureRelease()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := release.Resolve(tt.namespace, "nginx-monitoring", tt.constraint)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Release.Resolve() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got.Version != tt.want {
+				t.Errorf("Release.Resolve() = %v, want %v", got.Version, tt.want)
+			}
+		})
+	}
+}
+
+func TestStore_Reload(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir)
+
+	if err := store.Reload(); err == nil {
+		t.Fatal("Store.Reload() expected error for empty dir")
+	}
+	if store.Release() != nil {
+		t.Fatal("Store.Release() expected nil release")
+	}
+
+	writeFile := func(path string, b []byte) {
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, b, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeJSON := func(path string, v interface{}) {
+		b, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		writeFile(path, b)
+	}
+
+	fixture := FixtureRelease()
+	releaseDir := filepath.Join(dir, fixture.SHA256)
+	writeJSON(catalogapiv1.NewVersionEndpoint(dir, catalogapiv1.ReleaseVersion{}).GetOutputPath(), catalogapiv1.ReleaseVersion{
+		ReleaseSHA256: fixture.SHA256,
+	})
+	catalog := catalogapiv1.Catalog{
+		NamespacedIntegrations: map[string][]catalogapiv1.IntegrationVersion{},
+	}
+	for _, integration := range fixture.Integrations {
+		ns := integration.Metadata.Namespace
+		catalog.NamespacedIntegrations[ns] = append(catalog.NamespacedIntegrations[ns], catalogapiv1.IntegrationVersion{
+			Integration: integration.Integration,
+			Version:     integration.Version,
+		})
+		writeJSON(catalogapiv1.NewIntegrationEndpoint(releaseDir, integration).GetOutputPath(), integration)
+	}
+	for _, integration := range fixture.Integrations {
+		for _, version := range fixture.Versions(integration.Metadata.Namespace, integration.Metadata.Name) {
+			iv := version.IntegrationVersion
+			writeJSON(catalogapiv1.NewIntegrationVersionEndpoint(releaseDir, iv).GetOutputPath(), iv)
+			writeJSON(catalogapiv1.NewIntegrationVersionResourcesEndpoint(releaseDir, iv, "").GetOutputPath(), version.Resources)
+			writeJSON(catalogapiv1.NewIntegrationVersionChangelogJSONEndpoint(releaseDir, iv, version.Changelog).GetOutputPath(), version.Changelog)
+			writeFile(catalogapiv1.NewIntegrationVersionReadmeEndpoint(releaseDir, iv, "").GetOutputPath(), []byte(version.Readme))
+			for _, dashboard := range version.Dashboards {
+				writeFile(catalogapiv1.NewIntegrationVersionDashboardEndpoint(releaseDir, iv, dashboard.Name, "").GetOutputPath(), dashboard.Data)
+			}
+		}
+	}
+	writeJSON(catalogapiv1.NewCatalogEndpoint(releaseDir, catalog).GetOutputPath(), catalog)
+	writeJSON(catalogapiv1.NewSearchIndexEndpoint(releaseDir, fixture.SearchIndex).GetOutputPath(), fixture.SearchIndex)
+
+	if err := store.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	release := store.Release()
+	if release == nil {
+		t.Fatal("Store.Release() expected release")
+	}
+	if !reflect.DeepEqual(release.Integrations, fixture.Integrations) {
+		t.Errorf("Store.Release() integrations = %v, want %v", release.Integrations, fixture.Integrations)
+	}
+
+	summary, err := LoadSummary(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := fixture.Summary(); !reflect.DeepEqual(summary, want) {
+		t.Errorf("LoadSummary() = %v, want %v", summary, want)
+	}
+	for _, integration := range fixture.Integrations {
+		namespace, name := integration.Metadata.Namespace, integration.Metadata.Name
+		if got, want := release.Versions(namespace, name), fixture.Versions(namespace, name); !reflect.DeepEqual(got, want) {
+			t.Errorf("Store.Release() versions = %v, want %v", got, want)
+		}
+	}
+	if got := release.SearchIndex.Search("pagerduty"); len(got) != 1 {
+		t.Errorf("Store.Release() search index = %v, want 1 result", got)
+	}
+}
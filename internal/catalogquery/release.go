@@ -0,0 +1,199 @@
+package catalogquery
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	catalogapiv1 "github.com/sensu/catalog-api/internal/api/catalogapi/v1"
+	"github.com/sensu/catalog-api/internal/searchindex"
+)
+
+// Release is an in-memory representation of a generated catalog release,
+// which is used to answer queries that cannot be served by static files.
+type Release struct {
+	SHA256       string
+	Integrations []catalogapiv1.IntegrationWithVersions
+	SearchIndex  searchindex.Index
+
+	// versions maps "<namespace>/<name>" to every version of the integration
+	versions map[string][]Version
+}
+
+func NewRelease(sha256 string, integrations []catalogapiv1.IntegrationWithVersions, versions map[string][]Version, index searchindex.Index) *Release {
+	sort.Slice(integrations, func(i, j int) bool {
+		return integrationRef(integrations[i]) < integrationRef(integrations[j])
+	})
+	for _, ivs := range versions {
+		sortVersions(ivs)
+	}
+	return &Release{
+		SHA256:       sha256,
+		Integrations: integrations,
+		SearchIndex:  index,
+		versions:     versions,
+	}
+}
+
+// Namespaces returns the sorted list of namespaces within the release.
+func (r *Release) Namespaces() []string {
+	namespaces := []string{}
+	seen := map[string]bool{}
+	for _, integration := range r.Integrations {
+		namespace := integration.Metadata.Namespace
+		if !seen[namespace] {
+			seen[namespace] = true
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// Integration returns the latest version of an integration.
+func (r *Release) Integration(namespace, name string) (catalogapiv1.IntegrationWithVersions, bool) {
+	for _, integration := range r.Integrations {
+		if integration.Metadata.Namespace == namespace && integration.Metadata.Name == name {
+			return integration, true
+		}
+	}
+	return catalogapiv1.IntegrationWithVersions{}, false
+}
+
+// Versions returns every version of an integration, lowest first.
+func (r *Release) Versions(namespace, name string) []Version {
+	return r.versions[namespace+"/"+name]
+}
+
+// LoadRelease loads the release that the version endpoint within dir points
+// to.
+func LoadRelease(dir string) (*Release, error) {
+	version := catalogapiv1.ReleaseVersion{}
+	versionPath := catalogapiv1.NewVersionEndpoint(dir, version).GetOutputPath()
+	if err := readJSON(versionPath, &version); err != nil {
+		return nil, err
+	}
+	return LoadReleaseChecksum(dir, version.ReleaseSHA256)
+}
+
+// LoadReleaseChecksum loads the release with the given checksum from dir,
+// regardless of the release that the version endpoint points to.
+func LoadReleaseChecksum(dir string, sha256 string) (*Release, error) {
+	releaseDir := filepath.Join(dir, sha256)
+
+	catalog := catalogapiv1.Catalog{}
+	catalogPath := catalogapiv1.NewCatalogEndpoint(releaseDir, catalog).GetOutputPath()
+	if err := readJSON(catalogPath, &catalog); err != nil {
+		return nil, err
+	}
+
+	integrations := []catalogapiv1.IntegrationWithVersions{}
+	versions := map[string][]Version{}
+	for _, nsIntegrations := range catalog.NamespacedIntegrations {
+		for _, iv := range nsIntegrations {
+			integration := catalogapiv1.IntegrationWithVersions{
+				Integration: iv.Integration,
+			}
+			integrationPath := catalogapiv1.NewIntegrationEndpoint(releaseDir, integration).GetOutputPath()
+			if err := readJSON(integrationPath, &integration); err != nil {
+				return nil, err
+			}
+			integrations = append(integrations, integration)
+
+			ref := integrationRef(integration)
+			for _, v := range integration.Versions {
+				version, err := loadVersion(dir, sha256, integration, v)
+				if err != nil {
+					return nil, err
+				}
+				versions[ref] = append(versions[ref], version)
+			}
+		}
+	}
+
+	index := searchindex.Index{}
+	indexPath := catalogapiv1.NewSearchIndexEndpoint(releaseDir, index).GetOutputPath()
+	if err := readJSON(indexPath, &index); err != nil {
+		return nil, err
+	}
+
+	return NewRelease(sha256, integrations, versions, index), nil
+}
+
+// Summary counts the integrations & integration versions of a release by
+// namespace.
+type Summary struct {
+	SHA256       string
+	Integrations map[string]int
+	Versions     map[string]int
+}
+
+// Summary summarizes the release.
+func (r *Release) Summary() Summary {
+	summary := Summary{
+		SHA256:       r.SHA256,
+		Integrations: map[string]int{},
+		Versions:     map[string]int{},
+	}
+	for _, integration := range r.Integrations {
+		namespace := integration.Metadata.Namespace
+		summary.Integrations[namespace]++
+		summary.Versions[namespace] += len(r.Versions(namespace, integration.Metadata.Name))
+	}
+	return summary
+}
+
+// LoadSummary summarizes the release that the version endpoint within dir
+// points to. Only the catalog & integration endpoints are read, which is much
+// cheaper than loading the release.
+func LoadSummary(dir string) (Summary, error) {
+	version := catalogapiv1.ReleaseVersion{}
+	versionPath := catalogapiv1.NewVersionEndpoint(dir, version).GetOutputPath()
+	if err := readJSON(versionPath, &version); err != nil {
+		return Summary{}, err
+	}
+	releaseDir := filepath.Join(dir, version.ReleaseSHA256)
+
+	catalog := catalogapiv1.Catalog{}
+	catalogPath := catalogapiv1.NewCatalogEndpoint(releaseDir, catalog).GetOutputPath()
+	if err := readJSON(catalogPath, &catalog); err != nil {
+		return Summary{}, err
+	}
+
+	summary := Summary{
+		SHA256:       version.ReleaseSHA256,
+		Integrations: map[string]int{},
+		Versions:     map[string]int{},
+	}
+	for namespace, nsIntegrations := range catalog.NamespacedIntegrations {
+		for _, iv := range nsIntegrations {
+			integration := catalogapiv1.IntegrationWithVersions{
+				Integration: iv.Integration,
+			}
+			integrationPath := catalogapiv1.NewIntegrationEndpoint(releaseDir, integration).GetOutputPath()
+			if err := readJSON(integrationPath, &integration); err != nil {
+				return Summary{}, err
+			}
+			summary.Integrations[namespace]++
+			summary.Versions[namespace] += len(integration.Versions)
+		}
+	}
+	return summary, nil
+}
+
+func readJSON(path string, v interface{}) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading release file: %w", err)
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("error unmarshaling %s: %w", path, err)
+	}
+	return nil
+}
+
+func integrationRef(integration catalogapiv1.IntegrationWithVersions) string {
+	return integration.Metadata.Namespace + "/" + integration.Metadata.Name
+}
@@ -0,0 +1,78 @@
+package catalogquery
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	semver "github.com/Masterminds/semver/v3"
+)
+
+var (
+	ErrIntegrationNotFound = errors.New("integration not found")
+	ErrNoMatchingVersion   = errors.New("no version matches constraint")
+)
+
+type Resolution struct {
+	Namespace  string   `json:"namespace"`
+	Name       string   `json:"name"`
+	Constraint string   `json:"constraint,omitempty"`
+	Version    string   `json:"version"`
+	Versions   []string `json:"versions"`
+}
+
+// Resolve determines the highest version of an integration that satisfies
+// the given semver constraint, e.g. "^1.2" or ">= 20220125.0.0". An empty
+// constraint resolves to the latest version, including prereleases, which is
+// consistent with the version listed in the catalog; use "*" for the latest
+// stable version. All satisfying versions are included in the resolution,
+// highest first.
+func (r *Release) Resolve(namespace, name, constraint string) (Resolution, error) {
+	resolution := Resolution{
+		Namespace:  namespace,
+		Name:       name,
+		Constraint: constraint,
+		Versions:   []string{},
+	}
+
+	var c *semver.Constraints
+	if constraint != "" {
+		var err error
+		c, err = semver.NewConstraint(constraint)
+		if err != nil {
+			return resolution, fmt.Errorf("invalid constraint %s: %w", constraint, err)
+		}
+	}
+
+	found := false
+	matches := semver.Collection{}
+	for _, integration := range r.Integrations {
+		if integration.Metadata.Namespace != namespace || integration.Metadata.Name != name {
+			continue
+		}
+		found = true
+		for _, version := range integration.Versions {
+			v, err := semver.NewVersion(version)
+			if err != nil {
+				return resolution, fmt.Errorf("invalid version %s: %w", version, err)
+			}
+			if c == nil || c.Check(v) {
+				matches = append(matches, v)
+			}
+		}
+	}
+	if !found {
+		return resolution, fmt.Errorf("%w: %s/%s", ErrIntegrationNotFound, namespace, name)
+	}
+	if len(matches) == 0 {
+		return resolution, fmt.Errorf("%w: %s", ErrNoMatchingVersion, constraint)
+	}
+
+	sort.Sort(sort.Reverse(matches))
+	for _, v := range matches {
+		resolution.Versions = append(resolution.Versions, v.Original())
+	}
+	resolution.Version = resolution.Versions[0]
+
+	return resolution, nil
+}
@@ -0,0 +1,42 @@
+package catalogquery
+
+import (
+	"sync"
+)
+
+// Store holds the release currently being served. Reloading swaps the
+// release atomically, so queries in flight continue to use the release they
+// started with.
+type Store struct {
+	dir     string
+	mu      sync.RWMutex
+	release *Release
+}
+
+func NewStore(dir string) *Store {
+	return &Store{
+		dir: dir,
+	}
+}
+
+// Reload loads the release from the store's directory. The previously loaded
+// release is kept if loading fails.
+func (s *Store) Reload() error {
+	release, err := LoadRelease(s.dir)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.release = release
+	return nil
+}
+
+// Release returns the currently loaded release, or nil if no release has
+// been loaded.
+func (s *Store) Release() *Release {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.release
+}
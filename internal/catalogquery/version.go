@@ -0,0 +1,148 @@
+package catalogquery
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	semver "github.com/Masterminds/semver/v3"
+	catalogv1 "github.com/sensu/catalog-api/internal/api/catalog/v1"
+	catalogapiv1 "github.com/sensu/catalog-api/internal/api/catalogapi/v1"
+)
+
+// Version is a single version of an integration along with the contents of
+// its version specific endpoints.
+type Version struct {
+	catalogapiv1.IntegrationVersion
+	Resources  catalogv1.Resources
+	Readme     string
+	Changelog  catalogv1.Changelog
+	Logo       *Asset
+	Dashboards []Asset
+	Images     []Asset
+}
+
+// Asset is a file belonging to an integration version. Path is the URL path
+// of the file relative to the root of the catalog API. Data is only loaded
+// for dashboards.
+type Asset struct {
+	Name   string
+	Path   string
+	SHA256 string
+	Data   json.RawMessage
+}
+
+func loadVersion(dir string, releaseSHA256 string, integration catalogapiv1.IntegrationWithVersions, v string) (Version, error) {
+	releaseDir := filepath.Join(dir, releaseSHA256)
+	urlBase := "/" + releaseSHA256
+
+	iv := catalogapiv1.IntegrationVersion{
+		Integration: integration.Integration,
+		Version:     v,
+	}
+	version := Version{
+		IntegrationVersion: iv,
+	}
+
+	versionPath := catalogapiv1.NewIntegrationVersionEndpoint(releaseDir, iv).GetOutputPath()
+	if err := readJSON(versionPath, &version.IntegrationVersion); err != nil {
+		return version, err
+	}
+
+	resourcesPath := catalogapiv1.NewIntegrationVersionResourcesEndpoint(releaseDir, iv, "").GetOutputPath()
+	if err := readJSON(resourcesPath, &version.Resources); err != nil {
+		return version, err
+	}
+
+	readmePath := catalogapiv1.NewIntegrationVersionReadmeEndpoint(releaseDir, iv, "").GetOutputPath()
+	readme, err := os.ReadFile(readmePath)
+	if err != nil {
+		return version, fmt.Errorf("error reading release file: %w", err)
+	}
+	version.Readme = string(readme)
+
+	changelogPath := catalogapiv1.NewIntegrationVersionChangelogJSONEndpoint(releaseDir, iv, catalogv1.Changelog{}).GetOutputPath()
+	if err := readJSON(changelogPath, &version.Changelog); err != nil {
+		return version, err
+	}
+
+	logoPath := catalogapiv1.NewIntegrationVersionLogoEndpoint(releaseDir, iv, "").GetOutputPath()
+	if logo, err := os.ReadFile(logoPath); err == nil {
+		version.Logo = &Asset{
+			Name:   filepath.Base(logoPath),
+			Path:   catalogapiv1.NewIntegrationVersionLogoEndpoint(urlBase, iv, "").GetOutputPath(),
+			SHA256: checksum(logo),
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return version, fmt.Errorf("error reading release file: %w", err)
+	}
+
+	// the directory is determined from the path of a placeholder file
+	dashboardsDir := filepath.Dir(catalogapiv1.NewIntegrationVersionDashboardEndpoint(releaseDir, iv, "_", "").GetOutputPath())
+	version.Dashboards, err = loadAssets(dashboardsDir, func(name string) string {
+		return catalogapiv1.NewIntegrationVersionDashboardEndpoint(urlBase, iv, name, "").GetOutputPath()
+	}, true)
+	if err != nil {
+		return version, err
+	}
+
+	imagesDir := filepath.Dir(catalogapiv1.NewIntegrationVersionImageEndpoint(releaseDir, iv, "_", "").GetOutputPath())
+	version.Images, err = loadAssets(imagesDir, func(name string) string {
+		return catalogapiv1.NewIntegrationVersionImageEndpoint(urlBase, iv, name, "").GetOutputPath()
+	}, false)
+	if err != nil {
+		return version, err
+	}
+
+	return version, nil
+}
+
+func loadAssets(dir string, urlPath func(string) string, withData bool) ([]Asset, error) {
+	assets := []Asset{}
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return assets, nil
+	} else if err != nil {
+		return assets, fmt.Errorf("error reading release directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return assets, fmt.Errorf("error reading release file: %w", err)
+		}
+		asset := Asset{
+			Name:   entry.Name(),
+			Path:   urlPath(entry.Name()),
+			SHA256: checksum(data),
+		}
+		if withData {
+			asset.Data = data
+		}
+		assets = append(assets, asset)
+	}
+	return assets, nil
+}
+
+func checksum(data []byte) string {
+	return fmt.Sprintf("%x", sha256.Sum256(data))
+}
+
+func sortVersions(versions []Version) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		a, errA := semver.NewVersion(versions[i].Version)
+		b, errB := semver.NewVersion(versions[j].Version)
+		if errA != nil || errB != nil {
+			return versions[i].Version < versions[j].Version
+		}
+		return a.LessThan(b)
+	})
+}
@@ -0,0 +1,365 @@
+// Package catalogscaffold creates the skeleton of a new integration, which
+// passes validation as is.
+package catalogscaffold
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+
+	catalogv1 "github.com/sensu/catalog-api/internal/api/catalog/v1"
+	catalogapiv1 "github.com/sensu/catalog-api/internal/api/catalogapi/v1"
+	metav1 "github.com/sensu/catalog-api/internal/api/metadata/v1"
+	"github.com/sensu/catalog-api/internal/diagnostics"
+	"github.com/sensu/catalog-api/internal/integrationloader"
+	"github.com/sensu/catalog-api/internal/lint"
+)
+
+var (
+	// reName matches the namespaces & names that integration versions can be
+	// tagged with.
+	reName = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+	// DefaultReadmeSections are the sections of the README of an integration
+	// when the catalog does not require any.
+	DefaultReadmeSections = []string{
+		"Overview",
+		"Dashboards",
+		"Setup",
+		"Plugins",
+		"Metrics & Events",
+		"Alerts",
+		"Reference Documentation",
+	}
+
+	defaultProvider = "monitoring"
+	defaultClass    = "community"
+	defaultPlatform = "linux"
+)
+
+type Config struct {
+	Namespace string
+	Name      string
+
+	DisplayName      string
+	ShortDescription string
+	Provider         string
+	Class            string
+	Platforms        []string
+
+	// Contributors are the GitHub handles of the authors of the integration,
+	// e.g. "@octocat".
+	Contributors []string
+
+	// ReadmeSections are the headings of the sections of the README.
+	ReadmeSections []string
+
+	// Taxonomy is the taxonomy of the catalog that the integration is
+	// validated against.
+	Taxonomy catalogv1.Taxonomy
+
+	// Lint is the lint config of the catalog; the integration is not created
+	// if it has error-level findings.
+	Lint lint.Config
+}
+
+// WithDefaults returns the config with every unset option, other than the
+// namespace, name & contributors, set to its default.
+func (c Config) WithDefaults() Config {
+	if c.DisplayName == "" {
+		c.DisplayName = strings.Title(strings.NewReplacer("-", " ", "_", " ").Replace(c.Name))
+	}
+	if c.ShortDescription == "" {
+		c.ShortDescription = c.DisplayName + " integration"
+	}
+	if c.Provider == "" {
+		c.Provider = defaultOf(c.Taxonomy.ValidProviders(), defaultProvider)
+	}
+	if c.Class == "" {
+		c.Class = defaultOf(c.Taxonomy.ValidClasses(), defaultClass)
+	}
+	if len(c.Platforms) == 0 {
+		c.Platforms = []string{defaultOf(c.Taxonomy.Platforms, defaultPlatform)}
+	}
+	if len(c.ReadmeSections) == 0 {
+		c.ReadmeSections = DefaultReadmeSections
+	}
+	return c
+}
+
+// defaultOf returns value if it is one of values, or if values is empty, and
+// the first of values otherwise.
+func defaultOf(values []string, value string) string {
+	if len(values) == 0 {
+		return value
+	}
+	for _, v := range values {
+		if v == value {
+			return value
+		}
+	}
+	return values[0]
+}
+
+func (c Config) validate() error {
+	if !reName.MatchString(c.Namespace) {
+		return fmt.Errorf("namespace must only contain lowercase letters, digits, - & _, got: %q", c.Namespace)
+	}
+	if !reName.MatchString(c.Name) {
+		return fmt.Errorf("name must only contain lowercase letters, digits, - & _, got: %q", c.Name)
+	}
+	if catalogapiv1.IsReservedNamespace(c.Namespace) {
+		return fmt.Errorf("namespace name %s is reserved by the catalog api", c.Namespace)
+	}
+	for _, contributor := range c.Contributors {
+		if !lint.IsContributorHandle(contributor) {
+			return fmt.Errorf("contributors must be GitHub handles, e.g. @octocat, got: %s", contributor)
+		}
+	}
+	return c.Integration().ValidateWith(c.Taxonomy)
+}
+
+// Integration returns the config of the integration.
+func (c Config) Integration() catalogv1.Integration {
+	return catalogv1.Integration{
+		Metadata:           metav1.FixtureMetadata(c.Namespace, c.Name),
+		DisplayName:        c.DisplayName,
+		Class:              c.Class,
+		Contributors:       c.Contributors,
+		Provider:           c.Provider,
+		ShortDescription:   c.ShortDescription,
+		SupportedPlatforms: c.Platforms,
+		Tags:               []string{},
+		Prompts:            []catalogv1.Prompt{},
+		ResourcePatches:    []catalogv1.ResourcePatch{},
+	}
+}
+
+// Scaffold creates the files of a new integration within dir, which must not
+// exist, and returns their paths relative to dir. Unset options are set to
+// their defaults.
+func Scaffold(dir string, config Config) ([]string, error) {
+	config = config.WithDefaults()
+	if err := config.validate(); err != nil {
+		return nil, fmt.Errorf("invalid integration: %w", err)
+	}
+	files, err := renderFiles(config)
+	if err != nil {
+		return nil, err
+	}
+	if err := lintFiles(dir, config, files); err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+		return nil, fmt.Errorf("error creating namespace directory: %w", err)
+	}
+	if err := os.Mkdir(dir, 0755); errors.Is(err, os.ErrExist) {
+		return nil, fmt.Errorf("integration directory %s already exists", dir)
+	} else if err != nil {
+		return nil, fmt.Errorf("error creating integration directory: %w", err)
+	}
+
+	names, err := writeFiles(dir, files)
+	if err != nil {
+		// never leave a partial integration behind
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	return names, nil
+}
+
+// file is a file of the integration; name is relative to the integration
+// directory & uses forward slashes.
+type file struct {
+	name string
+	data []byte
+}
+
+// renderFiles returns the files of the integration in the order they are
+// written.
+func renderFiles(config Config) ([]file, error) {
+	files := []file{}
+	templates := []struct {
+		name     string
+		template *template.Template
+	}{
+		{integrationloader.ConfigName, configTemplate},
+		{integrationloader.ResourcesName, resourcesTemplate},
+		{integrationloader.ReadmeName, readmeTemplate},
+		{integrationloader.ChangelogName, changelogTemplate},
+	}
+	for _, t := range templates {
+		buf := bytes.Buffer{}
+		if err := t.template.Execute(&buf, config); err != nil {
+			return nil, fmt.Errorf("error rendering %s: %w", t.name, err)
+		}
+		files = append(files, file{t.name, buf.Bytes()})
+	}
+
+	logo, err := placeholderLogo()
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, file{integrationloader.LogoName, logo})
+
+	// git does not track empty directories
+	for _, name := range []string{integrationloader.ImagesDirName, integrationloader.DashboardsDirName} {
+		files = append(files, file{name + "/.gitkeep", nil})
+	}
+	return files, nil
+}
+
+// lintFiles lints the rendered integration with the lint config of the
+// catalog & returns an error listing its error-level findings, if any.
+func lintFiles(dir string, config Config, files []file) error {
+	linter, err := lint.New(config.Lint)
+	if err != nil {
+		return fmt.Errorf("invalid lint config: %w", err)
+	}
+
+	subject := lint.Subject{
+		Namespace: config.Namespace,
+		Name:      config.Name,
+		Dir:       dir,
+		Config:    config.Integration(),
+	}
+	entries := map[string]bool{}
+	for _, f := range files {
+		if f.name == integrationloader.ReadmeName {
+			subject.Readme = string(f.data)
+		}
+		entry := f.name
+		if i := strings.Index(entry, "/"); i >= 0 {
+			entry = entry[:i+1]
+		}
+		if !entries[entry] {
+			entries[entry] = true
+			subject.Files = append(subject.Files, entry)
+		}
+	}
+
+	findings := []string{}
+	for _, diag := range linter.Lint(subject) {
+		if diag.Severity == diagnostics.SeverityError {
+			findings = append(findings, diag.String())
+		}
+	}
+	if len(findings) > 0 {
+		return fmt.Errorf("integration fails linting:\n%s", strings.Join(findings, "\n"))
+	}
+	return nil
+}
+
+// writeFiles writes the files within dir & returns the names of the files
+// that were written.
+func writeFiles(dir string, files []file) ([]string, error) {
+	names := []string{}
+	for _, f := range files {
+		path := filepath.Join(dir, filepath.FromSlash(f.name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return names, fmt.Errorf("error creating directory for %s: %w", f.name, err)
+		}
+		if err := os.WriteFile(path, f.data, 0644); err != nil {
+			return names, fmt.Errorf("error writing %s: %w", f.name, err)
+		}
+		names = append(names, f.name)
+	}
+	return names, nil
+}
+
+// placeholderLogo returns a plain png image to be replaced by the logo of the
+// integration.
+func placeholderLogo() ([]byte, error) {
+	img := image.NewRGBA(image.Rect(0, 0, 128, 128))
+	for x := 0; x < 128; x++ {
+		for y := 0; y < 128; y++ {
+			img.Set(x, y, color.RGBA{R: 0x8c, G: 0xc6, B: 0x3f, A: 0xff})
+		}
+	}
+	buf := bytes.Buffer{}
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("error encoding logo: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+var funcs = template.FuncMap{
+	// quote quotes a string as a yaml scalar; json strings are valid yaml
+	"quote": func(s string) (string, error) {
+		b, err := json.Marshal(s)
+		return string(b), err
+	},
+}
+
+var configTemplate = template.Must(template.New(integrationloader.ConfigName).Funcs(funcs).Parse(`---
+type: Integration
+api_version: catalog/v1
+metadata:
+  namespace: {{ .Namespace }}
+  name: {{ .Name }}
+spec:
+  display_name: {{ quote .DisplayName }}
+  class: {{ quote .Class }}
+  provider: {{ quote .Provider }}
+  short_description: {{ quote .ShortDescription }}
+  contributors:
+{{- range .Contributors }}
+    - {{ quote . }}
+{{- end }}
+  supported_platforms:
+{{- range .Platforms }}
+    - {{ quote . }}
+{{- end }}
+  tags: []
+  prompts: []
+  resource_patches: []
+`))
+
+var resourcesTemplate = template.Must(template.New(integrationloader.ResourcesName).Parse(`# Replace this example check with the Sensu resources of the integration.
+---
+type: CheckConfig
+api_version: core/v2
+metadata:
+  name: {{ .Name }}
+spec:
+  command: check-{{ .Name }}
+  interval: 60
+  publish: true
+  subscriptions:
+    - {{ .Name }}
+  runtime_assets: []
+`))
+
+var readmeTemplate = template.Must(template.New(integrationloader.ReadmeName).Parse(`# {{ .DisplayName }}
+
+{{ .ShortDescription }}
+{{ range .ReadmeSections }}
+## {{ . }}
+
+<!-- TODO: complete this section -->
+{{ end -}}
+`))
+
+var changelogTemplate = template.Must(template.New(integrationloader.ChangelogName).Parse(`# Changelog
+
+All notable changes to this integration will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.0.0/),
+and this integration adheres to [Semantic Versioning](https://semver.org/spec/v2.0.0.html).
+
+## Unreleased
+
+### Added
+
+- Initial release of the {{ .DisplayName }} integration
+`))
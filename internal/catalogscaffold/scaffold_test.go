@@ -0,0 +1,316 @@
+package catalogscaffold
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	catalogv1 "github.com/sensu/catalog-api/internal/api/catalog/v1"
+	"github.com/sensu/catalog-api/internal/catalogloader"
+	"github.com/sensu/catalog-api/internal/catalogmanager"
+	"github.com/sensu/catalog-api/internal/integrationloader"
+	"github.com/sensu/catalog-api/internal/lint"
+)
+
+func TestMain(m *testing.M) {
+	log.Logger = log.Output(zerolog.Nop())
+	os.Exit(m.Run())
+}
+
+func TestScaffold(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     Config
+		wantErrMsg string
+	}{
+		{
+			name: "defaults",
+			config: Config{
+				Namespace:    "example_ns",
+				Name:         "example-monitoring",
+				Contributors: []string{"@octocat"},
+			},
+		},
+		{
+			name: "options & custom taxonomy",
+			config: Config{
+				Namespace:        "example_ns",
+				Name:             "example",
+				DisplayName:      "Example: Tracing",
+				ShortDescription: `Traces "example" requests`,
+				Class:            "official",
+				Platforms:        []string{"linux", "windows"},
+				Contributors:     []string{"@octocat", "@artem"},
+				ReadmeSections:   []string{"Overview", "Setup"},
+				Taxonomy: catalogv1.Taxonomy{
+					Providers: []string{"tracing"},
+					Classes:   []string{"official"},
+					Platforms: []string{"linux", "windows"},
+				},
+			},
+		},
+		{
+			name: "invalid name",
+			config: Config{
+				Namespace:    "example_ns",
+				Name:         "Example",
+				Contributors: []string{"@octocat"},
+			},
+			wantErrMsg: `invalid integration: name must only contain lowercase letters, digits, - & _, got: "Example"`,
+		},
+		{
+			name: "reserved namespace",
+			config: Config{
+				Namespace:    "catalog",
+				Name:         "example",
+				Contributors: []string{"@octocat"},
+			},
+			wantErrMsg: "invalid integration: namespace name catalog is reserved by the catalog api",
+		},
+		{
+			name: "missing contributors",
+			config: Config{
+				Namespace: "example_ns",
+				Name:      "example",
+			},
+			wantErrMsg: "invalid integration: one or more contributors must be defined",
+		},
+		{
+			name: "contributor that is not a handle",
+			config: Config{
+				Namespace:    "example_ns",
+				Name:         "example",
+				Contributors: []string{"octocat"},
+			},
+			wantErrMsg: "invalid integration: contributors must be GitHub handles, e.g. @octocat, got: octocat",
+		},
+		{
+			name: "contributor that is not a valid handle",
+			config: Config{
+				Namespace:    "example_ns",
+				Name:         "example",
+				Contributors: []string{"@foo_bar"},
+			},
+			wantErrMsg: "invalid integration: contributors must be GitHub handles, e.g. @octocat, got: @foo_bar",
+		},
+		{
+			name: "provider outside of the taxonomy",
+			config: Config{
+				Namespace:    "example_ns",
+				Name:         "example",
+				Provider:     "tracing",
+				Contributors: []string{"@octocat"},
+			},
+			wantErrMsg: "invalid integration: provider must be one of [alerts deregistration discovery events incidents metrics monitoring remediation], got: tracing",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoDir := t.TempDir()
+			dir := filepath.Join(repoDir, "integrations", tt.config.Namespace, tt.config.Name)
+
+			files, err := Scaffold(dir, tt.config)
+			if tt.wantErrMsg != "" {
+				if err == nil || err.Error() != tt.wantErrMsg {
+					t.Fatalf("Scaffold() error = %v, want %s", err, tt.wantErrMsg)
+				}
+				if _, err := os.Stat(dir); !os.IsNotExist(err) {
+					t.Errorf("Scaffold() created %s despite failing", dir)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Scaffold() error = %v", err)
+			}
+
+			wantFiles := []string{
+				integrationloader.ConfigName,
+				integrationloader.ResourcesName,
+				integrationloader.ReadmeName,
+				integrationloader.ChangelogName,
+				integrationloader.LogoName,
+				"img/.gitkeep",
+				"dashboards/.gitkeep",
+			}
+			if !reflect.DeepEqual(files, wantFiles) {
+				t.Errorf("Scaffold() files = %v, want %v", files, wantFiles)
+			}
+
+			// the scaffolded integration must pass validation & linting as is
+			m, err := catalogmanager.New(catalogmanager.Config{
+				StagingDir:          t.TempDir(),
+				ReleaseDir:          t.TempDir(),
+				IntegrationsDirName: "integrations",
+				Taxonomy:            tt.config.Taxonomy,
+			}, catalogloader.NewPathLoader(repoDir, "integrations"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			integrations, err := m.ValidateIntegrations()
+			if err != nil {
+				t.Fatalf("ValidateIntegrations() error = %v", err)
+			}
+			linter, err := lint.New(lint.Config{ReadmeSections: tt.config.WithDefaults().ReadmeSections})
+			if err != nil {
+				t.Fatal(err)
+			}
+			diags, err := m.LintIntegrations(linter, integrations)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(diags) > 0 {
+				t.Errorf("LintIntegrations() = %v, want no diagnostics", diags)
+			}
+
+			config, err := integrationloader.NewPathLoader(dir).LoadConfig()
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := tt.config.WithDefaults().Integration()
+			config.Positions = nil
+			if !reflect.DeepEqual(config, want) {
+				t.Errorf("sensu-integration.yaml = %+v, want %+v", config, want)
+			}
+		})
+	}
+}
+
+func TestScaffold_existingIntegration(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "example_ns", "example")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("existing"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config := Config{Namespace: "example_ns", Name: "example", Contributors: []string{"@octocat"}}
+	if _, err := Scaffold(dir, config); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("Scaffold() error = %v, want an error about the existing directory", err)
+	}
+	if b, err := os.ReadFile(filepath.Join(dir, "README.md")); err != nil || string(b) != "existing" {
+		t.Errorf("Scaffold() modified the existing integration")
+	}
+}
+
+func TestScaffold_lint(t *testing.T) {
+	tests := []struct {
+		name     string
+		lint     lint.Config
+		wantErrs []string
+	}{
+		{
+			name: "warnings",
+		},
+		{
+			name: "errors",
+			lint: lint.Config{
+				Rules: map[string]string{lint.RuleDisplayNameCasing: "error"},
+			},
+			wantErrs: []string{
+				`error: %s: example_ns/example: display_name "example tracing" is not in title case [display-name-casing]`,
+			},
+		},
+		{
+			name: "invalid config",
+			lint: lint.Config{
+				Rules: map[string]string{"unknown": "error"},
+			},
+			wantErrs: []string{"invalid lint config: unknown lint rule: unknown"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "example_ns", "example")
+			config := Config{
+				Namespace:      "example_ns",
+				Name:           "example",
+				DisplayName:    "example tracing",
+				Contributors:   []string{"@octocat"},
+				ReadmeSections: []string{"Setup"},
+				Lint:           tt.lint,
+			}
+
+			_, err := Scaffold(dir, config)
+			if len(tt.wantErrs) == 0 {
+				if err != nil {
+					t.Fatalf("Scaffold() error = %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("Scaffold() error = nil, want an error")
+			}
+			for _, want := range tt.wantErrs {
+				if strings.Contains(want, "%s") {
+					want = fmt.Sprintf(want, filepath.Join(dir, integrationloader.ConfigName))
+				}
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Scaffold() error = %v, want %s", err, want)
+				}
+			}
+			if _, err := os.Stat(dir); !os.IsNotExist(err) {
+				t.Errorf("Scaffold() created %s despite failing", dir)
+			}
+		})
+	}
+}
+
+func TestConfig_WithDefaults(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		want   Config
+	}{
+		{
+			name:   "default taxonomy",
+			config: Config{Name: "nginx-monitoring"},
+			want: Config{
+				Name:             "nginx-monitoring",
+				DisplayName:      "Nginx Monitoring",
+				ShortDescription: "Nginx Monitoring integration",
+				Provider:         "monitoring",
+				Class:            "community",
+				Platforms:        []string{"linux"},
+				ReadmeSections:   DefaultReadmeSections,
+			},
+		},
+		{
+			name: "custom taxonomy",
+			config: Config{
+				Name: "nginx",
+				Taxonomy: catalogv1.Taxonomy{
+					Providers: []string{"tracing"},
+					Classes:   []string{"official", "community"},
+					Platforms: []string{"windows"},
+				},
+			},
+			want: Config{
+				Name:             "nginx",
+				DisplayName:      "Nginx",
+				ShortDescription: "Nginx integration",
+				Provider:         "tracing",
+				Class:            "community",
+				Platforms:        []string{"windows"},
+				ReadmeSections:   DefaultReadmeSections,
+				Taxonomy: catalogv1.Taxonomy{
+					Providers: []string{"tracing"},
+					Classes:   []string{"official", "community"},
+					Platforms: []string{"windows"},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.config.WithDefaults(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("WithDefaults() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package catalogserver
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/fs"
+	"sync"
+)
+
+// maxCachedETags bounds the number of etags held by an etagCache. The cache is
+// cleared once it is full, which is rare as it is also cleared on every
+// rebuild.
+const maxCachedETags = 10000
+
+// etagCache memoizes the strong etags of served files, which are derived
+// from their contents. Files are identified by path, size & modification
+// time, so that regenerated files are hashed again.
+type etagCache struct {
+	mu    sync.Mutex
+	etags map[string]string
+}
+
+func newETagCache() *etagCache {
+	return &etagCache{etags: map[string]string{}}
+}
+
+// etag returns the etag of f, which is rewound after hashing. A nil cache
+// hashes f on every call.
+func (c *etagCache) etag(path string, stat fs.FileInfo, f io.ReadSeeker) (string, error) {
+	key := fmt.Sprintf("%s:%d:%d", path, stat.Size(), stat.ModTime().UnixNano())
+	if c != nil {
+		c.mu.Lock()
+		etag, ok := c.etags[key]
+		c.mu.Unlock()
+		if ok {
+			return etag, nil
+		}
+	}
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	etag := fmt.Sprintf(`"%x"`, h.Sum(nil))
+
+	if c != nil {
+		c.mu.Lock()
+		if len(c.etags) >= maxCachedETags {
+			c.etags = map[string]string{}
+		}
+		c.etags[key] = etag
+		c.mu.Unlock()
+	}
+	return etag, nil
+}
+
+// reset forgets every etag, e.g. once the files of a previous release are no
+// longer served.
+func (c *etagCache) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.etags = map[string]string{}
+}
@@ -0,0 +1,137 @@
+package catalogserver
+
+import (
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	catalogapiv1 "github.com/sensu/catalog-api/internal/api/catalogapi/v1"
+)
+
+const (
+	cacheControlImmutable = "public, max-age=31536000, immutable"
+	cacheControlNoCache   = "no-cache"
+	cacheControlNoStore   = "no-store, no-cache, must-revalidate, proxy-revalidate, max-age=0"
+)
+
+// textContentTypes are the content types of text endpoints, which are set
+// explicitly so that an endpoint has the same content type regardless of
+// whether a compressed sibling is served.
+var textContentTypes = map[string]string{
+	".json": "application/json",
+	".md":   "text/markdown; charset=utf-8",
+	".svg":  "image/svg+xml",
+}
+
+// serveFile serves a file of the current release. A pre-compressed sibling
+// of a text endpoint is served instead of the endpoint itself when the
+// client accepts its content coding.
+func (h Handler) serveFile(w http.ResponseWriter, r *http.Request) {
+	root := http.Dir(h.symlink)
+	name := path.Clean("/" + r.URL.Path)
+
+	if catalogapiv1.IsCompressible(name) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		w.Header().Set("Content-Type", textContentTypes[path.Ext(name)])
+
+		for _, encoding := range acceptedEncodings(r.Header.Get("Accept-Encoding")) {
+			if h.serveContent(w, r, root, catalogapiv1.CompressedPath(name, encoding), name, encoding) {
+				return
+			}
+		}
+	}
+	if h.serveContent(w, r, root, name, name, "") {
+		return
+	}
+
+	// directories & missing files
+	http.FileServer(root).ServeHTTP(w, r)
+}
+
+// serveContent serves the regular file at filePath as the representation of
+// name with the given content coding, and reports whether the file exists.
+// Conditional requests are answered based on the strong ETag of the file &
+// its modification time.
+func (h Handler) serveContent(w http.ResponseWriter, r *http.Request, root http.FileSystem, filePath string, name string, encoding string) bool {
+	f, err := root.Open(filePath)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	stat, err := f.Stat()
+	if err != nil || stat.IsDir() {
+		return false
+	}
+
+	etag, err := h.etags.etag(filePath, stat, f)
+	if err != nil {
+		log.Error().Err(err).Str("path", filePath).Msg("Failed to calculate etag")
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return true
+	}
+
+	w.Header().Set("Cache-Control", cacheControl(name))
+	w.Header().Set("ETag", etag)
+	if encoding != "" {
+		w.Header().Set("Content-Encoding", encoding)
+	}
+	http.ServeContent(w, r, name, stat.ModTime(), f)
+	return true
+}
+
+// cacheControl returns the Cache-Control header of the file at name. Files
+// within a release directory are addressed by the checksum of the release and
+// never change, while the version endpoint & other files at the root point at
+// the current release and must always be revalidated.
+func cacheControl(name string) string {
+	release := strings.SplitN(strings.TrimPrefix(name, "/"), "/", 2)[0]
+	if name != "/"+release && catalogapiv1.IsReleaseChecksum(release) {
+		return cacheControlImmutable
+	}
+	return cacheControlNoCache
+}
+
+// acceptedEncodings returns the content codings of pre-compressed siblings
+// that are acceptable according to an Accept-Encoding header, in order of
+// preference.
+func acceptedEncodings(header string) []string {
+	weights := map[string]float64{}
+	for _, part := range strings.Split(header, ",") {
+		params := strings.Split(part, ";")
+		coding := strings.ToLower(strings.TrimSpace(params[0]))
+		if coding == "" {
+			continue
+		}
+		weight := 1.0
+		for _, param := range params[1:] {
+			kv := strings.SplitN(param, "=", 2)
+			if len(kv) == 2 && strings.TrimSpace(kv[0]) == "q" {
+				q, err := strconv.ParseFloat(strings.TrimSpace(kv[1]), 64)
+				if err != nil {
+					q = 0
+				}
+				weight = q
+			}
+		}
+		weights[coding] = weight
+	}
+
+	accepted := []string{}
+	for _, encoding := range catalogapiv1.Encodings {
+		weight, ok := weights[encoding]
+		if !ok {
+			weight, ok = weights["*"]
+		}
+		if ok && weight > 0 {
+			accepted = append(accepted, encoding)
+			weights[encoding] = weight
+		}
+	}
+	sort.SliceStable(accepted, func(i, j int) bool {
+		return weights[accepted[i]] > weights[accepted[j]]
+	})
+	return accepted
+}
@@ -0,0 +1,320 @@
+package catalogserver
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	catalogapiv1 "github.com/sensu/catalog-api/internal/api/catalogapi/v1"
+	"github.com/sensu/catalog-api/internal/endpoints"
+)
+
+func TestHandler_serveFile(t *testing.T) {
+	dir := t.TempDir()
+	catalog := []byte(`{"namespaced_integrations":{}}`)
+	files := map[string][]byte{
+		"v1/catalog.json": catalog,
+		"v1/README.md":    []byte("# readme"),
+		"v1/logo.png":     []byte("png data"),
+	}
+	for _, encoding := range catalogapiv1.Encodings {
+		compressed, err := endpoints.Compress(encoding, catalog)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[catalogapiv1.CompressedPath("v1/catalog.json", encoding)] = compressed
+	}
+	for name, contents := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, contents, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	h := Handler{symlink: dir}
+
+	tests := []struct {
+		name           string
+		path           string
+		acceptEncoding string
+		wantEncoding   string
+		wantType       string
+		wantVary       bool
+	}{
+		{
+			name:     "uncompressed",
+			path:     "/v1/catalog.json",
+			wantType: "application/json",
+			wantVary: true,
+		},
+		{
+			name:           "gzip",
+			path:           "/v1/catalog.json",
+			acceptEncoding: "gzip, deflate",
+			wantEncoding:   "gzip",
+			wantType:       "application/json",
+			wantVary:       true,
+		},
+		{
+			name:           "brotli preferred",
+			path:           "/v1/catalog.json",
+			acceptEncoding: "gzip, deflate, br",
+			wantEncoding:   "br",
+			wantType:       "application/json",
+			wantVary:       true,
+		},
+		{
+			name:           "client preference",
+			path:           "/v1/catalog.json",
+			acceptEncoding: "br;q=0.5, gzip",
+			wantEncoding:   "gzip",
+			wantType:       "application/json",
+			wantVary:       true,
+		},
+		{
+			name:           "refused encodings",
+			path:           "/v1/catalog.json",
+			acceptEncoding: "br;q=0, *;q=0",
+			wantType:       "application/json",
+			wantVary:       true,
+		},
+		{
+			name:           "no compressed sibling",
+			path:           "/v1/README.md",
+			acceptEncoding: "br, gzip",
+			wantType:       "text/markdown; charset=utf-8",
+			wantVary:       true,
+		},
+		{
+			name:           "binary file",
+			path:           "/v1/logo.png",
+			acceptEncoding: "br, gzip",
+			wantType:       "image/png",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			rec := httptest.NewRecorder()
+			h.serveFile(rec, req)
+			resp := rec.Result()
+
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("status = %v, want %v", resp.StatusCode, http.StatusOK)
+			}
+			if got := resp.Header.Get("Content-Encoding"); got != tt.wantEncoding {
+				t.Errorf("Content-Encoding = %q, want %q", got, tt.wantEncoding)
+			}
+			if got := resp.Header.Get("Content-Type"); got != tt.wantType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.wantType)
+			}
+			if got := resp.Header.Get("Vary") == "Accept-Encoding"; got != tt.wantVary {
+				t.Errorf("Vary = %q, want Accept-Encoding: %v", resp.Header.Get("Vary"), tt.wantVary)
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantEncoding != "" {
+				body, err = endpoints.Decompress(tt.wantEncoding, body)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			if want := files[tt.path[1:]]; string(body) != string(want) {
+				t.Errorf("body = %q, want %q", body, want)
+			}
+		})
+	}
+}
+
+func TestAcceptedEncodings(t *testing.T) {
+	tests := []struct {
+		header string
+		want   []string
+	}{
+		{header: "", want: []string{}},
+		{header: "identity", want: []string{}},
+		{header: "gzip", want: []string{"gzip"}},
+		{header: "GZIP, br", want: []string{"br", "gzip"}},
+		{header: "gzip;q=1.0, br;q=0.8", want: []string{"gzip", "br"}},
+		{header: "*", want: []string{"br", "gzip"}},
+		{header: "*;q=0.5, gzip", want: []string{"gzip", "br"}},
+		{header: "br;q=0, gzip;q=invalid", want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			if got := acceptedEncodings(tt.header); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("acceptedEncodings() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandler_caching(t *testing.T) {
+	const releaseSHA256 = "af3c54b86b90fac8977f1bdc80d955002dd3f441bdbb4cc603c94abbb929dcf6"
+	dir := t.TempDir()
+	catalog := []byte(`{"namespaced_integrations":{}}`)
+	gzipped, err := endpoints.Compress(catalogapiv1.EncodingGzip, catalog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string][]byte{
+		"version.json":                               []byte(`{"release_sha256":"` + releaseSHA256 + `"}`),
+		releaseSHA256 + "/v1/catalog.json":           catalog,
+		releaseSHA256 + "/v1/catalog.json.gz":        gzipped,
+		"not-a-release/v1/catalog.json":              catalog,
+		releaseSHA256 + "/v1/example/1.0.0/logo.png": []byte("png data"),
+	}
+	for name, contents := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, contents, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	h := NewHandler(nil, dir)
+
+	get := func(path string, header http.Header) *http.Response {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		for name, values := range header {
+			req.Header[name] = values
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Result()
+	}
+
+	tests := []struct {
+		name             string
+		path             string
+		header           http.Header
+		wantStatus       int
+		wantCacheControl string
+	}{
+		{
+			name:             "release file",
+			path:             "/" + releaseSHA256 + "/v1/catalog.json",
+			wantStatus:       http.StatusOK,
+			wantCacheControl: cacheControlImmutable,
+		},
+		{
+			name:             "binary release file",
+			path:             "/" + releaseSHA256 + "/v1/example/1.0.0/logo.png",
+			wantStatus:       http.StatusOK,
+			wantCacheControl: cacheControlImmutable,
+		},
+		{
+			name:             "version endpoint",
+			path:             "/version.json",
+			wantStatus:       http.StatusOK,
+			wantCacheControl: cacheControlNoCache,
+		},
+		{
+			name:             "not a release",
+			path:             "/not-a-release/v1/catalog.json",
+			wantStatus:       http.StatusOK,
+			wantCacheControl: cacheControlNoCache,
+		},
+		{
+			name:             "not modified since",
+			path:             "/version.json",
+			header:           http.Header{"If-Modified-Since": {time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)}},
+			wantStatus:       http.StatusNotModified,
+			wantCacheControl: cacheControlNoCache,
+		},
+		{
+			name:             "modified since",
+			path:             "/version.json",
+			header:           http.Header{"If-Modified-Since": {time.Unix(0, 0).UTC().Format(http.TimeFormat)}},
+			wantStatus:       http.StatusOK,
+			wantCacheControl: cacheControlNoCache,
+		},
+		{
+			name:             "stale etag",
+			path:             "/" + releaseSHA256 + "/v1/catalog.json",
+			header:           http.Header{"If-None-Match": {`"stale"`}},
+			wantStatus:       http.StatusOK,
+			wantCacheControl: cacheControlImmutable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := get(tt.path, tt.header)
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %v, want %v", resp.StatusCode, tt.wantStatus)
+			}
+			if got := resp.Header.Get("Cache-Control"); got != tt.wantCacheControl {
+				t.Errorf("Cache-Control = %q, want %q", got, tt.wantCacheControl)
+			}
+			if resp.Header.Get("ETag") == "" {
+				t.Errorf("ETag not set")
+			}
+		})
+	}
+
+	// etags are strong, differ between encodings & are answered with 304
+	path := "/" + releaseSHA256 + "/v1/catalog.json"
+	etag := get(path, nil).Header.Get("ETag")
+	want := fmt.Sprintf(`"%x"`, sha256.Sum256(catalog))
+	if etag != want {
+		t.Errorf("ETag = %v, want %v", etag, want)
+	}
+	gzipETag := get(path, http.Header{"Accept-Encoding": {"gzip"}}).Header.Get("ETag")
+	if gzipETag == etag {
+		t.Errorf("ETag of gzip encoded response must differ from %v", etag)
+	}
+	if resp := get(path, http.Header{"If-None-Match": {etag}}); resp.StatusCode != http.StatusNotModified {
+		t.Errorf("status = %v, want %v", resp.StatusCode, http.StatusNotModified)
+	}
+	if resp := get(path, http.Header{"If-None-Match": {etag}, "Accept-Encoding": {"gzip"}}); resp.StatusCode != http.StatusOK {
+		t.Errorf("status of gzip encoded response = %v, want %v", resp.StatusCode, http.StatusOK)
+	}
+
+	// dynamic responses are not cached
+	if got := get("/"+releaseSHA256+"/v1/", nil).Header.Get("Cache-Control"); got != cacheControlNoStore {
+		t.Errorf("Cache-Control of directory = %q, want %q", got, cacheControlNoStore)
+	}
+}
+
+func TestETagCache_bounded(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "catalog.json")
+	if err := os.WriteFile(path, []byte("{}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	stat, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := newETagCache()
+	for i := 0; i <= maxCachedETags; i++ {
+		if _, err := c.etag(fmt.Sprintf("%s.%d", path, i), stat, bytes.NewReader([]byte("{}"))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(c.etags) > maxCachedETags {
+		t.Errorf("cache holds %d etags, want at most %d", len(c.etags), maxCachedETags)
+	}
+
+	c.reset()
+	if len(c.etags) != 0 {
+		t.Errorf("cache holds %d etags after reset, want 0", len(c.etags))
+	}
+}
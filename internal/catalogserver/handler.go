@@ -2,9 +2,11 @@ package catalogserver
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/gorilla/websocket"
 	"github.com/rs/zerolog/log"
+	"github.com/sensu/catalog-api/internal/catalogquery"
 	"github.com/sensu/catalog-api/internal/transport"
 )
 
@@ -12,26 +14,59 @@ func NewHandler(transport *transport.Transport, symlink string) Handler {
 	return Handler{
 		transport: transport,
 		symlink:   symlink,
+		etags:     newETagCache(),
 	}
 }
 
 type Handler struct {
 	transport *transport.Transport
 	symlink   string
+
+	// etags holds the etags of served files
+	etags *etagCache
+
+	// store holds the in-memory release used by the query & graphql apis
+	store    *catalogquery.Store
+	queryAPI bool
+
+	// graphql serves the graphql api; the graphql api is disabled when nil
+	graphql http.Handler
+
+	// metrics serves the /metrics endpoint; the endpoint is disabled when nil
+	metrics http.Handler
+
+	// status serves the /healthz, /readyz & /status.json endpoints; the
+	// endpoints are disabled when nil
+	status *statusTracker
 }
 
 func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	switch r.URL.Path {
-	case "/ws":
+	if r.URL.Path == "/ws" {
 		h.serveWs(w, r)
-	default:
-		// allow cross domain AJAX requests
-		w.Header().Set("Access-Control-Allow-Origin", "*")
+		return
+	}
 
-		// disable caching of served files
-		w.Header().Set("Cache-Control", "no-store, no-cache, must-revalidate, proxy-revalidate, max-age=0")
+	// disable caching of dynamic responses; files of the release set their
+	// own caching headers
+	w.Header().Set("Cache-Control", cacheControlNoStore)
 
-		http.FileServer(http.Dir(h.symlink)).ServeHTTP(w, r)
+	switch {
+	case h.queryAPI && r.URL.Path == "/query/integrations":
+		h.serveQueryIntegrations(w, r)
+	case h.queryAPI && strings.HasPrefix(r.URL.Path, "/resolve/"):
+		h.serveResolve(w, r)
+	case h.graphql != nil && r.URL.Path == "/catalog-graphql":
+		h.graphql.ServeHTTP(w, r)
+	case h.metrics != nil && r.URL.Path == "/metrics":
+		h.metrics.ServeHTTP(w, r)
+	case h.status != nil && r.URL.Path == "/healthz":
+		h.serveHealthz(w, r)
+	case h.status != nil && r.URL.Path == "/readyz":
+		h.serveReadyz(w, r)
+	case h.status != nil && r.URL.Path == "/status.json":
+		h.serveStatus(w, r)
+	default:
+		h.serveFile(w, r)
 	}
 }
 
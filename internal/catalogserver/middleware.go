@@ -0,0 +1,185 @@
+package catalogserver
+
+import (
+	"bufio"
+	"crypto/subtle"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/sensu/catalog-api/internal/catalogmetrics"
+)
+
+// accessLog logs every request once it has been served.
+func accessLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		log.Info().
+			Str("method", r.Method).
+			Str("path", r.URL.Path).
+			Int("status", rec.status).
+			Int64("bytes", rec.bytes).
+			Dur("duration", time.Since(start)).
+			Str("remote_addr", r.RemoteAddr).
+			Str("user_agent", r.UserAgent()).
+			Msg("Request served")
+	})
+}
+
+// instrument records the class, status & duration of every request in
+// metrics.
+func instrument(metrics *catalogmetrics.Metrics, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		metrics.ObserveRequest(endpointClass(r.URL.Path), rec.status, time.Since(start))
+	})
+}
+
+// endpointClass groups request paths into a small, fixed set of classes so
+// that request metrics do not grow with the number of files in a release.
+func endpointClass(path string) string {
+	switch {
+	case path == "/ws":
+		return "websocket"
+	case path == "/metrics":
+		return "metrics"
+	case path == "/healthz" || path == "/readyz" || path == "/status.json":
+		return "status"
+	case path == "/version.json" || path == "/version.json.sig":
+		return "version"
+	case path == "/query/integrations":
+		return "query"
+	case strings.HasPrefix(path, "/resolve/"):
+		return "resolve"
+	case path == "/catalog-graphql":
+		return "graphql"
+	case cacheControl(path) == cacheControlImmutable:
+		return "release"
+	}
+	return "other"
+}
+
+// responseRecorder records the status & size of a response. It supports
+// hijacking the connection, which websocket upgrades rely on.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += int64(n)
+	return n, err
+}
+
+func (r *responseRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (r *responseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer does not support hijacking")
+	}
+	r.status = http.StatusSwitchingProtocols
+	return h.Hijack()
+}
+
+// cors allows cross-origin requests from the given origins, or from every
+// origin if none are given, and answers preflight requests, which browsers
+// send without credentials, before they reach authentication.
+func cors(origins []string, next http.Handler) http.Handler {
+	if len(origins) == 0 {
+		origins = []string{"*"}
+	}
+	allowed := map[string]bool{}
+	for _, origin := range origins {
+		allowed[origin] = true
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		switch {
+		case allowed["*"]:
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		case len(allowed) > 0:
+			// the response depends on the origin, so caches must not serve it
+			// to other origins
+			w.Header().Add("Vary", "Origin")
+			if origin != "" && allowed[origin] {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+			}
+		}
+
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			w.Header().Set("Access-Control-Allow-Methods", "GET, HEAD, POST, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+			w.Header().Set("Access-Control-Max-Age", "86400")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// authenticate requires requests to carry the bearer token or basic auth
+// credentials of config. Every request is accepted if neither is configured.
+// Liveness & readiness probes are always accepted, as they reveal nothing
+// about the catalog.
+func authenticate(config Config, next http.Handler) http.Handler {
+	if !config.requiresAuth() {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/healthz" || r.URL.Path == "/readyz" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if config.BearerToken != "" {
+			auth := r.Header.Get("Authorization")
+			if strings.HasPrefix(auth, "Bearer ") && secureCompare(strings.TrimPrefix(auth, "Bearer "), config.BearerToken) {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+		if config.BasicAuthUsername != "" {
+			username, password, ok := r.BasicAuth()
+			// evaluate both comparisons to avoid leaking which one failed
+			validUsername := secureCompare(username, config.BasicAuthUsername)
+			validPassword := secureCompare(password, config.BasicAuthPassword)
+			if ok && validUsername && validPassword {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+
+		if config.BasicAuthUsername != "" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="catalog-api", charset="UTF-8"`)
+		} else {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="catalog-api"`)
+		}
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	})
+}
+
+func secureCompare(given string, expected string) bool {
+	return subtle.ConstantTimeCompare([]byte(given), []byte(expected)) == 1
+}
@@ -0,0 +1,73 @@
+package catalogserver
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/sensu/catalog-api/internal/catalogquery"
+)
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// GET /query/integrations?tag=&provider=&class=&platform=&q=&sort=&page=&per_page=
+func (h Handler) serveQueryIntegrations(w http.ResponseWriter, r *http.Request) {
+	release := h.store.Release()
+	if release == nil {
+		writeJSON(w, http.StatusServiceUnavailable, errorResponse{Error: "no release loaded"})
+		return
+	}
+
+	query, err := catalogquery.ParseQuery(r.URL.Query())
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, release.Query(query))
+}
+
+// GET /resolve/:namespace/:name?constraint=
+func (h Handler) serveResolve(w http.ResponseWriter, r *http.Request) {
+	release := h.store.Release()
+	if release == nil {
+		writeJSON(w, http.StatusServiceUnavailable, errorResponse{Error: "no release loaded"})
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/resolve/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		writeJSON(w, http.StatusNotFound, errorResponse{Error: "path must be in the form /resolve/:namespace/:name"})
+		return
+	}
+
+	resolution, err := release.Resolve(parts[0], parts[1], r.URL.Query().Get("constraint"))
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, catalogquery.ErrIntegrationNotFound) || errors.Is(err, catalogquery.ErrNoMatchingVersion) {
+			status = http.StatusNotFound
+		}
+		writeJSON(w, status, errorResponse{Error: err.Error()})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resolution)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to marshal response")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if _, err := w.Write(b); err != nil {
+		log.Debug().Err(err).Msg("Failed to write response")
+	}
+}
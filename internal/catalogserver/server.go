@@ -2,62 +2,274 @@ package catalogserver
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/rs/zerolog/log"
+	"github.com/sensu/catalog-api/internal/cataloggraphql"
+	"github.com/sensu/catalog-api/internal/catalogmetrics"
+	"github.com/sensu/catalog-api/internal/catalogquery"
 	"github.com/sensu/catalog-api/internal/transport"
 )
 
-func NewCatalogServer(listenAddr string, symlink string) Server {
+const (
+	readHeaderTimeout = 10 * time.Second
+	idleTimeout       = 120 * time.Second
+)
+
+type Config struct {
+	ListenAddr string
+	Symlink    string
+
+	// EnableQueryAPI loads the current release into memory to serve the
+	// /query & /resolve endpoints.
+	EnableQueryAPI bool
+
+	// EnableGraphQL loads the current release into memory to serve the
+	// /catalog-graphql endpoint.
+	EnableGraphQL bool
+
+	// TLSCertFile & TLSKeyFile, if set, are used to serve HTTPS.
+	TLSCertFile string
+	TLSKeyFile  string
+
+	// BearerToken and BasicAuthUsername & BasicAuthPassword, if set, are
+	// required to access the server. Requests are accepted if they match
+	// either.
+	BearerToken       string
+	BasicAuthUsername string
+	BasicAuthPassword string
+
+	// AllowedOrigins lists the origins allowed to make cross-origin requests;
+	// "*" allows every origin, as does an empty list.
+	AllowedOrigins []string
+}
+
+func (c Config) validate() error {
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		return errors.New("tls cert file & tls key file must be set together")
+	}
+	if (c.BasicAuthUsername == "") != (c.BasicAuthPassword == "") {
+		return errors.New("basic auth username & password must be set together")
+	}
+	return nil
+}
+
+func (c Config) requiresAuth() bool {
+	return c.BearerToken != "" || c.BasicAuthUsername != ""
+}
+
+func (c Config) allowsEveryOrigin() bool {
+	if len(c.AllowedOrigins) == 0 {
+		return true
+	}
+	for _, origin := range c.AllowedOrigins {
+		if origin == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+func NewCatalogServer(config Config) (Server, error) {
+	if err := config.validate(); err != nil {
+		return Server{}, fmt.Errorf("server config validation failed: %w", err)
+	}
+
+	if config.allowsEveryOrigin() && config.requiresAuth() {
+		log.Warn().Msg("Every origin is allowed to make cross-origin requests to a server that requires authentication")
+	}
+
 	t := transport.NewTransport()
+	metrics := catalogmetrics.New(t.Clients)
+	status := newStatusTracker()
+
+	var store *catalogquery.Store
+	if config.EnableQueryAPI || config.EnableGraphQL {
+		store = catalogquery.NewStore(config.Symlink)
+	}
 
 	handler := &Handler{
-		symlink:   symlink,
+		symlink:   config.Symlink,
 		transport: &t,
+		store:     store,
+		queryAPI:  config.EnableQueryAPI,
+		etags:     newETagCache(),
+		metrics:   metrics.Handler(),
+		status:    status,
+	}
+	if config.EnableGraphQL {
+		graphqlHandler, err := cataloggraphql.NewHandler(store)
+		if err != nil {
+			return Server{}, err
+		}
+		handler.graphql = graphqlHandler
 	}
 
 	server := &http.Server{
-		Addr:    listenAddr,
-		Handler: handler,
+		Addr:              config.ListenAddr,
+		Handler:           accessLog(instrument(metrics, cors(config.AllowedOrigins, authenticate(config, handler)))),
+		ReadHeaderTimeout: readHeaderTimeout,
+		IdleTimeout:       idleTimeout,
 	}
 
-	return NewServer(server, &t)
+	s := NewServer(server, &t)
+	s.store = store
+	s.etags = handler.etags
+	s.metrics = metrics
+	s.status = status
+	s.symlink = config.Symlink
+	s.tlsCertFile = config.TLSCertFile
+	s.tlsKeyFile = config.TLSKeyFile
+	return s, nil
 }
 
 type Server struct {
 	server    *http.Server
 	transport *transport.Transport
+	store     *catalogquery.Store
+	etags     *etagCache
+	metrics   *catalogmetrics.Metrics
+	status    *statusTracker
+	symlink   string
+
+	tlsCertFile string
+	tlsKeyFile  string
+
+	// stopped is closed when the server is stopped, which stops the
+	// transport
+	stopped  chan struct{}
+	stopOnce *sync.Once
 }
 
 func NewServer(server *http.Server, transport *transport.Transport) Server {
 	return Server{
 		server:    server,
 		transport: transport,
+		stopped:   make(chan struct{}),
+		stopOnce:  &sync.Once{},
 	}
 }
 
-func (c *Server) Start(ctx context.Context) {
-	// start the transport server
+// Start serves requests until the server is stopped, in which case nil is
+// returned, or until serving fails.
+func (c *Server) Start(ctx context.Context) error {
+	// start the transport server, which runs until the server is stopped
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-c.stopped:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
 	go c.transport.Start(ctx)
 
+	// load the current release for the query & graphql apis
+	c.reloadRelease()
+
 	// start the tcp listener
 	listener, err := net.Listen("tcp", c.server.Addr)
 	if err != nil {
-		panic(err)
+		return fmt.Errorf("error starting listener: %w", err)
 	}
-	log.Info().Str("address", c.server.Addr).Msg("API server started")
+	log.Info().
+		Str("address", listener.Addr().String()).
+		Bool("tls", c.tlsCertFile != "").
+		Msg("API server started")
 
 	// serve http requests over the tcp listener
-	if err := c.server.Serve(listener); err != nil && err != http.ErrServerClosed {
-		panic(err)
+	if c.tlsCertFile != "" {
+		err = c.server.ServeTLS(listener, c.tlsCertFile, c.tlsKeyFile)
+	} else {
+		err = c.server.Serve(listener)
+	}
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("error serving requests: %w", err)
 	}
+	return nil
 }
 
+// Stop stops accepting connections, waits for active requests to complete
+// or ctx to be done, and then disconnects websocket clients.
 func (c *Server) Stop(ctx context.Context) error {
-	return c.server.Shutdown(ctx)
+	err := c.server.Shutdown(ctx)
+	c.stopOnce.Do(func() {
+		close(c.stopped)
+	})
+	return err
 }
 
 func (c *Server) HandleWatchEvent() {
+	// the etags of the previous release are never requested again
+	if c.etags != nil {
+		c.etags.reset()
+	}
+	c.reloadRelease()
 	c.transport.Broadcast([]byte("refresh"))
 }
+
+// ObservePhase records the duration of a phase of a catalog build. It
+// implements catalogmanager.Observer.
+func (c *Server) ObservePhase(phase string, duration time.Duration) {
+	if c.metrics != nil {
+		c.metrics.ObservePhase(phase, duration)
+	}
+}
+
+// ObserveBuild records the outcome of a catalog build. A failed build
+// leaves the previous release in place, so it is reported by the status
+// endpoint rather than by readiness.
+func (c *Server) ObserveBuild(build Build) {
+	if c.metrics != nil {
+		c.metrics.ObserveBuild(build.Duration, build.Err)
+	}
+	if c.status != nil {
+		c.status.setBuild(build, time.Now())
+	}
+}
+
+// reloadRelease loads the current release into the store of the query &
+// graphql apis, if enabled, and records it in the status & metrics.
+func (c *Server) reloadRelease() {
+	c.reloadStore()
+	if c.status != nil {
+		c.status.setRelease(c.symlink)
+	}
+	if c.metrics == nil {
+		return
+	}
+	if release := c.storeRelease(); release != nil {
+		c.metrics.SetRelease(release.Summary())
+		return
+	}
+	summary, err := catalogquery.LoadSummary(c.symlink)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to load release metrics")
+	}
+	c.metrics.SetRelease(summary)
+}
+
+// storeRelease returns the release loaded into the store, if any.
+func (c *Server) storeRelease() *catalogquery.Release {
+	if c.store == nil {
+		return nil
+	}
+	return c.store.Release()
+}
+
+func (c *Server) reloadStore() {
+	if c.store == nil {
+		return
+	}
+	if err := c.store.Reload(); err != nil {
+		log.Error().Err(err).Msg("Failed to load release into memory")
+		return
+	}
+	log.Info().Str("release_sha256", c.store.Release().SHA256).Msg("Release loaded into memory")
+}
@@ -0,0 +1,430 @@
+package catalogserver
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sensu/catalog-api/internal/catalogmanager"
+)
+
+func TestAuthenticate(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	bearer := Config{BearerToken: "secret"}
+	basic := Config{BasicAuthUsername: "user", BasicAuthPassword: "pass"}
+	both := Config{BearerToken: "secret", BasicAuthUsername: "user", BasicAuthPassword: "pass"}
+
+	tests := []struct {
+		name       string
+		config     Config
+		setup      func(r *http.Request)
+		wantStatus int
+	}{
+		{name: "no auth", config: Config{}, wantStatus: http.StatusOK},
+		{name: "missing token", config: bearer, wantStatus: http.StatusUnauthorized},
+		{
+			name:       "valid token",
+			config:     bearer,
+			setup:      func(r *http.Request) { r.Header.Set("Authorization", "Bearer secret") },
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "invalid token",
+			config:     bearer,
+			setup:      func(r *http.Request) { r.Header.Set("Authorization", "Bearer wrong") },
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "valid basic auth",
+			config:     basic,
+			setup:      func(r *http.Request) { r.SetBasicAuth("user", "pass") },
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "invalid basic auth",
+			config:     basic,
+			setup:      func(r *http.Request) { r.SetBasicAuth("user", "wrong") },
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "basic auth when both are configured",
+			config:     both,
+			setup:      func(r *http.Request) { r.SetBasicAuth("user", "pass") },
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "token when both are configured",
+			config:     both,
+			setup:      func(r *http.Request) { r.Header.Set("Authorization", "Bearer secret") },
+			wantStatus: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/version.json", nil)
+			if tt.setup != nil {
+				tt.setup(req)
+			}
+			rec := httptest.NewRecorder()
+			authenticate(tt.config, ok).ServeHTTP(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %v, want %v", rec.Code, tt.wantStatus)
+			}
+			if rec.Code == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") == "" {
+				t.Errorf("WWW-Authenticate not set")
+			}
+		})
+	}
+}
+
+func TestCORS(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	tests := []struct {
+		name       string
+		origins    []string
+		origin     string
+		preflight  bool
+		wantOrigin string
+		wantStatus int
+	}{
+		{name: "any origin", origins: []string{"*"}, origin: "https://example.com", wantOrigin: "*", wantStatus: http.StatusOK},
+		{name: "allowed origin", origins: []string{"https://example.com"}, origin: "https://example.com", wantOrigin: "https://example.com", wantStatus: http.StatusOK},
+		{name: "other origin", origins: []string{"https://example.com"}, origin: "https://evil.com", wantStatus: http.StatusOK},
+		{name: "no origins", origins: []string{}, origin: "https://example.com", wantOrigin: "*", wantStatus: http.StatusOK},
+		{name: "preflight", origins: []string{"https://example.com"}, origin: "https://example.com", preflight: true, wantOrigin: "https://example.com", wantStatus: http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := http.MethodGet
+			if tt.preflight {
+				method = http.MethodOptions
+			}
+			req := httptest.NewRequest(method, "/version.json", nil)
+			req.Header.Set("Origin", tt.origin)
+			if tt.preflight {
+				req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+			}
+
+			// preflight requests are answered without credentials
+			handler := cors(tt.origins, authenticate(Config{BearerToken: "secret"}, ok))
+			if !tt.preflight {
+				req.Header.Set("Authorization", "Bearer secret")
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %v, want %v", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tt.wantOrigin {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.wantOrigin)
+			}
+		})
+	}
+}
+
+// writeCertificate writes a self-signed certificate for localhost.
+func writeCertificate(t *testing.T) (certFile string, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	certFile = filepath.Join(dir, "tls.crt")
+	keyFile = filepath.Join(dir, "tls.key")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+// freeAddr returns a local address that is free to listen on.
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	return l.Addr().String()
+}
+
+func TestServer_Start(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "version.json"), []byte(`{}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	certFile, keyFile := writeCertificate(t)
+
+	addr := freeAddr(t)
+	server, err := NewCatalogServer(Config{
+		ListenAddr:  addr,
+		Symlink:     dir,
+		TLSCertFile: certFile,
+		TLSKeyFile:  keyFile,
+		BearerToken: "secret",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	started := make(chan error, 1)
+	go func() {
+		started <- server.Start(context.Background())
+	}()
+
+	client := &http.Client{Transport: &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}}
+	get := func() (*http.Response, error) {
+		req, err := http.NewRequest(http.MethodGet, "https://"+addr+"/version.json", nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer secret")
+		return client.Do(req)
+	}
+	var resp *http.Response
+	for i := 0; i < 50; i++ {
+		if resp, err = get(); err == nil {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.TLS == nil {
+		t.Errorf("status = %v, tls = %v, want %v over tls", resp.StatusCode, resp.TLS != nil, http.StatusOK)
+	}
+
+	// a second server cannot listen on the same address
+	other, err := NewCatalogServer(Config{ListenAddr: addr, Symlink: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := other.Start(context.Background()); err == nil {
+		t.Errorf("Start() on an address in use should fail")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := server.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-started:
+		if err != nil {
+			t.Errorf("Start() error = %v after Stop()", err)
+		}
+	case <-ctx.Done():
+		t.Fatal("Start() did not return after Stop()")
+	}
+}
+
+func TestNewCatalogServer_validation(t *testing.T) {
+	if _, err := NewCatalogServer(Config{TLSCertFile: "tls.crt"}); err == nil {
+		t.Errorf("NewCatalogServer() with a cert but no key should fail")
+	}
+	if _, err := NewCatalogServer(Config{BasicAuthUsername: "user"}); err == nil {
+		t.Errorf("NewCatalogServer() with a username but no password should fail")
+	}
+}
+
+func TestEndpointClass(t *testing.T) {
+	const releaseSHA256 = "af3c54b86b90fac8977f1bdc80d955002dd3f441bdbb4cc603c94abbb929dcf6"
+	tests := map[string]string{
+		"/ws":                                    "websocket",
+		"/metrics":                               "metrics",
+		"/version.json":                          "version",
+		"/version.json.sig":                      "version",
+		"/query/integrations":                    "query",
+		"/resolve/nginx/nginx-monitoring":        "resolve",
+		"/catalog-graphql":                       "graphql",
+		"/" + releaseSHA256 + "/v1/catalog.json": "release",
+		"/" + releaseSHA256:                      "other",
+		"/releases.json":                         "other",
+		"/":                                      "other",
+	}
+	for path, want := range tests {
+		if got := endpointClass(path); got != want {
+			t.Errorf("endpointClass(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestNewCatalogServer_metrics(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "version.json"), []byte(`{}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	server, err := NewCatalogServer(Config{Symlink: dir, BearerToken: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.ObservePhase("checksum", time.Second)
+	server.ObserveBuild(Build{Duration: time.Second})
+
+	get := func(path string, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		server.server.Handler.ServeHTTP(rec, req)
+		return rec
+	}
+	get("/version.json", "secret")
+	get("/version.json", "")
+
+	// the metrics endpoint requires authentication like every other endpoint
+	if rec := get("/metrics", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("/metrics status = %v, want %v", rec.Code, http.StatusUnauthorized)
+	}
+	rec := get("/metrics", "secret")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("/metrics status = %v, want %v", rec.Code, http.StatusOK)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{
+		`catalog_api_http_requests_total{class="version",code="200"} 1`,
+		`catalog_api_http_requests_total{class="version",code="401"} 1`,
+		`catalog_api_http_requests_total{class="metrics",code="401"} 1`,
+		`catalog_api_builds_total{result="success"} 1`,
+		`catalog_api_build_phase_duration_seconds_count{phase="checksum"} 1`,
+		`catalog_api_websocket_clients 0`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("/metrics does not contain %q", want)
+		}
+	}
+}
+
+func TestNewCatalogServer_status(t *testing.T) {
+	const releaseSHA256 = "af3c54b86b90fac8977f1bdc80d955002dd3f441bdbb4cc603c94abbb929dcf6"
+	dir := t.TempDir()
+	version := `{"release_sha256":"` + releaseSHA256 + `","last_updated":1643664852}`
+	if err := os.WriteFile(filepath.Join(dir, "version.json"), []byte(version), 0600); err != nil {
+		t.Fatal(err)
+	}
+	server, err := NewCatalogServer(Config{Symlink: dir, BearerToken: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	get := func(path string, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		server.server.Handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// probes do not require authentication
+	if rec := get("/healthz", ""); rec.Code != http.StatusOK {
+		t.Errorf("/healthz status = %v, want %v", rec.Code, http.StatusOK)
+	}
+	if rec := get("/readyz", ""); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("/readyz status before a release is loaded = %v, want %v", rec.Code, http.StatusServiceUnavailable)
+	}
+
+	server.ObserveBuild(Build{Duration: time.Second, SourceCommit: "0123abc"})
+	server.reloadRelease()
+	if rec := get("/readyz", ""); rec.Code != http.StatusOK {
+		t.Errorf("/readyz status = %v, want %v", rec.Code, http.StatusOK)
+	}
+
+	// a failed rebuild leaves the release in place
+	server.ObserveBuild(Build{
+		Duration:     time.Second,
+		SourceCommit: "4567def",
+		Err:          errors.New("error loading integrations"),
+		ValidationErrors: catalogmanager.ValidationErrors{{
+			Namespace:   "nginx",
+			Integration: "nginx-monitoring",
+			Version:     "1.2.3",
+			Message:     "Failed to load logo",
+			Err:         errors.New("permission denied"),
+		}},
+	})
+	if rec := get("/readyz", ""); rec.Code != http.StatusOK {
+		t.Errorf("/readyz status after a failed build = %v, want %v", rec.Code, http.StatusOK)
+	}
+
+	if rec := get("/status.json", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("/status.json status = %v, want %v", rec.Code, http.StatusUnauthorized)
+	}
+	rec := get("/status.json", "secret")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("/status.json status = %v, want %v", rec.Code, http.StatusOK)
+	}
+	status := Status{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil {
+		t.Fatal(err)
+	}
+	if !status.Ready || status.Release == nil || status.LastBuild == nil {
+		t.Fatalf("status = %s, want a ready release & a last build", rec.Body.String())
+	}
+	wantRelease := ReleaseStatus{
+		ReleaseSHA256: releaseSHA256,
+		GeneratedAt:   time.Unix(1643664852, 0).UTC(),
+		SourceCommit:  "0123abc",
+	}
+	if *status.Release != wantRelease {
+		t.Errorf("release = %+v, want %+v", *status.Release, wantRelease)
+	}
+	if status.LastBuild.Succeeded || status.LastBuild.Error != "error loading integrations" {
+		t.Errorf("last build = %+v, want a failed build", *status.LastBuild)
+	}
+	wantValidation := []ValidationStatus{{
+		Namespace:   "nginx",
+		Integration: "nginx-monitoring",
+		Version:     "1.2.3",
+		Error:       "Failed to load logo: permission denied",
+	}}
+	if !reflect.DeepEqual(status.LastBuild.ValidationErrors, wantValidation) {
+		t.Errorf("validation errors = %+v, want %+v", status.LastBuild.ValidationErrors, wantValidation)
+	}
+}
@@ -0,0 +1,155 @@
+package catalogserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	catalogapiv1 "github.com/sensu/catalog-api/internal/api/catalogapi/v1"
+	"github.com/sensu/catalog-api/internal/catalogmanager"
+)
+
+// Build describes the outcome of a catalog build.
+type Build struct {
+	Duration     time.Duration
+	SourceCommit string
+
+	// Err is the error that the build failed with, if any.
+	Err error
+
+	// ValidationErrors are the validation failures of integrations, which do
+	// not fail the build.
+	ValidationErrors catalogmanager.ValidationErrors
+}
+
+// Status is the response body of the /status.json endpoint.
+type Status struct {
+	Ready     bool           `json:"ready"`
+	Release   *ReleaseStatus `json:"release"`
+	LastBuild *BuildStatus   `json:"last_build"`
+}
+
+// ReleaseStatus describes the release being served.
+type ReleaseStatus struct {
+	ReleaseSHA256 string    `json:"release_sha256"`
+	GeneratedAt   time.Time `json:"generated_at"`
+	SourceCommit  string    `json:"source_commit,omitempty"`
+}
+
+// BuildStatus describes the outcome of the most recent catalog build.
+type BuildStatus struct {
+	Succeeded        bool               `json:"succeeded"`
+	FinishedAt       time.Time          `json:"finished_at"`
+	DurationSeconds  float64            `json:"duration_seconds"`
+	Error            string             `json:"error,omitempty"`
+	ValidationErrors []ValidationStatus `json:"validation_errors"`
+}
+
+// ValidationStatus describes a validation failure of an integration version.
+type ValidationStatus struct {
+	Namespace   string `json:"namespace"`
+	Integration string `json:"integration"`
+	Version     string `json:"version"`
+	Error       string `json:"error"`
+}
+
+// statusTracker tracks the release being served & the outcome of the most
+// recent build. It is shared by the server & its handler.
+type statusTracker struct {
+	mu           sync.RWMutex
+	release      *ReleaseStatus
+	lastBuild    *BuildStatus
+	sourceCommit string
+}
+
+func newStatusTracker() *statusTracker {
+	return &statusTracker{}
+}
+
+// setBuild records the outcome of a build. The source commit of a
+// successful build is attributed to the release it produces.
+func (s *statusTracker) setBuild(build Build, finishedAt time.Time) {
+	status := &BuildStatus{
+		Succeeded:        build.Err == nil,
+		FinishedAt:       finishedAt.UTC(),
+		DurationSeconds:  build.Duration.Seconds(),
+		ValidationErrors: []ValidationStatus{},
+	}
+	if build.Err != nil {
+		status.Error = build.Err.Error()
+	}
+	for _, failure := range build.ValidationErrors {
+		status.ValidationErrors = append(status.ValidationErrors, ValidationStatus{
+			Namespace:   failure.Namespace,
+			Integration: failure.Integration,
+			Version:     failure.Version,
+			Error:       failure.Message + ": " + failure.Err.Error(),
+		})
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastBuild = status
+	if build.Err == nil {
+		s.sourceCommit = build.SourceCommit
+	}
+}
+
+// setRelease records the release that the version endpoint within dir
+// points to as the release being served. The release is cleared if it
+// cannot be read.
+func (s *statusTracker) setRelease(dir string) {
+	var release *ReleaseStatus
+	version := catalogapiv1.ReleaseVersion{}
+	b, err := os.ReadFile(catalogapiv1.NewVersionEndpoint(dir, version).GetOutputPath())
+	if err == nil {
+		err = json.Unmarshal(b, &version)
+	}
+	if err != nil || version.ReleaseSHA256 == "" {
+		log.Warn().Err(err).Msg("Failed to determine the release being served")
+	} else {
+		release = &ReleaseStatus{
+			ReleaseSHA256: version.ReleaseSHA256,
+			GeneratedAt:   time.Unix(version.LastUpdated, 0).UTC(),
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if release != nil {
+		release.SourceCommit = s.sourceCommit
+	}
+	s.release = release
+}
+
+func (s *statusTracker) status() Status {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return Status{
+		Ready:     s.release != nil,
+		Release:   s.release,
+		LastBuild: s.lastBuild,
+	}
+}
+
+func (h Handler) serveHealthz(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = w.Write([]byte("ok\n"))
+}
+
+func (h Handler) serveReadyz(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if !h.status.status().Ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte("no release loaded\n"))
+		return
+	}
+	_, _ = w.Write([]byte("ok\n"))
+}
+
+func (h Handler) serveStatus(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, h.status.status())
+}
@@ -0,0 +1,47 @@
+package catalogsign
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	catalogapiv1 "github.com/sensu/catalog-api/internal/api/catalogapi/v1"
+)
+
+var ErrSignatureNotFound = errors.New("signature not found")
+
+// SignFile returns the detached signature of the file at path.
+func SignFile(privateKey ed25519.PrivateKey, path string) (catalogapiv1.Signature, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return catalogapiv1.Signature{}, fmt.Errorf("error reading file to sign: %w", err)
+	}
+	return Sign(privateKey, b), nil
+}
+
+// VerifyFile verifies the file at path against its detached signature, which
+// is expected to be found alongside it. ErrSignatureNotFound is returned if
+// the file has not been signed.
+func VerifyFile(publicKey ed25519.PublicKey, path string) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading signed file: %w", err)
+	}
+
+	signature := catalogapiv1.Signature{}
+	sigPath := catalogapiv1.NewSignatureEndpoint(path, signature).GetOutputPath()
+	sigb, err := os.ReadFile(sigPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrSignatureNotFound
+	} else if err != nil {
+		return fmt.Errorf("error reading signature: %w", err)
+	}
+	if err := json.Unmarshal(sigb, &signature); err != nil {
+		return fmt.Errorf("error unmarshaling signature: %w", err)
+	}
+
+	return Verify(publicKey, b, signature)
+}
@@ -0,0 +1,135 @@
+package catalogsign
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+
+	catalogapiv1 "github.com/sensu/catalog-api/internal/api/catalogapi/v1"
+)
+
+const (
+	Algorithm = "ed25519"
+
+	pemTypePrivateKey = "PRIVATE KEY"
+	pemTypePublicKey  = "PUBLIC KEY"
+)
+
+var (
+	ErrKeyIDMismatch    = errors.New("signature key id does not match public key")
+	ErrInvalidSignature = errors.New("invalid signature")
+)
+
+// GenerateKey generates a new ed25519 key pair.
+func GenerateKey() (ed25519.PublicKey, ed25519.PrivateKey, error) {
+	return ed25519.GenerateKey(rand.Reader)
+}
+
+// KeyID returns the ID of a public key, the first 8 bytes of the sha256
+// checksum of the key, hex encoded.
+func KeyID(publicKey ed25519.PublicKey) string {
+	sum := sha256.Sum256(publicKey)
+	return fmt.Sprintf("%x", sum[:8])
+}
+
+// Sign returns the detached signature of data.
+func Sign(privateKey ed25519.PrivateKey, data []byte) catalogapiv1.Signature {
+	publicKey := privateKey.Public().(ed25519.PublicKey)
+	return catalogapiv1.Signature{
+		Algorithm: Algorithm,
+		KeyID:     KeyID(publicKey),
+		Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, data)),
+	}
+}
+
+// Verify checks that signature is a valid signature of data made by the
+// private key corresponding to publicKey.
+func Verify(publicKey ed25519.PublicKey, data []byte, signature catalogapiv1.Signature) error {
+	if signature.Algorithm != Algorithm {
+		return fmt.Errorf("unsupported signature algorithm: %s", signature.Algorithm)
+	}
+	if signature.KeyID != KeyID(publicKey) {
+		return ErrKeyIDMismatch
+	}
+	sig, err := base64.StdEncoding.DecodeString(signature.Signature)
+	if err != nil {
+		return fmt.Errorf("error base64 decoding signature: %w", err)
+	}
+	if !ed25519.Verify(publicKey, data, sig) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// MarshalPrivateKey encodes a private key as a PKCS #8 PEM block, as
+// generated by `openssl genpkey -algorithm ed25519`.
+func MarshalPrivateKey(privateKey ed25519.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling private key: %w", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: pemTypePrivateKey, Bytes: der}), nil
+}
+
+// MarshalPublicKey encodes a public key as a PKIX PEM block.
+func MarshalPublicKey(publicKey ed25519.PublicKey) ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling public key: %w", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: pemTypePublicKey, Bytes: der}), nil
+}
+
+func ParsePrivateKey(data []byte) (ed25519.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != pemTypePrivateKey {
+		return nil, fmt.Errorf("no %s pem block found", pemTypePrivateKey)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing private key: %w", err)
+	}
+	privateKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key is not an ed25519 key: %T", key)
+	}
+	return privateKey, nil
+}
+
+func ParsePublicKey(data []byte) (ed25519.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != pemTypePublicKey {
+		return nil, fmt.Errorf("no %s pem block found", pemTypePublicKey)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing public key: %w", err)
+	}
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key is not an ed25519 key: %T", key)
+	}
+	return publicKey, nil
+}
+
+func LoadPrivateKey(path string) (ed25519.PrivateKey, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading private key: %w", err)
+	}
+	return ParsePrivateKey(b)
+}
+
+func LoadPublicKey(path string) (ed25519.PublicKey, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading public key: %w", err)
+	}
+	return ParsePublicKey(b)
+}
@@ -0,0 +1,125 @@
+package catalogsign
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	catalogapiv1 "github.com/sensu/catalog-api/internal/api/catalogapi/v1"
+)
+
+func TestKeyRoundTrip(t *testing.T) {
+	publicKey, privateKey, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	privPEM, err := MarshalPrivateKey(privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsedPriv, err := ParsePrivateKey(privPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !parsedPriv.Equal(privateKey) {
+		t.Errorf("ParsePrivateKey() did not round trip")
+	}
+
+	pubPEM, err := MarshalPublicKey(publicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsedPub, err := ParsePublicKey(pubPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !parsedPub.Equal(publicKey) {
+		t.Errorf("ParsePublicKey() did not round trip")
+	}
+
+	if _, err := ParsePublicKey(privPEM); err == nil {
+		t.Errorf("ParsePublicKey() of a private key should fail")
+	}
+	if _, err := ParsePrivateKey([]byte("garbage")); err == nil {
+		t.Errorf("ParsePrivateKey() of garbage should fail")
+	}
+}
+
+func TestVerifyFile(t *testing.T) {
+	publicKey, privateKey, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, otherPrivateKey, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sign := func(t *testing.T, path string, key ed25519.PrivateKey) {
+		signature, err := SignFile(key, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := json.Marshal(signature)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sigPath := catalogapiv1.NewSignatureEndpoint(path, signature).GetOutputPath()
+		if err := os.WriteFile(sigPath, b, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		setup   func(t *testing.T, path string)
+		wantErr error
+	}{
+		{
+			name: "valid signature",
+			setup: func(t *testing.T, path string) {
+				sign(t, path, privateKey)
+			},
+		},
+		{
+			name:    "unsigned",
+			setup:   func(t *testing.T, path string) {},
+			wantErr: ErrSignatureNotFound,
+		},
+		{
+			name: "tampered file",
+			setup: func(t *testing.T, path string) {
+				sign(t, path, privateKey)
+				if err := os.WriteFile(path, []byte(`{"release_sha256":"tampered"}`), 0600); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name: "signed with another key",
+			setup: func(t *testing.T, path string) {
+				sign(t, path, otherPrivateKey)
+			},
+			wantErr: ErrKeyIDMismatch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "version.json")
+			if err := os.WriteFile(path, []byte(`{"release_sha256":"abc"}`), 0600); err != nil {
+				t.Fatal(err)
+			}
+			tt.setup(t, path)
+
+			err := VerifyFile(publicKey, path)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("VerifyFile() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
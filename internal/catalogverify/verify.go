@@ -0,0 +1,288 @@
+package catalogverify
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	catalogapiv1 "github.com/sensu/catalog-api/internal/api/catalogapi/v1"
+	"github.com/sensu/catalog-api/internal/catalogsign"
+	"github.com/sensu/catalog-api/internal/endpoints"
+	"github.com/sensu/catalog-api/internal/util"
+)
+
+// Failure describes a single verification failure. Path is relative to the
+// release directory and is empty for failures concerning the whole release.
+type Failure struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (f Failure) String() string {
+	if f.Path == "" {
+		return f.Message
+	}
+	return fmt.Sprintf("%s: %s", f.Path, f.Message)
+}
+
+// Result is the outcome of verifying a release.
+type Result struct {
+	ReleaseSHA256 string    `json:"release_sha256"`
+	Files         int       `json:"files"`
+	Versions      int       `json:"versions"`
+	Failures      []Failure `json:"failures"`
+}
+
+func (r Result) OK() bool {
+	return len(r.Failures) == 0
+}
+
+func (r *Result) fail(path string, format string, args ...interface{}) {
+	r.Failures = append(r.Failures, Failure{
+		Path:    path,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// ReleasePath determines the checksum & directory of the release to verify.
+// dir may either contain the version endpoint, in which case the release it
+// points to is used, or be the directory of a single release.
+func ReleasePath(dir string) (sha256 string, releaseDir string, err error) {
+	version := catalogapiv1.ReleaseVersion{}
+	versionPath := catalogapiv1.NewVersionEndpoint(dir, version).GetOutputPath()
+	b, err := os.ReadFile(versionPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return "", "", err
+		}
+		sha256 = filepath.Base(abs)
+		if !catalogapiv1.IsReleaseChecksum(sha256) {
+			return "", "", fmt.Errorf("%s is neither a release directory nor contains %s", dir, filepath.Base(versionPath))
+		}
+		return sha256, abs, nil
+	} else if err != nil {
+		return "", "", fmt.Errorf("error reading version endpoint: %w", err)
+	}
+
+	if err := json.Unmarshal(b, &version); err != nil {
+		return "", "", fmt.Errorf("error unmarshaling version endpoint: %w", err)
+	}
+	if !catalogapiv1.IsReleaseChecksum(version.ReleaseSHA256) {
+		return "", "", fmt.Errorf("version endpoint points to invalid release checksum %q", version.ReleaseSHA256)
+	}
+	return version.ReleaseSHA256, filepath.Join(dir, version.ReleaseSHA256), nil
+}
+
+// Verify recomputes the release checksum, the digest of every file listed in
+// the release manifest and the content digest of every integration version,
+// and compares them against the published values. If publicKey is not nil,
+// the signatures of the manifest & of the version endpoint, when dir contains
+// one, are verified as well and unsigned releases are rejected. An error is
+// only returned if verification could not be performed; mismatches are
+// reported as failures within the result.
+func Verify(dir string, publicKey ed25519.PublicKey) (Result, error) {
+	result := Result{
+		Failures: []Failure{},
+	}
+
+	sha256, releaseDir, err := ReleasePath(dir)
+	if err != nil {
+		return result, err
+	}
+	result.ReleaseSHA256 = sha256
+	if info, err := os.Stat(releaseDir); err != nil || !info.IsDir() {
+		return result, fmt.Errorf("release directory not found: %s", releaseDir)
+	}
+
+	// the release checksum covers every file of the release
+	checksum, err := util.CalculateDirChecksum(releaseDir, "staging", catalogapiv1.IsCompressedSibling)
+	if err != nil {
+		return result, err
+	}
+	if checksum != sha256 {
+		result.fail("", "release checksum mismatch: got %s", checksum)
+	}
+
+	if publicKey != nil {
+		verifySignatures(dir, releaseDir, publicKey, &result)
+	}
+	if err := verifyManifest(releaseDir, &result); err != nil {
+		return result, err
+	}
+	if err := verifyVersions(releaseDir, &result); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+func verifySignatures(dir string, releaseDir string, publicKey ed25519.PublicKey, result *Result) {
+	keyID := catalogsign.KeyID(publicKey)
+
+	version := catalogapiv1.ReleaseVersion{}
+	versionPath := catalogapiv1.NewVersionEndpoint(dir, version).GetOutputPath()
+	if _, err := os.Stat(versionPath); err == nil {
+		rel := filepath.Base(versionPath)
+		if err := catalogsign.VerifyFile(publicKey, versionPath); err != nil {
+			result.fail(rel, "signature verification failed: %s", err)
+		}
+		if err := readJSON(versionPath, &version); err == nil && version.PublicKeyID != keyID {
+			result.fail(rel, "public_key_id mismatch: got %q, want %q", version.PublicKeyID, keyID)
+		}
+	}
+
+	manifestPath := catalogapiv1.NewManifestEndpoint(releaseDir, catalogapiv1.Manifest{}).GetOutputPath()
+	if err := catalogsign.VerifyFile(publicKey, manifestPath); err != nil {
+		result.fail(catalogapiv1.ManifestFileName, "signature verification failed: %s", err)
+	}
+}
+
+func verifyManifest(releaseDir string, result *Result) error {
+	manifest := catalogapiv1.Manifest{}
+	manifestPath := catalogapiv1.NewManifestEndpoint(releaseDir, manifest).GetOutputPath()
+	if err := readJSON(manifestPath, &manifest); errors.Is(err, fs.ErrNotExist) {
+		result.fail(catalogapiv1.ManifestFileName, "manifest not found")
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	digests, err := util.CalculateFileDigests(releaseDir, catalogapiv1.ManifestFileName, catalogapiv1.ManifestFileName+catalogapiv1.SignatureExt)
+	if err != nil {
+		return err
+	}
+	// compressed siblings are not listed in the manifest; they are verified
+	// against the endpoint they were compressed from instead
+	actual := map[string]util.FileDigest{}
+	uncompressed := []util.FileDigest{}
+	for _, digest := range digests {
+		if catalogapiv1.IsCompressedSibling(digest.Path) {
+			if err := verifyCompressed(releaseDir, digest.Path, result); err != nil {
+				return err
+			}
+			continue
+		}
+		actual[digest.Path] = digest
+		uncompressed = append(uncompressed, digest)
+	}
+
+	listed := map[string]bool{}
+	for _, file := range manifest.Files {
+		listed[file.Path] = true
+		result.Files++
+
+		digest, ok := actual[file.Path]
+		switch {
+		case !ok:
+			result.fail(file.Path, "file listed in manifest not found")
+		case digest.Size != file.Size:
+			result.fail(file.Path, "size mismatch: manifest = %d, got %d", file.Size, digest.Size)
+		case digest.SHA256 != file.SHA256:
+			result.fail(file.Path, "sha256 mismatch: manifest = %s, got %s", file.SHA256, digest.SHA256)
+		}
+	}
+	for _, digest := range uncompressed {
+		if !listed[digest.Path] {
+			result.fail(digest.Path, "file not listed in manifest")
+		}
+	}
+
+	return nil
+}
+
+// verifyCompressed verifies that the compressed sibling at rel decompresses
+// to the contents of the endpoint it was compressed from.
+func verifyCompressed(releaseDir string, rel string, result *Result) error {
+	endpointRel, encoding, _ := catalogapiv1.SplitCompressedPath(rel)
+	compressed, err := os.ReadFile(filepath.Join(releaseDir, filepath.FromSlash(rel)))
+	if err != nil {
+		return err
+	}
+	original, err := os.ReadFile(filepath.Join(releaseDir, filepath.FromSlash(endpointRel)))
+	if errors.Is(err, fs.ErrNotExist) {
+		result.fail(rel, "compressed file has no uncompressed original")
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	decompressed, err := endpoints.Decompress(encoding, compressed)
+	if err != nil {
+		result.fail(rel, "error decompressing file: %s", err)
+	} else if !bytes.Equal(decompressed, original) {
+		result.fail(rel, "compressed contents do not match %s", endpointRel)
+	}
+	return nil
+}
+
+func verifyVersions(releaseDir string, result *Result) error {
+	catalog := catalogapiv1.Catalog{}
+	catalogPath := catalogapiv1.NewCatalogEndpoint(releaseDir, catalog).GetOutputPath()
+	if err := readJSON(catalogPath, &catalog); err != nil {
+		result.fail(relPath(releaseDir, catalogPath), "error reading catalog: %s", err)
+		return nil
+	}
+
+	for _, nsIntegrations := range catalog.NamespacedIntegrations {
+		for _, integration := range nsIntegrations {
+			namespace, name := integration.Metadata.Namespace, integration.Metadata.Name
+
+			versions := catalogapiv1.IntegrationVersions{}
+			versionsPath := catalogapiv1.NewIntegrationVersionsEndpoint(releaseDir, namespace, name, versions).GetOutputPath()
+			if err := readJSON(versionsPath, &versions); err != nil {
+				result.fail(relPath(releaseDir, versionsPath), "error reading integration versions: %s", err)
+				continue
+			}
+
+			for _, v := range versions {
+				result.Versions++
+				iv := catalogapiv1.IntegrationVersion{
+					Integration: integration.Integration,
+					Version:     v,
+				}
+				versionPath := catalogapiv1.NewIntegrationVersionEndpoint(releaseDir, iv).GetOutputPath()
+				rel := relPath(releaseDir, versionPath)
+
+				if err := readJSON(versionPath, &iv); err != nil {
+					result.fail(rel, "error reading integration version: %s", err)
+					continue
+				}
+				if iv.ContentSHA256 == "" {
+					result.fail(rel, "content_sha256 not set")
+					continue
+				}
+				contentSHA256, err := util.CalculateDirChecksum(catalogapiv1.IntegrationVersionDir(releaseDir, iv), "", catalogapiv1.IsCompressedSibling)
+				if err != nil {
+					return err
+				}
+				if contentSHA256 != iv.ContentSHA256 {
+					result.fail(rel, "content_sha256 mismatch: got %s", contentSHA256)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func relPath(releaseDir string, path string) string {
+	rel, err := filepath.Rel(releaseDir, path)
+	if err != nil {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
+
+func readJSON(path string, v interface{}) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
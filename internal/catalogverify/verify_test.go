@@ -0,0 +1,268 @@
+package catalogverify
+
+import (
+	"crypto/ed25519"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	catalogapiv1 "github.com/sensu/catalog-api/internal/api/catalogapi/v1"
+	"github.com/sensu/catalog-api/internal/catalogmanager"
+	"github.com/sensu/catalog-api/internal/catalogmanager/catalogmanagertest"
+	"github.com/sensu/catalog-api/internal/catalogsign"
+	"github.com/sensu/catalog-api/internal/endpoints"
+	"github.com/sensu/catalog-api/internal/types"
+)
+
+// generateRelease generates a release of a fixture integration and returns
+// the release directory. The release is signed if signingKey is not nil.
+func generateRelease(t *testing.T, signingKey ed25519.PrivateKey, compress bool) string {
+	t.Helper()
+
+	integrations := types.Integrations{
+		types.FixtureIntegrationVersion("example_ns", "example", 1, 2, 3),
+		types.FixtureIntegrationVersion("example_ns", "example", 1, 3, 0),
+	}
+	config := catalogmanager.Config{SigningKey: signingKey, Compress: compress}
+	return catalogmanagertest.GenerateRelease(t, config, integrations)
+}
+
+func TestVerify(t *testing.T) {
+	tests := []struct {
+		name         string
+		tamper       func(t *testing.T, releaseDir string)
+		wantFailures []string
+	}{
+		{
+			name:   "untampered release",
+			tamper: func(t *testing.T, releaseDir string) {},
+		},
+		{
+			name: "modified file",
+			tamper: func(t *testing.T, releaseDir string) {
+				path := filepath.Join(releaseDir, "v1", "example_ns", "example", "1.3.0", "README.md")
+				if err := os.WriteFile(path, []byte("tampered"), 0600); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantFailures: []string{
+				"release checksum mismatch",
+				"v1/example_ns/example/1.3.0/README.md: size mismatch",
+				"v1/example_ns/example/1.3.0.json: content_sha256 mismatch",
+			},
+		},
+		{
+			name: "added & removed files",
+			tamper: func(t *testing.T, releaseDir string) {
+				if err := os.Remove(filepath.Join(releaseDir, "v1", "example_ns", "example", "1.2.3", "img", "image.png")); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filepath.Join(releaseDir, "v1", "extra.json"), []byte("{}"), 0600); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantFailures: []string{
+				"release checksum mismatch",
+				"v1/example_ns/example/1.2.3/img/image.png: file listed in manifest not found",
+				"v1/extra.json: file not listed in manifest",
+				"v1/example_ns/example/1.2.3.json: content_sha256 mismatch",
+			},
+		},
+		{
+			name: "missing manifest",
+			tamper: func(t *testing.T, releaseDir string) {
+				if err := os.Remove(filepath.Join(releaseDir, "manifest.json")); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantFailures: []string{
+				"release checksum mismatch",
+				"manifest.json: manifest not found",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := generateRelease(t, nil, false)
+			sha256, releaseDir, err := ReleasePath(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.tamper(t, releaseDir)
+
+			// verifying the parent dir & the release dir itself must be
+			// equivalent
+			for _, path := range []string{dir, releaseDir} {
+				result, err := Verify(path, nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if result.ReleaseSHA256 != sha256 {
+					t.Errorf("Verify() release = %v, want %v", result.ReleaseSHA256, sha256)
+				}
+				if result.Versions != 2 {
+					t.Errorf("Verify() versions = %v, want 2", result.Versions)
+				}
+				if len(result.Failures) != len(tt.wantFailures) {
+					t.Fatalf("Verify() failures = %v, want %v", result.Failures, tt.wantFailures)
+				}
+				for i, failure := range result.Failures {
+					if !strings.HasPrefix(failure.String(), tt.wantFailures[i]) {
+						t.Errorf("Verify() failure = %v, want %v", failure, tt.wantFailures[i])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestVerify_Signatures(t *testing.T) {
+	publicKey, privateKey, err := catalogsign.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherPublicKey, _, err := catalogsign.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		signingKey   ed25519.PrivateKey
+		publicKey    ed25519.PublicKey
+		tamper       func(t *testing.T, dir string)
+		wantFailures []string
+	}{
+		{
+			name:       "signed release",
+			signingKey: privateKey,
+			publicKey:  publicKey,
+		},
+		{
+			name:       "signature not checked without public key",
+			signingKey: privateKey,
+		},
+		{
+			name:      "unsigned release",
+			publicKey: publicKey,
+			wantFailures: []string{
+				"version.json: signature verification failed: signature not found",
+				`version.json: public_key_id mismatch: got ""`,
+				"manifest.json: signature verification failed: signature not found",
+			},
+		},
+		{
+			name:       "signed with another key",
+			signingKey: privateKey,
+			publicKey:  otherPublicKey,
+			wantFailures: []string{
+				"version.json: signature verification failed: signature key id does not match public key",
+				"version.json: public_key_id mismatch",
+				"manifest.json: signature verification failed: signature key id does not match public key",
+			},
+		},
+		{
+			name:       "tampered version endpoint",
+			signingKey: privateKey,
+			publicKey:  publicKey,
+			tamper: func(t *testing.T, dir string) {
+				path := filepath.Join(dir, "version.json")
+				b, err := os.ReadFile(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				b = []byte(strings.Replace(string(b), `"last_updated":`, `"last_updated":1`, 1))
+				if err := os.WriteFile(path, b, 0600); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantFailures: []string{
+				"version.json: signature verification failed: invalid signature",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := generateRelease(t, tt.signingKey, false)
+			if tt.tamper != nil {
+				tt.tamper(t, dir)
+			}
+
+			result, err := Verify(dir, tt.publicKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(result.Failures) != len(tt.wantFailures) {
+				t.Fatalf("Verify() failures = %v, want %v", result.Failures, tt.wantFailures)
+			}
+			for i, failure := range result.Failures {
+				if !strings.HasPrefix(failure.String(), tt.wantFailures[i]) {
+					t.Errorf("Verify() failure = %v, want %v", failure, tt.wantFailures[i])
+				}
+			}
+		})
+	}
+}
+
+func TestVerify_Compressed(t *testing.T) {
+	sha256, _, err := ReleasePath(generateRelease(t, nil, false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := generateRelease(t, nil, true)
+	compressedSHA256, releaseDir, err := ReleasePath(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// compressed siblings are not part of the release
+	if compressedSHA256 != sha256 {
+		t.Errorf("release checksum with compressed endpoints = %v, want %v", compressedSHA256, sha256)
+	}
+	for _, name := range []string{"catalog.json.br", "catalog.json.gz"} {
+		if _, err := os.Stat(filepath.Join(releaseDir, "v1", name)); err != nil {
+			t.Errorf("compressed endpoint not generated: %v", err)
+		}
+	}
+	result, err := Verify(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.OK() {
+		t.Fatalf("Verify() failures = %v, want none", result.Failures)
+	}
+
+	// a compressed sibling that does not match its endpoint must be reported
+	tampered, err := endpoints.Compress(catalogapiv1.EncodingGzip, []byte(`{"tampered":true}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(releaseDir, "v1", "catalog.json.gz"), tampered, 0600); err != nil {
+		t.Fatal(err)
+	}
+	result, err = Verify(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "v1/catalog.json.gz: compressed contents do not match v1/catalog.json"
+	if len(result.Failures) != 1 || result.Failures[0].String() != want {
+		t.Errorf("Verify() failures = %v, want %v", result.Failures, want)
+	}
+}
+
+func TestReleasePath_InvalidChecksum(t *testing.T) {
+	tests := []string{"", "../../etc", strings.Repeat("A", 64)}
+	for _, checksum := range tests {
+		t.Run(checksum, func(t *testing.T) {
+			dir := t.TempDir()
+			version := `{"release_sha256":"` + checksum + `","last_updated":0}`
+			if err := os.WriteFile(filepath.Join(dir, "version.json"), []byte(version), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if _, releaseDir, err := ReleasePath(dir); err == nil {
+				t.Errorf("ReleasePath() = %v, want error", releaseDir)
+			}
+		})
+	}
+}
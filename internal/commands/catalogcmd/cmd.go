@@ -3,30 +3,103 @@ package catalogcmd
 import (
 	"context"
 	"flag"
+	"fmt"
 	"os"
+	"time"
 
 	"github.com/peterbourgon/ff/v3/ffcli"
 	"github.com/sensu/catalog-api/internal/commands/rootcmd"
+	"github.com/sensu/catalog-api/internal/diagnostics"
+	"github.com/sensu/catalog-api/internal/repoconfig"
 )
 
 var (
 	defaultIntegrationsDirName = "integrations"
 	defaultRepoDir             = "."
+	defaultConfigPath          = ""
 	defaultTempDir             = os.TempDir()
 	defaultSnapshot            = false
 	defaultWatchMode           = false
 	defaultApiURL              = "http://localhost:8080"
+	defaultQueryAPI            = false
+	defaultGraphQL             = false
+	defaultReleasesDir         = ""
+	defaultDiffFormat          = diffFormatText
+	defaultSigningKey          = ""
+	defaultPublicKey           = ""
+	defaultKeepReleases        = 0
+	defaultKeepWithin          = time.Duration(0)
+	defaultReproducible        = false
+	defaultOutput              = ""
+	defaultS3Endpoint          = ""
+	defaultS3Region            = ""
+	defaultCompress            = false
+	defaultTLSCert             = ""
+	defaultTLSKey              = ""
+	defaultAuthToken           = ""
+	defaultBasicAuth           = ""
+	defaultCORSOrigins         = "*"
+	defaultValidateFormat      = diagnostics.FormatText
+	defaultValidateOutput      = ""
+	defaultValidateSource      = validateSourcePath
+	defaultValidateSince       = ""
+	defaultLint                = true
+	defaultListLintRules       = false
+	defaultKeepGoing           = false
+	defaultMaxExcluded         = 0
+	defaultNewDisplayName      = ""
+	defaultNewShortDescription = ""
+	defaultNewProvider         = ""
+	defaultNewClass            = ""
+	defaultNewPlatforms        = ""
+	defaultNewContributors     = ""
+	defaultNewPrompt           = true
 )
 
 type Config struct {
 	rootConfig          rootcmd.Config
 	repoDir             string
+	configPath          string
+	repoConfig          repoconfig.Config
 	tempDir             string
 	integrationsDirName string
 	snapshot            bool
 	watch               bool
 	port                int
 	apiURL              string
+	queryAPI            bool
+	graphql             bool
+	releasesDir         string
+	diffFormat          string
+	signingKey          string
+	publicKey           string
+	keepReleases        int
+	keepWithin          time.Duration
+	reproducible        bool
+	output              string
+	s3Endpoint          string
+	s3Region            string
+	compress            bool
+	tlsCert             string
+	tlsKey              string
+	authToken           string
+	basicAuth           string
+	corsOrigins         string
+	validateFormat      string
+	validateOutput      string
+	validateSource      string
+	validateSince       string
+	lint                bool
+	listLintRules       bool
+	keepGoing           bool
+	maxExcluded         int
+	newDisplayName      string
+	newShortDescription string
+	newProvider         string
+	newClass            string
+	newPlatforms        string
+	newContributors     string
+	newPrompt           bool
 }
 
 func New(rootConfig rootcmd.Config) *ffcli.Command {
@@ -42,10 +115,17 @@ func New(rootConfig rootcmd.Config) *ffcli.Command {
 		ShortUsage: "catalog-api catalog [flags] <subcommand> [flags]",
 		ShortHelp:  "Validate a Catalog and its integrations",
 		FlagSet:    fs,
+		Options:    rootcmd.ParseOptions(),
 		Exec:       cfg.Exec,
 		Subcommands: []*ffcli.Command{
+			cfg.NewCommand(),
 			cfg.GenerateCommand(),
 			cfg.ValidateCommand(),
+			cfg.LintCommand(),
+			cfg.DiffCommand(),
+			cfg.VerifyCommand(),
+			cfg.KeygenCommand(),
+			cfg.RollbackCommand(),
 			cfg.ServerCommand(),
 			cfg.PreviewCommand(),
 		},
@@ -62,6 +142,7 @@ func (c *Config) RegisterFlags(fs *flag.FlagSet) {
 
 func (c *Config) RegisterCatalogFlags(fs *flag.FlagSet) {
 	fs.StringVar(&c.repoDir, "repo-dir", defaultRepoDir, "path to the catalog repository")
+	fs.StringVar(&c.configPath, "config", defaultConfigPath, fmt.Sprintf("path to the catalog config (defaults to %s within the repo dir)", repoconfig.FileName))
 	fs.StringVar(&c.integrationsDirName, "integrations-dir-name", defaultIntegrationsDirName, "path to the directory containing namespaced integrations")
 }
 
@@ -0,0 +1,143 @@
+package catalogcmd
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/peterbourgon/ff/v3/ffcli"
+	"github.com/rs/zerolog/log"
+	catalogapiv1 "github.com/sensu/catalog-api/internal/api/catalogapi/v1"
+	"github.com/sensu/catalog-api/internal/catalogdiff"
+	"github.com/sensu/catalog-api/internal/catalogloader"
+	"github.com/sensu/catalog-api/internal/catalogquery"
+	"github.com/sensu/catalog-api/internal/commands/rootcmd"
+)
+
+const (
+	diffFormatText = "text"
+	diffFormatJSON = "json"
+)
+
+func (c *Config) DiffCommand() *ffcli.Command {
+	fs := flag.NewFlagSet("catalog-api catalog diff", flag.ExitOnError)
+
+	// register catalog diff flags
+	c.RegisterDiffFlags(fs)
+
+	// register catalog & global flags
+	c.RegisterFlags(fs)
+
+	return &ffcli.Command{
+		Name:       "diff",
+		ShortUsage: "catalog-api catalog diff [flags] <old> <new>",
+		ShortHelp:  "Compare two catalog releases",
+		LongHelp: "Compare two catalog releases. Each release may be specified as a release\n" +
+			"directory, a release checksum within --releases-dir, or a git ref of the\n" +
+			"catalog repository to generate the release from.",
+		FlagSet: fs,
+		Options: rootcmd.ParseOptions(),
+		Exec:    c.rootConfig.PreExec(c.withRepoConfig(fs, c.execDiff)),
+	}
+}
+
+func (c *Config) RegisterDiffFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.tempDir, "temp-dir", defaultTempDir, "path to a temporary directory for generated files")
+	fs.StringVar(&c.releasesDir, "releases-dir", defaultReleasesDir, "path to a directory containing published releases, used to look up release checksums")
+	fs.StringVar(&c.diffFormat, "format", defaultDiffFormat, "output format, one of text or json")
+}
+
+func (c *Config) execDiff(ctx context.Context, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("diff requires exactly 2 arguments, got: %d", len(args))
+	}
+	if c.diffFormat != diffFormatText && c.diffFormat != diffFormatJSON {
+		return fmt.Errorf("format must be one of %s or %s, got: %s", diffFormatText, diffFormatJSON, c.diffFormat)
+	}
+
+	oldRelease, err := c.loadDiffRelease(ctx, args[0])
+	if err != nil {
+		return fmt.Errorf("error loading release %s: %w", args[0], err)
+	}
+	newRelease, err := c.loadDiffRelease(ctx, args[1])
+	if err != nil {
+		return fmt.Errorf("error loading release %s: %w", args[1], err)
+	}
+
+	report, err := catalogdiff.Diff(oldRelease, newRelease)
+	if err != nil {
+		return fmt.Errorf("error comparing releases: %w", err)
+	}
+
+	if c.diffFormat == diffFormatJSON {
+		return report.WriteJSON(os.Stdout)
+	}
+	return report.WriteText(os.Stdout)
+}
+
+// loadDiffRelease loads a release from a release directory, a release
+// checksum or a git ref, in that order of precedence.
+func (c *Config) loadDiffRelease(ctx context.Context, arg string) (*catalogquery.Release, error) {
+	if info, err := os.Stat(arg); err == nil && info.IsDir() {
+		return loadReleaseDir(arg)
+	}
+
+	if catalogapiv1.IsReleaseChecksum(arg) {
+		if c.releasesDir == "" {
+			return nil, errors.New("--releases-dir must be set to look up a release checksum")
+		}
+		return catalogquery.LoadReleaseChecksum(c.releasesDir, arg)
+	}
+
+	return c.generateReleaseAtRevision(ctx, arg)
+}
+
+// loadReleaseDir loads a release from either a directory containing the
+// version endpoint or from the directory of a single release checksum.
+func loadReleaseDir(dir string) (*catalogquery.Release, error) {
+	versionPath := catalogapiv1.NewVersionEndpoint(dir, catalogapiv1.ReleaseVersion{}).GetOutputPath()
+	if _, err := os.Stat(versionPath); errors.Is(err, fs.ErrNotExist) {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return nil, err
+		}
+		if sha256 := filepath.Base(abs); catalogapiv1.IsReleaseChecksum(sha256) {
+			return catalogquery.LoadReleaseChecksum(filepath.Dir(abs), sha256)
+		}
+	}
+	return catalogquery.LoadRelease(dir)
+}
+
+// generateReleaseAtRevision generates the release of the catalog as of the
+// given git revision, i.e. from the integration versions that had been
+// tagged by then.
+func (c *Config) generateReleaseAtRevision(ctx context.Context, revision string) (*catalogquery.Release, error) {
+	repo, err := git.PlainOpen(c.repoDir)
+	if err != nil {
+		return nil, err
+	}
+	loader, err := catalogloader.NewGitLoaderAtRevision(repo, c.integrationsDirName, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	cm, err := c.newCatalogManager(loader, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := os.RemoveAll(cm.tmpdir); err != nil {
+			log.Warn().Err(err).Str("path", cm.tmpdir).Msg("Failed to remove temp directory")
+		}
+	}()
+
+	if err := cm.ProcessCatalog(); err != nil {
+		return nil, err
+	}
+	return catalogquery.LoadRelease(filepath.Join(cm.tmpdir, "release"))
+}
@@ -2,17 +2,30 @@ package catalogcmd
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
 	"os/signal"
 	"path"
+	"strconv"
+	"strings"
 	"syscall"
+	"time"
 
+	"github.com/go-git/go-git/v5"
 	"github.com/peterbourgon/ff/v3/ffcli"
 	"github.com/rs/zerolog/log"
+	"github.com/sensu/catalog-api/internal/catalogpublish"
+	cmderrors "github.com/sensu/catalog-api/internal/commands/errors"
+	"github.com/sensu/catalog-api/internal/commands/rootcmd"
+	"github.com/sensu/catalog-api/internal/output"
 )
 
+// exitStatusPartialRelease is the exit status of the generate command when
+// integration versions were excluded from the release in keep-going mode.
+const exitStatusPartialRelease = 2
+
 func (c *Config) GenerateCommand() *ffcli.Command {
 	fs := flag.NewFlagSet("catalog-api catalog generate", flag.ExitOnError)
 
@@ -27,7 +40,8 @@ func (c *Config) GenerateCommand() *ffcli.Command {
 		ShortUsage: "catalog-api catalog generate [flags]",
 		ShortHelp:  "Generate a static catalog API",
 		FlagSet:    fs,
-		Exec:       c.rootConfig.PreExec(c.execGenerate),
+		Options:    rootcmd.ParseOptions(),
+		Exec:       c.rootConfig.PreExec(c.withRepoConfig(fs, c.execGenerate)),
 	}
 }
 
@@ -35,9 +49,26 @@ func (c *Config) RegisterGenerateFlags(fs *flag.FlagSet) {
 	fs.StringVar(&c.tempDir, "temp-dir", defaultTempDir, "path to a temporary directory for generated files")
 	fs.BoolVar(&c.snapshot, "snapshot", defaultSnapshot, "generate a catalog api for the current catalog branch")
 	fs.BoolVar(&c.watch, "watch", defaultWatchMode, "enter watch mode, which rebuilds on file change")
+	fs.BoolVar(&c.reproducible, "reproducible", defaultReproducible, "derive the release timestamp from git tag & commit dates instead of the current time; SOURCE_DATE_EPOCH takes precedence when set")
+	fs.BoolVar(&c.compress, "compress", defaultCompress, "also write gzip & brotli compressed copies of json, markdown & svg endpoints")
+	fs.StringVar(&c.signingKey, "signing-key", defaultSigningKey, "path to an ed25519 private key used to sign the release")
+	fs.StringVar(&c.releasesDir, "releases-dir", defaultReleasesDir, "path to a directory to publish the release to, alongside previously published releases")
+	fs.IntVar(&c.keepReleases, "keep-releases", defaultKeepReleases, "number of most recent releases to retain within --releases-dir (0 retains every release unless --keep-within is set)")
+	fs.DurationVar(&c.keepWithin, "keep-within", defaultKeepWithin, "retain releases within --releases-dir that were published within the given duration, e.g. 720h")
+	fs.StringVar(&c.output, "output", defaultOutput, "write the release to dir:<path>, tar:<path>, zip:<path> or s3://<bucket>[/<prefix>] instead of the temp directory")
+	fs.StringVar(&c.s3Endpoint, "s3-endpoint", defaultS3Endpoint, "url of an S3 compatible object store to use for s3:// outputs (defaults to AWS_ENDPOINT_URL_S3 or AWS S3)")
+	fs.StringVar(&c.s3Region, "s3-region", defaultS3Region, "region of the bucket of s3:// outputs (defaults to AWS_REGION or us-east-1)")
+	fs.BoolVar(&c.keepGoing, "keep-going", defaultKeepGoing, "exclude integration versions that fail to build from the release instead of failing; exits with status 2 if any were excluded")
+	fs.IntVar(&c.maxExcluded, "max-excluded", defaultMaxExcluded, "fail the release if integration versions of more than the given number of integrations are excluded by --keep-going (0 means no limit)")
 }
 
 func (c *Config) execGenerate(ctx context.Context, _ []string) error {
+	if c.output != "" && c.releasesDir != "" {
+		return errors.New("--output and --releases-dir are mutually exclusive")
+	}
+	if c.output != "" && c.watch {
+		return errors.New("--output is not supported in watch mode")
+	}
 	if c.watch {
 		return c.execGenerateWatcher(ctx)
 	}
@@ -47,7 +78,7 @@ func (c *Config) execGenerate(ctx context.Context, _ []string) error {
 func (c *Config) execGenerateWatcher(ctx context.Context) error {
 	// produce initial build
 	outdir, err := c.generate(ctx)
-	if err != nil {
+	if _, err := excludedVersions(err); err != nil {
 		return err
 	}
 	log.Info().Msgf("outdir: %s", outdir)
@@ -75,7 +106,7 @@ func (c *Config) execGenerateWatcher(ctx context.Context) error {
 
 			log.Debug().Msg("update detected, rebuilding...")
 			outdir, err = c.generate(ctx)
-			if err != nil {
+			if _, err := excludedVersions(err); err != nil {
 				log.Error().Err(err)
 			}
 
@@ -93,14 +124,118 @@ func (c *Config) execGenerateWatcher(ctx context.Context) error {
 
 func (c *Config) execRunGenerate(ctx context.Context) error {
 	outDir, err := c.generate(ctx)
+	excluded, err := excludedVersions(err)
 	if err != nil {
 		return err
 	}
+	if err := c.deliver(outDir, len(excluded)); err != nil {
+		return err
+	}
+
+	// the release is delivered regardless, but the failures must not go
+	// unnoticed
+	if len(excluded) > 0 {
+		return cmderrors.ErrExitStatus{
+			Status: exitStatusPartialRelease,
+			Err:    fmt.Errorf("%d integration version(s) were excluded from the release", len(excluded)),
+		}
+	}
+	return nil
+}
+
+// deliver publishes or writes out the release generated within outDir, as
+// configured, & sets the outputs of the release for github actions.
+func (c *Config) deliver(outDir string, excluded int) error {
 	releaseDir := path.Join(outDir, "release")
 
-	// print outputs for github actions
-	// TODO(jk): enable this with a command flag,
-	// e.g. --with-github-action-outputs
-	fmt.Printf("::set-output name=release-dir::%s\n", releaseDir)
+	if c.releasesDir != "" {
+		if err := c.publish(releaseDir); err != nil {
+			return err
+		}
+		if err := os.RemoveAll(outDir); err != nil {
+			log.Warn().Err(err).Str("path", outDir).Msg("Failed to remove temp directory")
+		}
+		releaseDir = c.releasesDir
+	}
+
+	if c.output != "" {
+		// the release was generated in memory & written to the output
+		if err := os.RemoveAll(outDir); err != nil {
+			log.Warn().Err(err).Str("path", outDir).Msg("Failed to remove temp directory")
+		}
+		log.Info().Str("output", c.output).Msg("Release written to output")
+
+		// the release dir output is only meaningful for directory outputs
+		if !strings.HasPrefix(c.output, "dir:") {
+			return nil
+		}
+		releaseDir = strings.TrimPrefix(c.output, "dir:")
+	}
+
+	log.Info().Str("release_dir", releaseDir).Msg("Release generated")
+
+	// set outputs for github actions
+	outputs := map[string]string{
+		"release-dir": releaseDir,
+	}
+	if c.keepGoing {
+		outputs["excluded"] = strconv.Itoa(excluded)
+	}
+	return writeGitHubOutputs(outputs)
+}
+
+// publish publishes the release generated within releaseDir to
+// c.releasesDir, recording it in the release history & removing releases
+// that are no longer retained.
+func (c *Config) publish(releaseDir string) error {
+	publisher, err := catalogpublish.New(catalogpublish.Config{
+		ReleasesDir: c.releasesDir,
+		KeepLast:    c.keepReleases,
+		KeepWithin:  c.keepWithin,
+	})
+	if err != nil {
+		return err
+	}
+
+	info, err := publisher.Publish(releaseDir, c.sourceCommit())
+	if err != nil {
+		return fmt.Errorf("error publishing release: %w", err)
+	}
+	log.Info().
+		Str("release_sha256", info.ReleaseSHA256).
+		Str("releases_dir", c.releasesDir).
+		Msg("Release published")
 	return nil
 }
+
+// newOutput creates the output specified by c.output, dating archive entries
+// by the timestamp of the release so that archives of reproducible releases
+// are reproducible too.
+func (c *Config) newOutput(timestamp time.Time) (output.Output, error) {
+	opts := output.Options{
+		ModTime: time.Unix(timestamp.Unix(), 0),
+		S3:      output.S3ConfigFromEnv(),
+	}
+	if c.s3Endpoint != "" {
+		opts.S3.Endpoint = c.s3Endpoint
+	}
+	if c.s3Region != "" {
+		opts.S3.Region = c.s3Region
+	}
+	return output.Parse(c.output, opts)
+}
+
+// sourceCommit returns the hash of the commit checked out within the catalog
+// repository, or an empty string if it cannot be determined.
+func (c *Config) sourceCommit() string {
+	repo, err := git.PlainOpen(c.repoDir)
+	if err != nil {
+		return ""
+	}
+	head, err := repo.Head()
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to determine source commit")
+		return ""
+	}
+	return head.Hash().String()
+}
@@ -0,0 +1,54 @@
+package catalogcmd
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// writeGitHubOutputs sets the outputs of the current GitHub Actions step by
+// appending them to the file that $GITHUB_OUTPUT points to. Nothing is
+// written when not running within GitHub Actions.
+func writeGitHubOutputs(outputs map[string]string) error {
+	path := os.Getenv("GITHUB_OUTPUT")
+	if path == "" {
+		return nil
+	}
+
+	names := make([]string, 0, len(outputs))
+	for name := range outputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	for _, name := range names {
+		value := outputs[name]
+		if !strings.Contains(value, "\n") {
+			fmt.Fprintf(&sb, "%s=%s\n", name, value)
+			continue
+		}
+
+		// multiline values are enclosed by a delimiter that must not occur
+		// within the value
+		b := make([]byte, 16)
+		if _, err := rand.Read(b); err != nil {
+			return fmt.Errorf("error generating github output delimiter: %w", err)
+		}
+		delimiter := "ghadelimiter_" + hex.EncodeToString(b)
+		fmt.Fprintf(&sb, "%s<<%s\n%s\n%s\n", name, delimiter, value, delimiter)
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("error opening github output file: %w", err)
+	}
+	if _, err := f.WriteString(sb.String()); err != nil {
+		f.Close()
+		return fmt.Errorf("error writing github outputs: %w", err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,79 @@
+package catalogcmd
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/peterbourgon/ff/v3/ffcli"
+	"github.com/rs/zerolog/log"
+	"github.com/sensu/catalog-api/internal/catalogsign"
+	"github.com/sensu/catalog-api/internal/commands/rootcmd"
+)
+
+func (c *Config) KeygenCommand() *ffcli.Command {
+	fs := flag.NewFlagSet("catalog-api catalog keygen", flag.ExitOnError)
+
+	// register global flags
+	c.rootConfig.RegisterFlags(fs)
+
+	return &ffcli.Command{
+		Name:       "keygen",
+		ShortUsage: "catalog-api catalog keygen [flags] <name>",
+		ShortHelp:  "Generate an ed25519 key pair for signing releases",
+		LongHelp: "Generate an ed25519 key pair for signing releases. The private key is\n" +
+			"written to <name>.key & the public key to <name>.pub. Existing files are\n" +
+			"never overwritten.",
+		FlagSet: fs,
+		Options: rootcmd.ParseOptions(),
+		Exec:    c.rootConfig.PreExec(c.execKeygen),
+	}
+}
+
+func (c *Config) execKeygen(_ context.Context, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("keygen requires exactly 1 argument, got: %d", len(args))
+	}
+	privatePath, publicPath := args[0]+".key", args[0]+".pub"
+
+	publicKey, privateKey, err := catalogsign.GenerateKey()
+	if err != nil {
+		return fmt.Errorf("error generating key pair: %w", err)
+	}
+	privatePEM, err := catalogsign.MarshalPrivateKey(privateKey)
+	if err != nil {
+		return err
+	}
+	publicPEM, err := catalogsign.MarshalPublicKey(publicKey)
+	if err != nil {
+		return err
+	}
+
+	if err := writeNewFile(privatePath, privatePEM, 0600); err != nil {
+		return err
+	}
+	if err := writeNewFile(publicPath, publicPEM, 0644); err != nil {
+		return err
+	}
+
+	log.Info().
+		Str("private_key", privatePath).
+		Str("public_key", publicPath).
+		Str("public_key_id", catalogsign.KeyID(publicKey)).
+		Msg("Key pair generated")
+	return nil
+}
+
+// writeNewFile writes data to path, failing if the file already exists.
+func writeNewFile(path string, data []byte, perm os.FileMode) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return fmt.Errorf("error creating %s: %w", path, err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+	return f.Close()
+}
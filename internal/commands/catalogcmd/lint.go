@@ -0,0 +1,95 @@
+package catalogcmd
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/peterbourgon/ff/v3/ffcli"
+	"github.com/sensu/catalog-api/internal/commands/rootcmd"
+	"github.com/sensu/catalog-api/internal/diagnostics"
+	"github.com/sensu/catalog-api/internal/lint"
+)
+
+var errLintFailed = errors.New("one or more integrations failed linting")
+
+func (c *Config) LintCommand() *ffcli.Command {
+	fs := flag.NewFlagSet("catalog-api catalog lint", flag.ExitOnError)
+
+	// register catalog lint flags
+	c.RegisterLintFlags(fs)
+
+	// register catalog & global flags
+	c.RegisterFlags(fs)
+
+	return &ffcli.Command{
+		Name:       "lint",
+		ShortUsage: "catalog-api catalog lint [flags]",
+		ShortHelp:  "Check integrations against the conventions of the catalog",
+		FlagSet:    fs,
+		// --output differs from the --output of generate, so it is set by
+		// its own environment variable
+		Exec: rootcmd.WithScopedEnvVars(fs, "lint", []string{"output"},
+			c.rootConfig.PreExec(c.withRepoConfig(fs, c.execLint))),
+	}
+}
+
+func (c *Config) RegisterLintFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.validateFormat, "format", defaultValidateFormat, fmt.Sprintf("report format, one of %s", strings.Join(diagnostics.Formats, ", ")))
+	fs.StringVar(&c.validateOutput, "output", defaultValidateOutput, "path to write the report to instead of stdout")
+	fs.StringVar(&c.validateSource, "source", defaultValidateSource, fmt.Sprintf("integration versions to lint, one of %s", strings.Join(validateSources, ", ")))
+	fs.StringVar(&c.validateSince, "since", defaultValidateSince, "only lint the integration versions tagged since the given git revision; requires --source tags or snapshot")
+	fs.BoolVar(&c.listLintRules, "list-rules", defaultListLintRules, "list the lint rules & their default severities, then exit")
+}
+
+func (c *Config) execLint(context.Context, []string) error {
+	if c.listLintRules {
+		for _, rule := range lint.Rules() {
+			fmt.Printf("%-26s %-8s %s\n", rule.ID, rule.Severity, rule.Description)
+		}
+		return nil
+	}
+	if !diagnostics.ValidFormat(c.validateFormat) {
+		return fmt.Errorf("format must be one of %s, got: %s", strings.Join(diagnostics.Formats, ", "), c.validateFormat)
+	}
+
+	linter, err := c.newLinter()
+	if err != nil {
+		return err
+	}
+
+	loader, err := c.newValidateLoader()
+	if err != nil {
+		return err
+	}
+	cm, err := c.newCatalogManager(loader, nil)
+	if err != nil {
+		return err
+	}
+
+	integrations, err := loader.LoadIntegrations()
+	if err != nil {
+		return fmt.Errorf("error loading integrations from catalog: %w", err)
+	}
+	diags, err := cm.LintIntegrations(linter, integrations)
+	if err != nil {
+		return fmt.Errorf("error linting catalog: %w", err)
+	}
+
+	report := c.withResults(diagnostics.NewReport(diags), integrations)
+	if err := c.writeReport(report); err != nil {
+		return err
+	}
+	if report.HasErrors() {
+		return errLintFailed
+	}
+	return nil
+}
+
+// newLinter returns a linter configured by the lint section of the catalog
+// config.
+func (c *Config) newLinter() (lint.Linter, error) {
+	return lint.New(c.repoConfig.Lint)
+}
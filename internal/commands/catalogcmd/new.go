@@ -0,0 +1,171 @@
+package catalogcmd
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+	"github.com/peterbourgon/ff/v3/ffcli"
+	"github.com/rs/zerolog/log"
+	"github.com/sensu/catalog-api/internal/catalogscaffold"
+	"github.com/sensu/catalog-api/internal/commands/rootcmd"
+)
+
+func (c *Config) NewCommand() *ffcli.Command {
+	fs := flag.NewFlagSet("catalog-api catalog new", flag.ExitOnError)
+
+	// register catalog new flags
+	c.RegisterNewFlags(fs)
+
+	// register catalog & global flags
+	c.RegisterFlags(fs)
+
+	return &ffcli.Command{
+		Name:       "new",
+		ShortUsage: "catalog-api catalog new [flags] <namespace>/<name>",
+		ShortHelp:  "Scaffold a new integration that passes validation",
+		LongHelp: "Scaffold a new integration within the integrations directory of the\n" +
+			"catalog repository. The integration config, an example resource, README,\n" +
+			"CHANGELOG, placeholder logo & the img/ & dashboards/ directories are\n" +
+			"created, all of which pass catalog validate as is.\n\n" +
+			"When run from a terminal, the options that were not set by flags are\n" +
+			"prompted for; otherwise they are set to their defaults.",
+		FlagSet: fs,
+		Options: rootcmd.ParseOptions(),
+		Exec:    c.rootConfig.PreExec(c.withRepoConfig(fs, c.execNew)),
+	}
+}
+
+func (c *Config) RegisterNewFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.newDisplayName, "display-name", defaultNewDisplayName, "display name of the integration (defaults to the title cased name)")
+	fs.StringVar(&c.newShortDescription, "short-description", defaultNewShortDescription, "short description of the integration")
+	fs.StringVar(&c.newProvider, "provider", defaultNewProvider, "provider of the integration")
+	fs.StringVar(&c.newClass, "class", defaultNewClass, "class of the integration")
+	fs.StringVar(&c.newPlatforms, "platforms", defaultNewPlatforms, "comma separated list of the platforms supported by the integration")
+	fs.StringVar(&c.newContributors, "contributors", defaultNewContributors, "comma separated list of the GitHub handles of the contributors, e.g. @octocat")
+	fs.BoolVar(&c.newPrompt, "prompt", defaultNewPrompt, "prompt for the options that were not set when run from a terminal")
+}
+
+func (c *Config) execNew(_ context.Context, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("new requires exactly 1 argument, got: %d", len(args))
+	}
+	parts := strings.SplitN(args[0], "/", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("integration must be in the form <namespace>/<name>, got: %s", args[0])
+	}
+
+	config := catalogscaffold.Config{
+		Namespace:        parts[0],
+		Name:             parts[1],
+		DisplayName:      c.newDisplayName,
+		ShortDescription: c.newShortDescription,
+		Provider:         c.newProvider,
+		Class:            c.newClass,
+		Platforms:        splitList(c.newPlatforms),
+		Contributors:     splitList(c.newContributors),
+		ReadmeSections:   c.repoConfig.Lint.ReadmeSections,
+		Taxonomy:         c.repoConfig.Taxonomy(),
+		Lint:             c.repoConfig.Lint,
+	}
+	if c.newPrompt && isatty.IsTerminal(os.Stdin.Fd()) {
+		config = promptNew(newPrompter(os.Stdin, os.Stdout), config)
+	}
+
+	dir := filepath.Join(c.repoDir, c.integrationsDirName, config.Namespace, config.Name)
+	files, err := catalogscaffold.Scaffold(dir, config)
+	if err != nil {
+		return err
+	}
+
+	log.Info().
+		Str("integration", args[0]).
+		Str("dir", dir).
+		Strs("files", files).
+		Msg("Integration created")
+	return nil
+}
+
+// promptNew prompts for the options of config that are unset, suggesting
+// their defaults.
+func promptNew(p *prompter, config catalogscaffold.Config) catalogscaffold.Config {
+	defaults := config.WithDefaults()
+	if config.DisplayName == "" {
+		config.DisplayName = p.ask("Display name", defaults.DisplayName, nil)
+		defaults = config.WithDefaults()
+	}
+	if config.ShortDescription == "" {
+		config.ShortDescription = p.ask("Short description", defaults.ShortDescription, nil)
+	}
+	if config.Provider == "" {
+		config.Provider = p.ask("Provider", defaults.Provider, config.Taxonomy.ValidProviders())
+	}
+	if config.Class == "" {
+		config.Class = p.ask("Class", defaults.Class, config.Taxonomy.ValidClasses())
+	}
+	if len(config.Platforms) == 0 {
+		config.Platforms = splitList(p.ask("Platforms", strings.Join(defaults.Platforms, ","), nil))
+	}
+	for len(config.Contributors) == 0 {
+		config.Contributors = splitList(p.ask("Contributors (e.g. @octocat)", "", nil))
+		if p.done {
+			break
+		}
+	}
+	return config
+}
+
+type prompter struct {
+	in   *bufio.Reader
+	out  io.Writer
+	done bool
+}
+
+func newPrompter(in io.Reader, out io.Writer) *prompter {
+	return &prompter{in: bufio.NewReader(in), out: out}
+}
+
+// ask prompts for a value until one of choices, if any, is given. The default
+// value is returned when the answer is empty or once the input is exhausted.
+func (p *prompter) ask(question string, defaultValue string, choices []string) string {
+	for !p.done {
+		fmt.Fprint(p.out, question)
+		if len(choices) > 0 {
+			fmt.Fprintf(p.out, " (%s)", strings.Join(choices, ", "))
+		}
+		if defaultValue != "" {
+			fmt.Fprintf(p.out, " [%s]", defaultValue)
+		}
+		fmt.Fprint(p.out, ": ")
+
+		line, err := p.in.ReadString('\n')
+		if err != nil {
+			p.done = true
+			fmt.Fprintln(p.out)
+		}
+		answer := strings.TrimSpace(line)
+		if answer == "" {
+			return defaultValue
+		}
+		if len(choices) == 0 || contains(choices, answer) {
+			return answer
+		}
+		fmt.Fprintf(p.out, "%s must be one of %s\n", question, strings.Join(choices, ", "))
+	}
+	return defaultValue
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/peterbourgon/ff/v3/ffcli"
 	"github.com/sensu/catalog-api/internal/catalogpreview"
+	"github.com/sensu/catalog-api/internal/commands/rootcmd"
 )
 
 const (
@@ -28,7 +29,8 @@ func (c *Config) PreviewCommand() *ffcli.Command {
 		ShortUsage: "catalog-api catalog preview [flags]",
 		ShortHelp:  "Serves static catalog API & preview catalog web application for development purposes",
 		FlagSet:    fs,
-		Exec:       c.rootConfig.PreExec(c.execPreview),
+		Options:    rootcmd.ParseOptions(),
+		Exec:       c.rootConfig.PreExec(c.withRepoConfig(fs, c.execPreview)),
 	}
 }
 
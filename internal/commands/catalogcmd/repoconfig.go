@@ -0,0 +1,46 @@
+package catalogcmd
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/sensu/catalog-api/internal/commands/rootcmd"
+	"github.com/sensu/catalog-api/internal/repoconfig"
+)
+
+// withRepoConfig loads the config of the catalog repository before executing
+// fn. Options set by the config become the values of the flags in fs that
+// were set neither on the command line nor by environment variables.
+func (c *Config) withRepoConfig(fs *flag.FlagSet, fn rootcmd.ExecFn) rootcmd.ExecFn {
+	return func(ctx context.Context, args []string) error {
+		path := c.configPath
+		if path == "" {
+			path = filepath.Join(c.repoDir, repoconfig.FileName)
+		} else if _, err := os.Stat(path); err != nil {
+			// unlike the default catalog config, an explicit one must exist
+			return fmt.Errorf("error reading catalog config: %w", err)
+		}
+		config, err := repoconfig.Load(path)
+		if err != nil {
+			return err
+		}
+		c.repoConfig = config
+
+		set := map[string]bool{}
+		fs.Visit(func(f *flag.Flag) {
+			set[f.Name] = true
+		})
+		for name, value := range config.FlagValues() {
+			if set[name] || fs.Lookup(name) == nil {
+				continue
+			}
+			if err := fs.Set(name, value); err != nil {
+				return fmt.Errorf("error applying catalog config to --%s: %w", name, err)
+			}
+		}
+		return fn(ctx, args)
+	}
+}
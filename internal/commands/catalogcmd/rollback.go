@@ -0,0 +1,64 @@
+package catalogcmd
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/peterbourgon/ff/v3/ffcli"
+	"github.com/rs/zerolog/log"
+	"github.com/sensu/catalog-api/internal/catalogpublish"
+	"github.com/sensu/catalog-api/internal/commands/rootcmd"
+)
+
+func (c *Config) RollbackCommand() *ffcli.Command {
+	fs := flag.NewFlagSet("catalog-api catalog rollback", flag.ExitOnError)
+
+	// register catalog rollback flags
+	c.RegisterRollbackFlags(fs)
+
+	// register global flags
+	c.rootConfig.RegisterFlags(fs)
+
+	return &ffcli.Command{
+		Name:       "rollback",
+		ShortUsage: "catalog-api catalog rollback [flags] <release_sha256>",
+		ShortHelp:  "Point version.json at a previously published release",
+		LongHelp: "Point version.json at a previously published release. The release must\n" +
+			"still be retained within --releases-dir; see releases.json for the list of\n" +
+			"retained releases.",
+		FlagSet: fs,
+		Options: rootcmd.ParseOptions(),
+		Exec:    c.rootConfig.PreExec(c.execRollback),
+	}
+}
+
+func (c *Config) RegisterRollbackFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.releasesDir, "releases-dir", defaultReleasesDir, "path to the directory releases have been published to")
+}
+
+func (c *Config) execRollback(_ context.Context, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("rollback requires exactly 1 argument, got: %d", len(args))
+	}
+	if c.releasesDir == "" {
+		return errors.New("--releases-dir must be set")
+	}
+
+	publisher, err := catalogpublish.New(catalogpublish.Config{
+		ReleasesDir: c.releasesDir,
+	})
+	if err != nil {
+		return err
+	}
+	if err := publisher.Rollback(args[0]); err != nil {
+		return fmt.Errorf("error rolling back release: %w", err)
+	}
+
+	log.Info().
+		Str("release_sha256", args[0]).
+		Str("releases_dir", c.releasesDir).
+		Msg("Release rolled back")
+	return nil
+}
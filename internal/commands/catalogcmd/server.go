@@ -2,12 +2,15 @@ package catalogcmd
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"path/filepath"
+	"strings"
 
 	"github.com/peterbourgon/ff/v3/ffcli"
 	"github.com/sensu/catalog-api/internal/catalogserver"
+	"github.com/sensu/catalog-api/internal/commands/rootcmd"
 )
 
 const (
@@ -26,9 +29,10 @@ func (c *Config) ServerCommand() *ffcli.Command {
 	return &ffcli.Command{
 		Name:       "server",
 		ShortUsage: "catalog-api catalog server [flags]",
-		ShortHelp:  "Serves static catalog API for development purposes",
+		ShortHelp:  "Serves static catalog API",
 		FlagSet:    fs,
-		Exec:       c.rootConfig.PreExec(c.execServer),
+		Options:    rootcmd.ParseOptions(),
+		Exec:       c.rootConfig.PreExec(c.withRepoConfig(fs, c.execServer)),
 	}
 }
 
@@ -37,6 +41,16 @@ func (c *Config) RegisterServerFlags(fs *flag.FlagSet) {
 	fs.StringVar(&c.tempDir, "temp-dir", defaultTempDir, "path to a temporary directory for generated files")
 	fs.BoolVar(&c.snapshot, "without-snapshot", defaultSnapshot, "generate a catalog api using tags only")
 	fs.BoolVar(&c.watch, "watch", defaultWatchMode, "enter watch mode, which rebuilds on file change")
+	fs.BoolVar(&c.compress, "compress", defaultCompress, "also write gzip & brotli compressed copies of json, markdown & svg endpoints")
+	fs.BoolVar(&c.keepGoing, "keep-going", defaultKeepGoing, "exclude integration versions that fail to build from the release instead of failing")
+	fs.IntVar(&c.maxExcluded, "max-excluded", defaultMaxExcluded, "fail the release if integration versions of more than the given number of integrations are excluded by --keep-going (0 means no limit)")
+	fs.BoolVar(&c.queryAPI, "query-api", defaultQueryAPI, "load the current release into memory & serve the /query & /resolve endpoints")
+	fs.BoolVar(&c.graphql, "graphql", defaultGraphQL, "load the current release into memory & serve the /catalog-graphql endpoint")
+	fs.StringVar(&c.tlsCert, "tls-cert", defaultTLSCert, "path to a PEM encoded certificate to serve HTTPS with; requires --tls-key")
+	fs.StringVar(&c.tlsKey, "tls-key", defaultTLSKey, "path to the PEM encoded private key of --tls-cert")
+	fs.StringVar(&c.authToken, "auth-token", defaultAuthToken, "require requests to carry the given bearer token")
+	fs.StringVar(&c.basicAuth, "basic-auth", defaultBasicAuth, "require requests to carry the given basic auth credentials, formatted as <username>:<password>")
+	fs.StringVar(&c.corsOrigins, "cors-origins", defaultCORSOrigins, "comma separated list of origins allowed to make cross-origin requests; * allows every origin")
 }
 
 func (c *Config) execServer(ctx context.Context, _ []string) error {
@@ -50,8 +64,39 @@ func (c *Config) startServer(ctx context.Context) (err error) {
 	// configure
 	listenAddr := fmt.Sprintf(":%d", c.port)
 	symlink := filepath.Join(c.tempDir, "current")
-	server := catalogserver.NewCatalogServer(listenAddr, symlink)
+	config := catalogserver.Config{
+		ListenAddr:     listenAddr,
+		Symlink:        symlink,
+		EnableQueryAPI: c.queryAPI,
+		EnableGraphQL:  c.graphql,
+		TLSCertFile:    c.tlsCert,
+		TLSKeyFile:     c.tlsKey,
+		BearerToken:    c.authToken,
+		AllowedOrigins: splitList(c.corsOrigins),
+	}
+	if c.basicAuth != "" {
+		credentials := strings.SplitN(c.basicAuth, ":", 2)
+		if len(credentials) != 2 || credentials[0] == "" || credentials[1] == "" {
+			return errors.New("--basic-auth must be formatted as <username>:<password>")
+		}
+		config.BasicAuthUsername, config.BasicAuthPassword = credentials[0], credentials[1]
+	}
+	server, err := catalogserver.NewCatalogServer(config)
+	if err != nil {
+		return fmt.Errorf("error creating server: %w", err)
+	}
 
 	// start server
 	return c.startServerWithWatcher(ctx, symlink, &server)
 }
+
+// splitList splits a comma separated list, ignoring empty elements.
+func splitList(list string) []string {
+	elements := []string{}
+	for _, element := range strings.Split(list, ",") {
+		if element = strings.TrimSpace(element); element != "" {
+			elements = append(elements, element)
+		}
+	}
+	return elements
+}
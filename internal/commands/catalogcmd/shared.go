@@ -2,11 +2,14 @@ package catalogcmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
 	"path"
 	"path/filepath"
+	"strconv"
+	"sync"
 	"syscall"
 	"time"
 
@@ -15,18 +18,25 @@ import (
 	"github.com/rs/zerolog/log"
 	"github.com/sensu/catalog-api/internal/catalogloader"
 	"github.com/sensu/catalog-api/internal/catalogmanager"
+	"github.com/sensu/catalog-api/internal/catalogserver"
+	"github.com/sensu/catalog-api/internal/catalogsign"
+	"github.com/sensu/catalog-api/internal/output"
 )
 
 var (
-	throttleDelay = 1250 * time.Millisecond
+	throttleDelay   = 1250 * time.Millisecond
+	shutdownTimeout = 30 * time.Second
 )
 
 type tmpCatalogManager struct {
 	catalogmanager.CatalogManager
 	tmpdir string
+
+	// out is the output the release is written to, if --output is set
+	out output.Output
 }
 
-func (c *Config) newCatalogManager(loader catalogloader.Loader) (cm tmpCatalogManager, err error) {
+func (c *Config) newCatalogManager(loader catalogloader.Loader, observer catalogmanager.Observer) (cm tmpCatalogManager, err error) {
 	// create a temporay directory within c.tempDir to hold the generated api
 	// files
 	cm.tmpdir, err = os.MkdirTemp(c.tempDir, "")
@@ -52,8 +62,36 @@ func (c *Config) newCatalogManager(loader catalogloader.Loader) (cm tmpCatalogMa
 	}
 
 	mCfg := catalogmanager.Config{
-		StagingDir: stagingDir,
-		ReleaseDir: releaseDir,
+		StagingDir:          stagingDir,
+		ReleaseDir:          releaseDir,
+		IntegrationsDirName: c.integrationsDirName,
+		Compress:            c.compress,
+		Observer:            observer,
+		KeepGoing:           c.keepGoing,
+		MaxExcluded:         c.maxExcluded,
+		Taxonomy:            c.repoConfig.Taxonomy(),
+	}
+	if c.signingKey != "" {
+		mCfg.SigningKey, err = catalogsign.LoadPrivateKey(c.signingKey)
+		if err != nil {
+			return cm, err
+		}
+	}
+	mCfg.Timestamp, err = c.sourceDate(loader)
+	if err != nil {
+		return cm, err
+	}
+	if c.output != "" {
+		// archive entries are dated by the release, so the timestamp of the
+		// release must be known before the output is created
+		if mCfg.Timestamp.IsZero() {
+			mCfg.Timestamp = time.Now()
+		}
+		cm.out, err = c.newOutput(mCfg.Timestamp)
+		if err != nil {
+			return cm, err
+		}
+		mCfg.Output = cm.out
 	}
 
 	// create a new catalog manager which is used to determine versions from git
@@ -62,7 +100,38 @@ func (c *Config) newCatalogManager(loader catalogloader.Loader) (cm tmpCatalogMa
 	return cm, err
 }
 
-func (c *Config) newCatalogManagerFromRepo(ctx context.Context) (cm tmpCatalogManager, err error) {
+// sourceDate determines the timestamp of the release. SOURCE_DATE_EPOCH is
+// used if set; otherwise, in reproducible mode, the timestamp is derived from
+// the dates of the git tags & commits the catalog is loaded from. The zero
+// time, i.e. the current time, is returned if neither applies.
+func (c *Config) sourceDate(loader catalogloader.Loader) (time.Time, error) {
+	if epoch := os.Getenv("SOURCE_DATE_EPOCH"); epoch != "" {
+		seconds, err := strconv.ParseInt(epoch, 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid SOURCE_DATE_EPOCH: %w", err)
+		}
+		return time.Unix(seconds, 0), nil
+	}
+	if !c.reproducible {
+		return time.Time{}, nil
+	}
+
+	dater, ok := loader.(catalogloader.SourceDater)
+	if !ok {
+		return time.Time{}, errors.New("reproducible releases are not supported by the catalog loader")
+	}
+	date, err := dater.SourceDate()
+	if err != nil {
+		return date, fmt.Errorf("error determining source date: %w", err)
+	}
+	if date.IsZero() {
+		// the catalog has no releases yet
+		return time.Unix(0, 0), nil
+	}
+	return date, nil
+}
+
+func (c *Config) newCatalogManagerFromRepo(ctx context.Context, observer catalogmanager.Observer) (cm tmpCatalogManager, err error) {
 	repo, err := git.PlainOpen(c.repoDir)
 	if err != nil {
 		return cm, err
@@ -74,26 +143,54 @@ func (c *Config) newCatalogManagerFromRepo(ctx context.Context) (cm tmpCatalogMa
 	} else {
 		loader = catalogloader.NewGitLoader(repo, c.integrationsDirName)
 	}
-	return c.newCatalogManager(loader)
+	return c.newCatalogManager(loader, observer)
 }
 
 func (c *Config) generate(ctx context.Context) (string, error) {
-	cm, err := c.newCatalogManagerFromRepo(ctx)
-	if err != nil {
+	cm, err := c.newCatalogManagerFromRepo(ctx, nil)
+	if err == nil {
+		// process the catalog & all its integrations
+		err = cm.ProcessCatalog()
+	}
+	if cm.out == nil {
 		return cm.tmpdir, err
 	}
 
-	// process the catalog & all its integrations
-	err = cm.ProcessCatalog()
+	// releases that exclude failing integration versions are still written
+	var partial catalogmanager.PartialReleaseError
+	if err != nil && !errors.As(err, &partial) {
+		if err := cm.out.Abort(); err != nil {
+			log.Warn().Err(err).Msg("Failed to abort output")
+		}
+		return cm.tmpdir, err
+	}
+	if err := cm.out.Close(); err != nil {
+		return cm.tmpdir, fmt.Errorf("error writing release to output: %w", err)
+	}
 	return cm.tmpdir, err
 }
 
+// excludedVersions returns the integration versions that were excluded from a
+// release generated in keep-going mode, logging each of them. The error of
+// ProcessCatalog is returned unless it only reports excluded versions.
+func excludedVersions(err error) (catalogmanager.ValidationErrors, error) {
+	var partial catalogmanager.PartialReleaseError
+	if !errors.As(err, &partial) {
+		return nil, err
+	}
+	log.Warn().Err(err).Msg("Release generated without failing integration versions")
+	for _, failure := range partial.Excluded {
+		log.Warn().Msg(failure.Diagnostic().String())
+	}
+	return partial.Excluded, nil
+}
+
 func (c *Config) createWatcher(ctx context.Context) (*fsnotify.Watcher, error) {
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
 		return watcher, fmt.Errorf("error configuring watcher: %w", err)
 	}
-	dir := filepath.Join(c.repoDir, "integrations")
+	dir := filepath.Join(c.repoDir, c.integrationsDirName)
 	walker := func(path string, de os.DirEntry, err error) error {
 		if de.Type().IsDir() {
 			return watcher.Add(path)
@@ -137,7 +234,7 @@ func dedupeWatchEvents(ctx context.Context, watcher *fsnotify.Watcher) chan erro
 }
 
 type Server interface {
-	Start(context.Context)
+	Start(context.Context) error
 	Stop(context.Context) error
 }
 
@@ -146,29 +243,52 @@ type WatchableServer interface {
 	HandleWatchEvent()
 }
 
+// BuildObserver is implemented by servers that record the catalog builds
+// that they serve.
+type BuildObserver interface {
+	catalogmanager.Observer
+	ObserveBuild(build catalogserver.Build)
+}
+
+var _ BuildObserver = &catalogserver.Server{}
+
 func (c *Config) startServerWithWatcher(ctx context.Context, symlink string, server WatchableServer) (err error) {
+	observer, _ := server.(BuildObserver)
+
 	// prepare
-	cleanup, err := c.prepare(ctx, symlink)
+	cleanup, err := c.prepare(ctx, symlink, observer)
 	if err != nil {
 		return err
 	}
+	var cleanupMu sync.Mutex
 	defer func() {
+		cleanupMu.Lock()
+		defer cleanupMu.Unlock()
 		cleanup() // inside closure to ensure we deref the correct func
+		_ = os.RemoveAll(symlink)
 	}()
 
 	// start server
-	go server.Start(ctx)
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.Start(ctx)
+	}()
 
 	// watch
 	if c.watch {
 		process := func() error {
 			log.Info().Msg("Filesystem change detected")
-			cleanup()
-			cleanup, err = c.prepare(ctx, symlink)
+			next, err := c.prepare(ctx, symlink, observer)
 			if err != nil {
+				// the previous release continues to be served
 				return err
 			}
 			server.HandleWatchEvent()
+
+			cleanupMu.Lock()
+			defer cleanupMu.Unlock()
+			cleanup()
+			cleanup = next
 			return nil
 		}
 		if err = c.watchRepo(ctx, process); err != nil {
@@ -180,8 +300,17 @@ func (c *Config) startServerWithWatcher(ctx context.Context, symlink string, ser
 	exit := make(chan os.Signal, 1)
 	signal.Notify(exit, os.Interrupt, syscall.SIGTERM)
 
-	<-exit
-	return server.Stop(ctx)
+	select {
+	case <-exit:
+	case err := <-serverErr:
+		return err
+	}
+
+	// drain active connections before exiting
+	log.Info().Msg("Shutting down")
+	shutdownCtx, cancel := context.WithTimeout(ctx, shutdownTimeout)
+	defer cancel()
+	return server.Stop(shutdownCtx)
 }
 
 func (c *Config) watchRepo(ctx context.Context, process func() error) (err error) {
@@ -211,9 +340,23 @@ func (c *Config) watchRepo(ctx context.Context, process func() error) (err error
 	return
 }
 
-func (c *Config) prepare(ctx context.Context, symlink string) (cleanup func(), err error) {
+// prepare generates the catalog api & points symlink at it, replacing the
+// api that it pointed at previously. The returned cleanup func removes the
+// generated api. The build is reported to observer, if not nil.
+func (c *Config) prepare(ctx context.Context, symlink string, observer BuildObserver) (cleanup func(), err error) {
+	build := catalogserver.Build{}
+	if observer != nil {
+		start := time.Now()
+		build.SourceCommit = c.sourceCommit()
+		defer func() {
+			build.Duration = time.Since(start)
+			build.Err = err
+			observer.ObserveBuild(build)
+		}()
+	}
+
 	// configure
-	cm, err := c.newCatalogManagerFromRepo(ctx)
+	cm, err := c.newCatalogManagerFromRepo(ctx, observer)
 	if err != nil {
 		return cleanup, err
 	}
@@ -224,14 +367,21 @@ func (c *Config) prepare(ctx context.Context, symlink string) (cleanup func(), e
 	}()
 
 	// generate
-	if err := cm.ProcessCatalog(); err != nil {
+	excluded, err := excludedVersions(cm.ProcessCatalog())
+	if err != nil {
 		return cleanup, err
 	}
 
 	// validate
 	if err := cm.ValidateCatalog(); err != nil {
-		log.Warn().Err(err)
+		log.Warn().Err(err).Msg("Catalog validation failed")
+		if errors.As(err, &build.ValidationErrors) {
+			for _, failure := range build.ValidationErrors {
+				log.Warn().Msg(failure.Diagnostic().String())
+			}
+		}
 	}
+	build.ValidationErrors = append(build.ValidationErrors, excluded...)
 
 	// symlink
 	if err := os.RemoveAll(symlink); err != nil {
@@ -243,6 +393,5 @@ func (c *Config) prepare(ctx context.Context, symlink string) (cleanup func(), e
 	log.Info().Str("path", cm.tmpdir).Msg("API generated")
 	return func() {
 		_ = os.RemoveAll(cm.tmpdir)
-		_ = os.RemoveAll(symlink)
 	}, err
 }
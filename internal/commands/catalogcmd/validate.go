@@ -2,16 +2,39 @@ package catalogcmd
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
 
+	"github.com/go-git/go-git/v5"
 	"github.com/peterbourgon/ff/v3/ffcli"
 	"github.com/sensu/catalog-api/internal/catalogloader"
+	"github.com/sensu/catalog-api/internal/catalogmanager"
+	"github.com/sensu/catalog-api/internal/commands/rootcmd"
+	"github.com/sensu/catalog-api/internal/diagnostics"
+	"github.com/sensu/catalog-api/internal/types"
+	"github.com/sensu/catalog-api/internal/util"
 )
 
+// Sources of the integration versions validated by the validate command.
+const (
+	validateSourceTags     = "tags"
+	validateSourceSnapshot = "snapshot"
+	validateSourcePath     = "path"
+)
+
+var validateSources = []string{validateSourceTags, validateSourceSnapshot, validateSourcePath}
+
 func (c *Config) ValidateCommand() *ffcli.Command {
 	fs := flag.NewFlagSet("catalog-api catalog validate", flag.ExitOnError)
 
+	// register catalog validate flags
+	c.RegisterValidateFlags(fs)
+
 	// register catalog & global flags
 	c.RegisterFlags(fs)
 
@@ -20,22 +43,153 @@ func (c *Config) ValidateCommand() *ffcli.Command {
 		ShortUsage: "catalog-api catalog validate [flags]",
 		ShortHelp:  "Validate a catalog directory and its integrations",
 		FlagSet:    fs,
-		Exec:       c.rootConfig.PreExec(c.execValidate),
+		// --output differs from the --output of generate, so it is set by
+		// its own environment variable
+		Exec: rootcmd.WithScopedEnvVars(fs, "validate", []string{"output"},
+			c.rootConfig.PreExec(c.withRepoConfig(fs, c.execValidate))),
 	}
 }
 
+func (c *Config) RegisterValidateFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.validateFormat, "format", defaultValidateFormat, fmt.Sprintf("report format, one of %s", strings.Join(diagnostics.Formats, ", ")))
+	fs.StringVar(&c.validateOutput, "output", defaultValidateOutput, "path to write the report to instead of stdout")
+	fs.StringVar(&c.validateSource, "source", defaultValidateSource, fmt.Sprintf("integration versions to validate, one of %s; tags validates every tagged version, snapshot the tagged versions & the catalog directory, path the catalog directory only", strings.Join(validateSources, ", ")))
+	fs.StringVar(&c.validateSince, "since", defaultValidateSince, "only validate the integration versions tagged since the given git revision; requires --source tags or snapshot")
+	fs.BoolVar(&c.lint, "lint", defaultLint, "also check integrations against the lint rules")
+}
+
 func (c *Config) execValidate(context.Context, []string) error {
-	loader := catalogloader.NewPathLoader(c.repoDir, c.integrationsDirName)
+	if !diagnostics.ValidFormat(c.validateFormat) {
+		return fmt.Errorf("format must be one of %s, got: %s", strings.Join(diagnostics.Formats, ", "), c.validateFormat)
+	}
+
+	loader, err := c.newValidateLoader()
+	if err != nil {
+		return err
+	}
 
-	cm, err := c.newCatalogManager(loader)
+	cm, err := c.newCatalogManager(loader, nil)
 	if err != nil {
 		return err
 	}
 
 	// validate the catalog & all its integrations
-	if err := cm.ValidateCatalog(); err != nil {
-		return fmt.Errorf("error validating catalog: %w", err)
+	integrations, validationErr := cm.ValidateIntegrations()
+	var failures catalogmanager.ValidationErrors
+	if validationErr != nil && !errors.As(validationErr, &failures) {
+		return fmt.Errorf("error validating catalog: %w", validationErr)
+	}
+
+	diags := failures.Diagnostics()
+	if c.lint {
+		linter, err := c.newLinter()
+		if err != nil {
+			return err
+		}
+		lintDiags, err := cm.LintIntegrations(linter, integrations)
+		if err != nil {
+			return fmt.Errorf("error linting catalog: %w", err)
+		}
+		diags = append(diags, lintDiags...)
+	}
+
+	report := c.withResults(diagnostics.NewReport(diags), integrations)
+	if err := c.writeReport(report); err != nil {
+		return err
+	}
+
+	if validationErr != nil {
+		return fmt.Errorf("error validating catalog: %w", validationErr)
+	}
+	if report.HasErrors() {
+		return errLintFailed
+	}
+	return nil
+}
+
+// withResults adds the outcome of each integration version to the report
+// when tagged versions were checked.
+func (c *Config) withResults(report diagnostics.Report, integrations types.Integrations) diagnostics.Report {
+	if c.validateSource == validateSourcePath {
+		return report
+	}
+	results := []diagnostics.Result{}
+	for _, integration := range integrations {
+		results = append(results, diagnostics.Result{
+			Namespace: integration.Namespace,
+			Name:      integration.Name,
+			Version:   integration.SemVer(),
+		})
+	}
+	return report.WithResults(results)
+}
+
+// writeReport writes the report & sets the outputs of the report for github
+// actions.
+func (c *Config) writeReport(report diagnostics.Report) error {
+	if err := c.writeValidateReport(report); err != nil {
+		return err
+	}
+
+	// set outputs for github actions
+	outputs := map[string]string{
+		"errors":   strconv.Itoa(report.Count(diagnostics.SeverityError)),
+		"warnings": strconv.Itoa(report.Count(diagnostics.SeverityWarning)),
+	}
+	if c.validateOutput != "" {
+		outputs["report"] = c.validateOutput
+	}
+	return writeGitHubOutputs(outputs)
+}
+
+// newValidateLoader returns the catalog loader of the integration versions
+// selected by c.validateSource & c.validateSince.
+func (c *Config) newValidateLoader() (catalogloader.Loader, error) {
+	switch c.validateSource {
+	case validateSourcePath:
+		if c.validateSince != "" {
+			return nil, fmt.Errorf("--since requires --source %s or %s", validateSourceTags, validateSourceSnapshot)
+		}
+		return catalogloader.NewPathLoader(c.repoDir, c.integrationsDirName), nil
+	case validateSourceTags, validateSourceSnapshot:
+	default:
+		return nil, fmt.Errorf("source must be one of %s, got: %s", strings.Join(validateSources, ", "), c.validateSource)
 	}
 
+	repo, err := git.PlainOpen(c.repoDir)
+	if err != nil {
+		return nil, fmt.Errorf("error opening catalog repository: %w", err)
+	}
+	if c.validateSource == validateSourceSnapshot {
+		loader := catalogloader.NewSnapshotLoader(repo, c.repoDir, c.integrationsDirName)
+		if c.validateSince != "" {
+			return loader.Since(c.validateSince)
+		}
+		return loader, nil
+	}
+	loader := catalogloader.NewGitLoader(repo, c.integrationsDirName)
+	if c.validateSince != "" {
+		return loader.Since(c.validateSince)
+	}
+	return loader, nil
+}
+
+// writeValidateReport writes the report in c.validateFormat to
+// c.validateOutput, or to stdout if no output is set.
+func (c *Config) writeValidateReport(report diagnostics.Report) error {
+	var w io.Writer = os.Stdout
+	var sb strings.Builder
+	if c.validateOutput != "" {
+		w = &sb
+	}
+	if err := report.Write(w, c.validateFormat); err != nil {
+		return fmt.Errorf("error writing report: %w", err)
+	}
+	if c.validateOutput == "" {
+		return nil
+	}
+	if err := util.WriteFileAtomic(c.validateOutput, []byte(sb.String()), 0644); err != nil {
+		return fmt.Errorf("error writing report: %w", err)
+	}
 	return nil
 }
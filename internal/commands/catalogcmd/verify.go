@@ -0,0 +1,80 @@
+package catalogcmd
+
+import (
+	"context"
+	"crypto/ed25519"
+	"flag"
+	"fmt"
+
+	"github.com/peterbourgon/ff/v3/ffcli"
+	"github.com/rs/zerolog/log"
+	"github.com/sensu/catalog-api/internal/catalogsign"
+	"github.com/sensu/catalog-api/internal/catalogverify"
+	"github.com/sensu/catalog-api/internal/commands/rootcmd"
+)
+
+func (c *Config) VerifyCommand() *ffcli.Command {
+	fs := flag.NewFlagSet("catalog-api catalog verify", flag.ExitOnError)
+
+	// register catalog verify flags
+	c.RegisterVerifyFlags(fs)
+
+	// register catalog & global flags
+	c.RegisterFlags(fs)
+
+	return &ffcli.Command{
+		Name:       "verify",
+		ShortUsage: "catalog-api catalog verify [flags] <release-dir>",
+		ShortHelp:  "Verify the checksums & digests of a generated release",
+		LongHelp: "Verify the checksums & digests of a generated release. The release directory\n" +
+			"may either contain version.json, in which case the release it points to is\n" +
+			"verified, or be the <release_sha256> directory of a single release.\n\n" +
+			"When --public-key is set, releases that are unsigned or that were not\n" +
+			"signed by the corresponding private key fail verification.",
+		FlagSet: fs,
+		Options: rootcmd.ParseOptions(),
+		Exec:    c.rootConfig.PreExec(c.withRepoConfig(fs, c.execVerify)),
+	}
+}
+
+func (c *Config) RegisterVerifyFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.publicKey, "public-key", defaultPublicKey, "path to an ed25519 public key used to verify release signatures")
+}
+
+func (c *Config) execVerify(_ context.Context, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("verify requires exactly 1 argument, got: %d", len(args))
+	}
+
+	var publicKey ed25519.PublicKey
+	if c.publicKey != "" {
+		var err error
+		publicKey, err = catalogsign.LoadPublicKey(c.publicKey)
+		if err != nil {
+			return err
+		}
+	}
+
+	result, err := catalogverify.Verify(args[0], publicKey)
+	if err != nil {
+		return fmt.Errorf("error verifying release: %w", err)
+	}
+
+	for _, failure := range result.Failures {
+		log.Error().Str("path", failure.Path).Msg(failure.Message)
+	}
+	if !result.OK() {
+		return fmt.Errorf("release %s failed verification with %d failure(s)", result.ReleaseSHA256, len(result.Failures))
+	}
+
+	event := log.Info()
+	if publicKey != nil {
+		event = event.Str("public_key_id", catalogsign.KeyID(publicKey))
+	}
+	event.
+		Str("release_sha256", result.ReleaseSHA256).
+		Int("files", result.Files).
+		Int("versions", result.Versions).
+		Msg("Release verified")
+	return nil
+}
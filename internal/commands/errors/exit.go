@@ -0,0 +1,17 @@
+package errors
+
+// ErrExitStatus is returned by commands that completed their work but must
+// report a non-zero exit status other than the default of 1, e.g. when a
+// release was generated without some of its integrations.
+type ErrExitStatus struct {
+	Status int
+	Err    error
+}
+
+func (e ErrExitStatus) Error() string {
+	return e.Err.Error()
+}
+
+func (e ErrExitStatus) Unwrap() error {
+	return e.Err
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"os"
+	"strings"
 
+	"github.com/peterbourgon/ff/v3"
 	"github.com/peterbourgon/ff/v3/ffcli"
 	"github.com/rs/zerolog"
 
@@ -13,6 +16,10 @@ import (
 
 const DefaultLogLevel = "info"
 
+// EnvVarPrefix is the prefix of the environment variables that set the flags
+// of commands, e.g. CATALOG_API_LOG_LEVEL sets --log-level.
+const EnvVarPrefix = "CATALOG_API"
+
 type ExecFn func(context.Context, []string) error
 
 type Config struct {
@@ -28,10 +35,65 @@ func New(cfg *Config) *ffcli.Command {
 		Name:       "catalog-api",
 		ShortUsage: "catalog-api <subcommand> [flags]",
 		FlagSet:    fs,
+		Options:    ParseOptions(),
 		Exec:       cfg.Exec,
 	}
 }
 
+// ParseOptions returns the options that the flags of every command are
+// parsed with. Flags that are not set on the command line are set from
+// environment variables.
+func ParseOptions() []ff.Option {
+	return []ff.Option{ff.WithEnvVarPrefix(EnvVarPrefix)}
+}
+
+// WithScopedEnvVars sets the flags of fs that were not set on the command line
+// from environment variables before executing fn, as ParseOptions does while
+// parsing, except that the scoped flags are set from variables that are also
+// prefixed with scope. E.g. with the scope "validate", --output is set by
+// CATALOG_API_VALIDATE_OUTPUT rather than by the CATALOG_API_OUTPUT of the
+// generate command. Commands using WithScopedEnvVars must be parsed without
+// ParseOptions.
+func WithScopedEnvVars(fs *flag.FlagSet, scope string, scoped []string, fn ExecFn) ExecFn {
+	return func(ctx context.Context, args []string) error {
+		set := map[string]bool{}
+		fs.Visit(func(f *flag.Flag) {
+			set[f.Name] = true
+		})
+		isScoped := map[string]bool{}
+		for _, name := range scoped {
+			isScoped[name] = true
+		}
+
+		var err error
+		fs.VisitAll(func(f *flag.Flag) {
+			if err != nil || set[f.Name] {
+				return
+			}
+			name := f.Name
+			if isScoped[name] {
+				name = scope + "_" + name
+			}
+			key := EnvVarPrefix + "_" + envVarReplacer.Replace(strings.ToUpper(name))
+			value := os.Getenv(key)
+			if value == "" {
+				return
+			}
+			if setErr := fs.Set(f.Name, value); setErr != nil {
+				err = fmt.Errorf("error setting flag %q from env var %q: %w", f.Name, key, setErr)
+			}
+		})
+		if err != nil {
+			return err
+		}
+		return fn(ctx, args)
+	}
+}
+
+// envVarReplacer replaces the characters of flag names that are not valid
+// within environment variable names, as ff does.
+var envVarReplacer = strings.NewReplacer("-", "_", ".", "_", "/", "_")
+
 func usage() string {
 	cmd := New(&Config{})
 
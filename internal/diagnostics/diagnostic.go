@@ -0,0 +1,136 @@
+// Package diagnostics models the findings of checks of a catalog, e.g.
+// validation failures, and renders them for people & CI systems.
+package diagnostics
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Diagnostic is a single finding about an integration version or one of its
+// files.
+type Diagnostic struct {
+	Severity Severity `json:"severity"`
+	RuleID   string   `json:"rule_id"`
+
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Version   string `json:"version,omitempty"`
+
+	// File is the path of the file the diagnostic refers to, relative to the
+	// root of the catalog repository. Line & Column are 1-based and are zero
+	// when unknown.
+	File   string `json:"file,omitempty"`
+	Line   int    `json:"line,omitempty"`
+	Column int    `json:"column,omitempty"`
+
+	Message string `json:"message"`
+}
+
+// Integration returns the namespace & name of the integration the
+// diagnostic refers to, e.g. "nginx/nginx-monitoring", or the namespace
+// alone if the diagnostic refers to a namespace.
+func (d Diagnostic) Integration() string {
+	if d.Name == "" {
+		return d.Namespace
+	}
+	return d.Namespace + "/" + d.Name
+}
+
+// Location returns the file, line & column of the diagnostic in the
+// conventional file:line:column form.
+func (d Diagnostic) Location() string {
+	location := d.File
+	if d.Line > 0 {
+		location += ":" + strconv.Itoa(d.Line)
+		if d.Column > 0 {
+			location += ":" + strconv.Itoa(d.Column)
+		}
+	}
+	return location
+}
+
+func (d Diagnostic) String() string {
+	s := string(d.Severity) + ":"
+	if location := d.Location(); location != "" {
+		s += " " + location + ":"
+	}
+	if integration := d.Integration(); integration != "" {
+		if d.Version != "" {
+			integration += "@" + d.Version
+		}
+		s += " " + integration + ":"
+	}
+	return fmt.Sprintf("%s %s [%s]", s, d.Message, d.RuleID)
+}
+
+// Report is the set of diagnostics of a check of a catalog. Results, if
+// set, holds the outcome of each integration version that was checked.
+type Report struct {
+	Diagnostics []Diagnostic `json:"diagnostics"`
+	Results     []Result     `json:"results,omitempty"`
+}
+
+// NewReport returns a report of the given diagnostics, ordered by
+// integration, version, file & position.
+func NewReport(diagnostics []Diagnostic) Report {
+	sorted := append([]Diagnostic{}, diagnostics...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		if a.Version != b.Version {
+			return a.Version < b.Version
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return Report{Diagnostics: sorted}
+}
+
+// Count returns the number of diagnostics of the given severity.
+func (r Report) Count(severity Severity) int {
+	count := 0
+	for _, d := range r.Diagnostics {
+		if d.Severity == severity {
+			count++
+		}
+	}
+	return count
+}
+
+// HasErrors returns true if any diagnostic is an error.
+func (r Report) HasErrors() bool {
+	return r.Count(SeverityError) > 0
+}
+
+// RuleIDs returns the sorted, distinct rule ids of the diagnostics.
+func (r Report) RuleIDs() []string {
+	ids := []string{}
+	seen := map[string]bool{}
+	for _, d := range r.Diagnostics {
+		if !seen[d.RuleID] {
+			seen[d.RuleID] = true
+			ids = append(ids, d.RuleID)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,226 @@
+package diagnostics
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func fixtureReport() Report {
+	return NewReport([]Diagnostic{
+		{
+			Severity:  SeverityWarning,
+			RuleID:    "readme-load",
+			Namespace: "system",
+			Name:      "host-monitoring",
+			Version:   "0.1.0",
+			File:      "integrations/system/host-monitoring/README.md",
+			Message:   "Failed to load readme",
+		},
+		{
+			Severity:  SeverityError,
+			RuleID:    "config-invalid",
+			Namespace: "nginx",
+			Name:      "nginx-monitoring",
+			Version:   "1.2.3",
+			File:      "integrations/nginx/nginx-monitoring/sensu-integration.yaml",
+			Line:      4,
+			Column:    9,
+			Message:   "Failed to validate integration config: provider must not be empty",
+		},
+	})
+}
+
+func TestDiagnostic_String(t *testing.T) {
+	tests := []struct {
+		name string
+		d    Diagnostic
+		want string
+	}{
+		{
+			name: "full",
+			d:    fixtureReport().Diagnostics[0],
+			want: "error: integrations/nginx/nginx-monitoring/sensu-integration.yaml:4:9: nginx/nginx-monitoring@1.2.3: Failed to validate integration config: provider must not be empty [config-invalid]",
+		},
+		{
+			name: "without location",
+			d:    Diagnostic{Severity: SeverityError, RuleID: "load", Message: "Failed to load integrations"},
+			want: "error: Failed to load integrations [load]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.d.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReport_WriteText(t *testing.T) {
+	var buf bytes.Buffer
+	if err := fixtureReport().WriteText(&buf); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], "error: ") || !strings.HasPrefix(lines[1], "warning: ") {
+		t.Errorf("WriteText() = %q, want the error before the warning", buf.String())
+	}
+	if lines[3] != "1 error(s), 1 warning(s)" {
+		t.Errorf("summary = %q", lines[3])
+	}
+
+	buf.Reset()
+	if err := (Report{}).WriteText(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "No problems found\n" {
+		t.Errorf("WriteText() of an empty report = %q", buf.String())
+	}
+}
+
+func TestReport_WriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := fixtureReport().WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	got := struct {
+		Diagnostics []Diagnostic `json:"diagnostics"`
+		Errors      int          `json:"errors"`
+		Warnings    int          `json:"warnings"`
+	}{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Diagnostics) != 2 || got.Errors != 1 || got.Warnings != 1 {
+		t.Errorf("WriteJSON() = %s", buf.String())
+	}
+	if got.Diagnostics[0] != fixtureReport().Diagnostics[0] {
+		t.Errorf("diagnostic = %+v, want %+v", got.Diagnostics[0], fixtureReport().Diagnostics[0])
+	}
+
+	buf.Reset()
+	if err := (Report{}).WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `"diagnostics": []`) {
+		t.Errorf("WriteJSON() of an empty report = %s", buf.String())
+	}
+}
+
+func TestReport_WriteJUnit(t *testing.T) {
+	var buf bytes.Buffer
+	if err := fixtureReport().WriteJUnit(&buf); err != nil {
+		t.Fatal(err)
+	}
+	got := junitTestSuites{}
+	if err := xml.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Tests != 2 || got.Failures != 1 || len(got.Suites) != 2 {
+		t.Fatalf("WriteJUnit() = %s", buf.String())
+	}
+	nginx := got.Suites[0]
+	if nginx.Name != "nginx/nginx-monitoring" || nginx.Failures != 1 || nginx.TestCases[0].Failure == nil {
+		t.Errorf("suite = %+v, want a failure of nginx/nginx-monitoring", nginx)
+	}
+	if tc := got.Suites[1].TestCases[0]; tc.Failure != nil || !strings.HasPrefix(tc.SystemOut, "warning: ") {
+		t.Errorf("test case = %+v, want a passing test case with a warning", tc)
+	}
+
+	buf.Reset()
+	if err := (Report{}).WriteJUnit(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Tests != 1 || got.Failures != 0 {
+		t.Errorf("WriteJUnit() of an empty report = %s", buf.String())
+	}
+}
+
+func TestReport_WriteSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := fixtureReport().WriteSARIF(&buf); err != nil {
+		t.Fatal(err)
+	}
+	got := sarifLog{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Version != "2.1.0" || len(got.Runs) != 1 {
+		t.Fatalf("WriteSARIF() = %s", buf.String())
+	}
+	run := got.Runs[0]
+	if len(run.Tool.Driver.Rules) != 2 || len(run.Results) != 2 {
+		t.Fatalf("WriteSARIF() = %s", buf.String())
+	}
+	result := run.Results[0]
+	if result.RuleID != "config-invalid" || result.Level != "error" || run.Tool.Driver.Rules[result.RuleIndex].ID != result.RuleID {
+		t.Errorf("result = %+v", result)
+	}
+	region := result.Locations[0].PhysicalLocation.Region
+	if region == nil || region.StartLine != 4 || region.StartColumn != 9 {
+		t.Errorf("region = %+v, want line 4, column 9", region)
+	}
+	if run.Results[1].Level != "warning" || run.Results[1].Locations[0].PhysicalLocation.Region != nil {
+		t.Errorf("result = %+v, want a warning without a region", run.Results[1])
+	}
+}
+
+func TestReport_Write(t *testing.T) {
+	for _, format := range Formats {
+		if !ValidFormat(format) {
+			t.Errorf("ValidFormat(%q) = false", format)
+		}
+		if err := fixtureReport().Write(&bytes.Buffer{}, format); err != nil {
+			t.Errorf("Write(%q) error = %v", format, err)
+		}
+	}
+	if err := fixtureReport().Write(&bytes.Buffer{}, "yaml"); err == nil {
+		t.Errorf("Write(yaml) should fail")
+	}
+}
+
+func TestReport_WithResults(t *testing.T) {
+	report := fixtureReport().WithResults([]Result{
+		{Namespace: "system", Name: "host-monitoring", Version: "0.1.0"},
+		{Namespace: "nginx", Name: "nginx-monitoring", Version: "1.2.3"},
+		{Namespace: "nginx", Name: "nginx-monitoring", Version: "1.2.2"},
+	})
+	want := []string{
+		"ok: nginx/nginx-monitoring@1.2.2",
+		"fail: nginx/nginx-monitoring@1.2.3: 1 error(s), 0 warning(s)",
+		"fail: system/host-monitoring@0.1.0: 0 error(s), 1 warning(s)",
+	}
+	got := []string{}
+	for _, result := range report.Results {
+		got = append(got, result.String())
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("results = %q, want %q", got, want)
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteText(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "\n\n"+strings.Join(want, "\n")+"\n\n") {
+		t.Errorf("WriteText() = %q, want the results after the diagnostics", buf.String())
+	}
+
+	buf.Reset()
+	if err := report.WriteJUnit(&buf); err != nil {
+		t.Fatal(err)
+	}
+	suites := junitTestSuites{}
+	if err := xml.Unmarshal(buf.Bytes(), &suites); err != nil {
+		t.Fatal(err)
+	}
+	if suites.Tests != 3 || suites.Failures != 1 || suites.Suites[0].Tests != 2 {
+		t.Errorf("WriteJUnit() = %s, want a passing test case of the passing version", buf.String())
+	}
+}
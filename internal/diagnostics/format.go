@@ -0,0 +1,94 @@
+package diagnostics
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Formats a report can be written in.
+const (
+	FormatText  = "text"
+	FormatJSON  = "json"
+	FormatJUnit = "junit"
+	FormatSARIF = "sarif"
+)
+
+// Formats lists every supported format.
+var Formats = []string{FormatText, FormatJSON, FormatJUnit, FormatSARIF}
+
+// ValidFormat returns true if format is a supported format.
+func ValidFormat(format string) bool {
+	for _, f := range Formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// Write writes the report in the given format.
+func (r Report) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatText:
+		return r.WriteText(w)
+	case FormatJSON:
+		return r.WriteJSON(w)
+	case FormatJUnit:
+		return r.WriteJUnit(w)
+	case FormatSARIF:
+		return r.WriteSARIF(w)
+	}
+	return fmt.Errorf("unsupported format: %s", format)
+}
+
+// WriteText writes the report as one line per diagnostic, followed by one
+// line per result, if any, and a summary.
+func (r Report) WriteText(w io.Writer) error {
+	var sb strings.Builder
+	for _, d := range r.Diagnostics {
+		sb.WriteString(d.String())
+		sb.WriteString("\n")
+	}
+	if len(r.Results) > 0 {
+		if len(r.Diagnostics) > 0 {
+			sb.WriteString("\n")
+		}
+		for _, result := range r.Results {
+			sb.WriteString(result.String())
+			sb.WriteString("\n")
+		}
+	}
+	if len(r.Diagnostics) == 0 {
+		sb.WriteString("No problems found\n")
+	} else {
+		fmt.Fprintf(&sb, "\n%d error(s), %d warning(s)\n", r.Count(SeverityError), r.Count(SeverityWarning))
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r Report) WriteJSON(w io.Writer) error {
+	v := struct {
+		Diagnostics []Diagnostic `json:"diagnostics"`
+		Results     []Result     `json:"results,omitempty"`
+		Errors      int          `json:"errors"`
+		Warnings    int          `json:"warnings"`
+	}{
+		Diagnostics: r.Diagnostics,
+		Results:     r.Results,
+		Errors:      r.Count(SeverityError),
+		Warnings:    r.Count(SeverityWarning),
+	}
+	if v.Diagnostics == nil {
+		v.Diagnostics = []Diagnostic{}
+	}
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling diagnostics: %w", err)
+	}
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
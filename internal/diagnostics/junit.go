@@ -0,0 +1,121 @@
+package diagnostics
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Line      int           `xml:"line,attr,omitempty"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Type    string `xml:"type,attr"`
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// junitSuiteName is the name of the test suite of diagnostics that do not
+// refer to an integration, and of the passing test case of a report without
+// diagnostics.
+const junitSuiteName = "catalog"
+
+// WriteJUnit writes the report as JUnit XML, with a test suite per
+// integration & a test case per diagnostic. Errors are reported as failures;
+// warnings are reported as passing test cases. Each result without
+// diagnostics is reported as a passing test case too.
+func (r Report) WriteJUnit(w io.Writer) error {
+	suites := junitTestSuites{Name: "catalog-api"}
+	index := map[string]int{}
+	suite := func(name string) int {
+		i, ok := index[name]
+		if !ok {
+			i = len(suites.Suites)
+			index[name] = i
+			suites.Suites = append(suites.Suites, junitTestSuite{Name: name})
+		}
+		return i
+	}
+	for _, d := range r.Diagnostics {
+		suiteName := d.Integration()
+		if suiteName == "" {
+			suiteName = junitSuiteName
+		}
+		i := suite(suiteName)
+
+		name := d.RuleID
+		if d.Version != "" {
+			name = d.Version + " " + name
+		}
+		tc := junitTestCase{
+			ClassName: suiteName,
+			Name:      name,
+			File:      d.File,
+			Line:      d.Line,
+		}
+		text := d.Message
+		if location := d.Location(); location != "" {
+			text = location + ": " + text
+		}
+		if d.Severity == SeverityError {
+			tc.Failure = &junitFailure{Type: d.RuleID, Message: d.Message, Text: text}
+			suites.Suites[i].Failures++
+			suites.Failures++
+		} else {
+			tc.SystemOut = string(d.Severity) + ": " + text
+		}
+		suites.Suites[i].TestCases = append(suites.Suites[i].TestCases, tc)
+		suites.Suites[i].Tests++
+		suites.Tests++
+	}
+	for _, result := range r.Results {
+		if !result.Passed() {
+			continue
+		}
+		suiteName := result.Namespace + "/" + result.Name
+		i := suite(suiteName)
+		tc := junitTestCase{ClassName: suiteName, Name: result.Version + " validate"}
+		suites.Suites[i].TestCases = append(suites.Suites[i].TestCases, tc)
+		suites.Suites[i].Tests++
+		suites.Tests++
+	}
+	if len(suites.Suites) == 0 {
+		suites.Suites = []junitTestSuite{{
+			Name:      junitSuiteName,
+			Tests:     1,
+			TestCases: []junitTestCase{{ClassName: junitSuiteName, Name: "validate"}},
+		}}
+		suites.Tests = 1
+	}
+
+	b, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling junit report: %w", err)
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
@@ -0,0 +1,73 @@
+package diagnostics
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Result is the outcome of the check of a single integration version.
+type Result struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	Errors    int    `json:"errors"`
+	Warnings  int    `json:"warnings"`
+}
+
+// Passed returns true if no diagnostics were found for the integration
+// version.
+func (r Result) Passed() bool {
+	return r.Errors == 0 && r.Warnings == 0
+}
+
+// String returns the result in the form
+// "ok: nginx/nginx-monitoring@1.2.3" or
+// "fail: nginx/nginx-monitoring@1.2.3: 1 error(s), 0 warning(s)".
+func (r Result) String() string {
+	subject := fmt.Sprintf("%s/%s@%s", r.Namespace, r.Name, r.Version)
+	if r.Passed() {
+		return "ok: " + subject
+	}
+	return fmt.Sprintf("fail: %s: %d error(s), %d warning(s)", subject, r.Errors, r.Warnings)
+}
+
+// WithResults returns a copy of the report with a result for each of the
+// given integration versions, ordered by integration & version, whose counts
+// are tallied from the diagnostics of the report.
+func (r Report) WithResults(results []Result) Report {
+	tallied := []Result{}
+	index := map[string]int{}
+	for _, result := range results {
+		result.Errors, result.Warnings = 0, 0
+		index[resultKey(result.Namespace, result.Name, result.Version)] = len(tallied)
+		tallied = append(tallied, result)
+	}
+	for _, d := range r.Diagnostics {
+		i, ok := index[resultKey(d.Namespace, d.Name, d.Version)]
+		if !ok {
+			continue
+		}
+		switch d.Severity {
+		case SeverityError:
+			tallied[i].Errors++
+		case SeverityWarning:
+			tallied[i].Warnings++
+		}
+	}
+	sort.SliceStable(tallied, func(i, j int) bool {
+		a, b := tallied[i], tallied[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Version < b.Version
+	})
+	r.Results = tallied
+	return r
+}
+
+func resultKey(namespace, name, version string) string {
+	return namespace + "/" + name + "@" + version
+}
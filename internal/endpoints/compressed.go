@@ -0,0 +1,97 @@
+package endpoints
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+
+	"github.com/andybalholm/brotli"
+	catalogapiv1 "github.com/sensu/catalog-api/internal/api/catalogapi/v1"
+	"github.com/sensu/catalog-api/internal/output"
+)
+
+// GET /api/:release_sha256/**/*.{json,md,svg}.{br,gz}
+//
+// GenerateCompressedEndpoints writes a pre-compressed sibling of every text
+// endpoint within staging for each supported content coding. Siblings that
+// would not be smaller than the endpoint itself are omitted.
+func GenerateCompressedEndpoints(staging output.Staging) error {
+	// list the endpoints before writing any sibling alongside them
+	names := []string{}
+	err := fs.WalkDir(staging, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !catalogapiv1.IsCompressible(name) {
+			return err
+		}
+		names = append(names, name)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		contents, err := fs.ReadFile(staging, name)
+		if err != nil {
+			return err
+		}
+		for _, encoding := range catalogapiv1.Encodings {
+			compressed, err := Compress(encoding, contents)
+			if err != nil {
+				return err
+			}
+			if len(compressed) >= len(contents) {
+				continue
+			}
+			if err := staging.WriteFile(catalogapiv1.CompressedPath(name, encoding), compressed); err != nil {
+				return fmt.Errorf("error creating compressed endpoint file: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// Compress compresses data with the given content coding. The output only
+// depends on data, so that compressed endpoints are reproducible.
+func Compress(encoding string, data []byte) ([]byte, error) {
+	buf := bytes.Buffer{}
+	var w io.WriteCloser
+	switch encoding {
+	case catalogapiv1.EncodingBrotli:
+		w = brotli.NewWriterLevel(&buf, brotli.BestCompression)
+	case catalogapiv1.EncodingGzip:
+		gw, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+		if err != nil {
+			return nil, err
+		}
+		w = gw
+	default:
+		return nil, fmt.Errorf("unsupported content coding: %s", encoding)
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decompress reverses Compress.
+func Decompress(encoding string, data []byte) ([]byte, error) {
+	var r io.Reader
+	switch encoding {
+	case catalogapiv1.EncodingBrotli:
+		r = brotli.NewReader(bytes.NewReader(data))
+	case catalogapiv1.EncodingGzip:
+		gr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		r = gr
+	default:
+		return nil, fmt.Errorf("unsupported content coding: %s", encoding)
+	}
+	return io.ReadAll(r)
+}
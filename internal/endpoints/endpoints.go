@@ -1,5 +1,9 @@
 package endpoints
 
+// outputRoot is the base path of the endpoints that are generated. Endpoints
+// are written to outputs, which name files relative to their root.
+const outputRoot = ""
+
 type APIEndpoint interface {
 	GetOutputPath() string
 	GetData() interface{}
@@ -0,0 +1,12 @@
+package endpoints
+
+import (
+	catalogapiv1 "github.com/sensu/catalog-api/internal/api/catalogapi/v1"
+	"github.com/sensu/catalog-api/internal/output"
+)
+
+// GET /api/:release_sha256/v1/errors.json
+func GenerateErrorsEndpoint(out output.Output, errors catalogapiv1.ReleaseErrors) error {
+	endpoint := catalogapiv1.NewErrorsEndpoint(outputRoot, errors)
+	return renderJSON(out, endpoint)
+}
@@ -0,0 +1,87 @@
+package endpoints
+
+import (
+	"fmt"
+	"sort"
+
+	catalogapiv1 "github.com/sensu/catalog-api/internal/api/catalogapi/v1"
+	"github.com/sensu/catalog-api/internal/output"
+)
+
+// GET /api/:generated_sha/v1/facets.json
+// GET /api/:generated_sha/v1/:facet/:value.json
+func GenerateFacetEndpoints(out output.Output, nsIntegrations map[string][]catalogapiv1.IntegrationVersion) error {
+	for namespace := range nsIntegrations {
+		if catalogapiv1.IsFacet(namespace) {
+			return fmt.Errorf("namespace name %s is reserved by the catalog api", namespace)
+		}
+	}
+
+	// group the integrations by facet & facet value
+	grouped := map[string]map[string][]catalogapiv1.IntegrationVersion{
+		catalogapiv1.FacetTags:      {},
+		catalogapiv1.FacetProviders: {},
+		catalogapiv1.FacetClasses:   {},
+		catalogapiv1.FacetPlatforms: {},
+	}
+	for _, integrations := range nsIntegrations {
+		for _, iv := range integrations {
+			for _, tag := range uniqueStrings(iv.Tags) {
+				grouped[catalogapiv1.FacetTags][tag] = append(grouped[catalogapiv1.FacetTags][tag], iv)
+			}
+			for _, platform := range uniqueStrings(iv.SupportedPlatforms) {
+				grouped[catalogapiv1.FacetPlatforms][platform] = append(grouped[catalogapiv1.FacetPlatforms][platform], iv)
+			}
+			grouped[catalogapiv1.FacetProviders][iv.Provider] = append(grouped[catalogapiv1.FacetProviders][iv.Provider], iv)
+			grouped[catalogapiv1.FacetClasses][iv.Class] = append(grouped[catalogapiv1.FacetClasses][iv.Class], iv)
+		}
+	}
+
+	counts := map[string]map[string]int{}
+	for facet, values := range grouped {
+		counts[facet] = map[string]int{}
+		for value, integrations := range values {
+			if value == "" {
+				continue
+			}
+			counts[facet][value] = len(integrations)
+
+			sort.Slice(integrations, func(i, j int) bool {
+				a, b := integrations[i].Metadata, integrations[j].Metadata
+				if a.Namespace != b.Namespace {
+					return a.Namespace < b.Namespace
+				}
+				return a.Name < b.Name
+			})
+			fv := catalogapiv1.FacetValue{
+				Facet:        facet,
+				Value:        value,
+				Integrations: integrations,
+			}
+			if err := renderJSON(out, catalogapiv1.NewFacetValueEndpoint(outputRoot, fv)); err != nil {
+				return err
+			}
+		}
+	}
+
+	facets := catalogapiv1.Facets{
+		Tags:      counts[catalogapiv1.FacetTags],
+		Providers: counts[catalogapiv1.FacetProviders],
+		Classes:   counts[catalogapiv1.FacetClasses],
+		Platforms: counts[catalogapiv1.FacetPlatforms],
+	}
+	endpoint := catalogapiv1.NewFacetsEndpoint(outputRoot, facets)
+	return renderJSON(out, endpoint)
+}
+
+func uniqueStrings(values []string) []string {
+	seen := map[string]bool{}
+	unique := []string{}
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
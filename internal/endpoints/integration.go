@@ -3,11 +3,13 @@ package endpoints
 import (
 	catalogv1 "github.com/sensu/catalog-api/internal/api/catalog/v1"
 	catalogapiv1 "github.com/sensu/catalog-api/internal/api/catalogapi/v1"
+	"github.com/sensu/catalog-api/internal/output"
 	"github.com/sensu/catalog-api/internal/types"
+	"github.com/sensu/catalog-api/internal/util"
 )
 
 // GET /api/:generated_sha/v1/integrations/:namespace/:name.json
-func GenerateIntegrationEndpoint(basePath string, integration catalogv1.Integration, ivs types.Integrations) error {
+func GenerateIntegrationEndpoint(out output.Output, integration catalogv1.Integration, ivs types.Integrations) error {
 	versions := []string{}
 	for _, iv := range ivs {
 		versions = append(versions, iv.SemVer())
@@ -17,86 +19,105 @@ func GenerateIntegrationEndpoint(basePath string, integration catalogv1.Integrat
 		Version:     ivs.LatestVersion().SemVer(),
 		Versions:    versions,
 	}
-	endpoint := catalogapiv1.NewIntegrationEndpoint(basePath, integrationWithVersions)
-	return renderJSON(endpoint)
+	endpoint := catalogapiv1.NewIntegrationEndpoint(outputRoot, integrationWithVersions)
+	return renderJSON(out, endpoint)
 }
 
 // GET /api/:generated_sha/v1/integrations/:namespace/:name/versions.json
-func GenerateIntegrationVersionsEndpoint(basePath string, namespace string, integration string, ivs []types.IntegrationVersion) error {
+func GenerateIntegrationVersionsEndpoint(out output.Output, namespace string, integration string, ivs []types.IntegrationVersion) error {
 	versions := []string{}
 	for _, iv := range ivs {
 		versions = append(versions, iv.SemVer())
 	}
-	endpoint := catalogapiv1.NewIntegrationVersionsEndpoint(basePath, namespace, integration, versions)
-	return renderJSON(endpoint)
+	endpoint := catalogapiv1.NewIntegrationVersionsEndpoint(outputRoot, namespace, integration, versions)
+	return renderJSON(out, endpoint)
 }
 
 // GET /api/:generated_sha/v1/integrations/:namespace/:name/:version.json
-func GenerateIntegrationVersionEndpoint(basePath string, integration catalogv1.Integration, version types.IntegrationVersion) error {
+//
+// The endpoint includes a digest of the files within the version directory,
+// so it must be generated after all of the other version endpoints.
+func GenerateIntegrationVersionEndpoint(staging output.Staging, integration catalogv1.Integration, version types.IntegrationVersion) error {
 	iv := catalogapiv1.IntegrationVersion{
 		Integration: integration,
 		Version:     version.SemVer(),
 	}
-	endpoint := catalogapiv1.NewIntegrationVersionEndpoint(basePath, iv)
-	return renderJSON(endpoint)
+	contentSHA256, err := util.CalculateFSChecksum(staging, catalogapiv1.IntegrationVersionDir(outputRoot, iv), "", catalogapiv1.IsCompressedSibling)
+	if err != nil {
+		return err
+	}
+	iv.ContentSHA256 = contentSHA256
+
+	endpoint := catalogapiv1.NewIntegrationVersionEndpoint(outputRoot, iv)
+	return renderJSON(staging, endpoint)
 }
 
 // GET /api/:generated_sha/v1/integrations/:namespace/:name/:version/sensu-resources.json
-func GenerateIntegrationVersionResourcesEndpoint(basePath string, integration catalogv1.Integration, version types.IntegrationVersion, data string) error {
+func GenerateIntegrationVersionResourcesEndpoint(out output.Output, integration catalogv1.Integration, version types.IntegrationVersion, data string) error {
 	iv := catalogapiv1.IntegrationVersion{
 		Integration: integration,
 		Version:     version.SemVer(),
 	}
-	endpoint := catalogapiv1.NewIntegrationVersionResourcesEndpoint(basePath, iv, data)
-	return renderRaw(endpoint)
+	endpoint := catalogapiv1.NewIntegrationVersionResourcesEndpoint(outputRoot, iv, data)
+	return renderRaw(out, endpoint)
 }
 
 // GET /api/:generated_sha/v1/integrations/:namespace/:name/:version/logo.png
-func GenerateIntegrationVersionLogoEndpoint(basePath string, integration catalogv1.Integration, version types.IntegrationVersion, data string) error {
+func GenerateIntegrationVersionLogoEndpoint(out output.Output, integration catalogv1.Integration, version types.IntegrationVersion, data string) error {
 	iv := catalogapiv1.IntegrationVersion{
 		Integration: integration,
 		Version:     version.SemVer(),
 	}
-	endpoint := catalogapiv1.NewIntegrationVersionLogoEndpoint(basePath, iv, data)
-	return renderRaw(endpoint)
+	endpoint := catalogapiv1.NewIntegrationVersionLogoEndpoint(outputRoot, iv, data)
+	return renderRaw(out, endpoint)
 }
 
 // GET /api/:generated_sha/v1/integrations/:namespace/:name/:version/README.md
-func GenerateIntegrationVersionReadmeEndpoint(basePath string, integration catalogv1.Integration, version types.IntegrationVersion, data string) error {
+func GenerateIntegrationVersionReadmeEndpoint(out output.Output, integration catalogv1.Integration, version types.IntegrationVersion, data string) error {
 	iv := catalogapiv1.IntegrationVersion{
 		Integration: integration,
 		Version:     version.SemVer(),
 	}
-	endpoint := catalogapiv1.NewIntegrationVersionReadmeEndpoint(basePath, iv, data)
-	return renderRaw(endpoint)
+	endpoint := catalogapiv1.NewIntegrationVersionReadmeEndpoint(outputRoot, iv, data)
+	return renderRaw(out, endpoint)
 }
 
 // GET /api/:generated_sha/v1/integrations/:namespace/:name/:version/CHANGELOG.md
-func GenerateIntegrationVersionChangelogEndpoint(basePath string, integration catalogv1.Integration, version types.IntegrationVersion, data string) error {
+func GenerateIntegrationVersionChangelogEndpoint(out output.Output, integration catalogv1.Integration, version types.IntegrationVersion, data string) error {
+	iv := catalogapiv1.IntegrationVersion{
+		Integration: integration,
+		Version:     version.SemVer(),
+	}
+	endpoint := catalogapiv1.NewIntegrationVersionChangelogEndpoint(outputRoot, iv, data)
+	return renderRaw(out, endpoint)
+}
+
+// GET /api/:generated_sha/v1/integrations/:namespace/:name/:version/CHANGELOG.json
+func GenerateIntegrationVersionChangelogJSONEndpoint(out output.Output, integration catalogv1.Integration, version types.IntegrationVersion, changelog catalogv1.Changelog) error {
 	iv := catalogapiv1.IntegrationVersion{
 		Integration: integration,
 		Version:     version.SemVer(),
 	}
-	endpoint := catalogapiv1.NewIntegrationVersionChangelogEndpoint(basePath, iv, data)
-	return renderRaw(endpoint)
+	endpoint := catalogapiv1.NewIntegrationVersionChangelogJSONEndpoint(outputRoot, iv, changelog)
+	return renderJSON(out, endpoint)
 }
 
 // GET /api/:generated_sha/v1/integrations/:namespace/:name/:version/img/:image
-func GenerateIntegrationVersionImageEndpoint(basePath string, integration catalogv1.Integration, version types.IntegrationVersion, filename string, data string) error {
+func GenerateIntegrationVersionImageEndpoint(out output.Output, integration catalogv1.Integration, version types.IntegrationVersion, filename string, data string) error {
 	iv := catalogapiv1.IntegrationVersion{
 		Integration: integration,
 		Version:     version.SemVer(),
 	}
-	endpoint := catalogapiv1.NewIntegrationVersionImageEndpoint(basePath, iv, filename, data)
-	return renderRaw(endpoint)
+	endpoint := catalogapiv1.NewIntegrationVersionImageEndpoint(outputRoot, iv, filename, data)
+	return renderRaw(out, endpoint)
 }
 
 // GET /api/:generated_sha/v1/integrations/:namespace/:name/:version/dashboards/:dashboard.json
-func GenerateIntegrationVersionDashboardEndpoint(basePath string, integration catalogv1.Integration, version types.IntegrationVersion, filename string, data string) error {
+func GenerateIntegrationVersionDashboardEndpoint(out output.Output, integration catalogv1.Integration, version types.IntegrationVersion, filename string, data string) error {
 	iv := catalogapiv1.IntegrationVersion{
 		Integration: integration,
 		Version:     version.SemVer(),
 	}
-	endpoint := catalogapiv1.NewIntegrationVersionDashboardEndpoint(basePath, iv, filename, data)
-	return renderRaw(endpoint)
+	endpoint := catalogapiv1.NewIntegrationVersionDashboardEndpoint(outputRoot, iv, filename, data)
+	return renderRaw(out, endpoint)
 }
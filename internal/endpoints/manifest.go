@@ -0,0 +1,29 @@
+package endpoints
+
+import (
+	catalogapiv1 "github.com/sensu/catalog-api/internal/api/catalogapi/v1"
+	"github.com/sensu/catalog-api/internal/output"
+	"github.com/sensu/catalog-api/internal/util"
+)
+
+// GET /api/:release_sha256/manifest.json
+func GenerateManifestEndpoint(staging output.Staging) error {
+	digests, err := util.CalculateFSFileDigests(staging, catalogapiv1.ManifestFileName, catalogapiv1.ManifestFileName+catalogapiv1.SignatureExt)
+	if err != nil {
+		return err
+	}
+
+	manifest := catalogapiv1.Manifest{
+		Files: []catalogapiv1.ManifestFile{},
+	}
+	for _, digest := range digests {
+		manifest.Files = append(manifest.Files, catalogapiv1.ManifestFile{
+			Path:   digest.Path,
+			SHA256: digest.SHA256,
+			Size:   digest.Size,
+		})
+	}
+
+	endpoint := catalogapiv1.NewManifestEndpoint(outputRoot, manifest)
+	return renderJSON(staging, endpoint)
+}
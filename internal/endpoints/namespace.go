@@ -2,19 +2,23 @@ package endpoints
 
 import (
 	catalogapiv1 "github.com/sensu/catalog-api/internal/api/catalogapi/v1"
-	"github.com/sensu/catalog-api/internal/types"
+	"github.com/sensu/catalog-api/internal/output"
 )
 
+// GET /api/:generated_sha/v1/namespaces.json
+func GenerateNamespacesEndpoint(out output.Output, namespaces []catalogapiv1.IntegrationNamespace) error {
+	endpoint := catalogapiv1.NewNamespacesEndpoint(outputRoot, catalogapiv1.Namespaces{Namespaces: namespaces})
+	return renderJSON(out, endpoint)
+}
+
 // GET /api/:generated_sha/v1/integrations/:namespace.json
-func generateIntegrationNamespaceEndpoint(basePath string, namespace string, ivs []types.IntegrationVersion) error {
-	integrations := []string{}
-	for _, iv := range ivs {
-		integrations = append(integrations, iv.Name)
-	}
-	ns := catalogapiv1.IntegrationNamespace{
-		Name:         namespace,
-		Integrations: integrations,
-	}
-	endpoint := catalogapiv1.NewIntegrationNamespaceEndpoint(basePath, ns)
-	return renderJSON(endpoint)
+func GenerateIntegrationNamespaceEndpoint(out output.Output, ns catalogapiv1.IntegrationNamespace) error {
+	endpoint := catalogapiv1.NewIntegrationNamespaceEndpoint(outputRoot, ns)
+	return renderJSON(out, endpoint)
+}
+
+// GET /api/:generated_sha/v1/integrations/:namespace/logo.png
+func GenerateIntegrationNamespaceLogoEndpoint(out output.Output, namespace string, data string) error {
+	endpoint := catalogapiv1.NewIntegrationNamespaceLogoEndpoint(outputRoot, namespace, data)
+	return renderRaw(out, endpoint)
 }
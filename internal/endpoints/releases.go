@@ -0,0 +1,12 @@
+package endpoints
+
+import (
+	catalogapiv1 "github.com/sensu/catalog-api/internal/api/catalogapi/v1"
+	"github.com/sensu/catalog-api/internal/output"
+)
+
+// GET /api/releases.json
+func GenerateReleasesEndpoint(out output.Output, history catalogapiv1.ReleaseHistory) error {
+	endpoint := catalogapiv1.NewReleasesEndpoint(outputRoot, history)
+	return renderJSON(out, endpoint)
+}
@@ -0,0 +1,13 @@
+package endpoints
+
+import (
+	catalogapiv1 "github.com/sensu/catalog-api/internal/api/catalogapi/v1"
+	"github.com/sensu/catalog-api/internal/output"
+	"github.com/sensu/catalog-api/internal/searchindex"
+)
+
+// GET /api/:generated_sha/v1/search-index.json
+func GenerateSearchIndexEndpoint(out output.Output, index searchindex.Index) error {
+	endpoint := catalogapiv1.NewSearchIndexEndpoint(outputRoot, index)
+	return renderJSON(out, endpoint)
+}
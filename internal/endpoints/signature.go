@@ -0,0 +1,28 @@
+package endpoints
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"io/fs"
+
+	catalogapiv1 "github.com/sensu/catalog-api/internal/api/catalogapi/v1"
+	"github.com/sensu/catalog-api/internal/catalogsign"
+	"github.com/sensu/catalog-api/internal/output"
+)
+
+// GET /api/version.json.sig
+// GET /api/:release_sha256/manifest.json.sig
+func GenerateSignatureEndpoint(out output.Output, signedName string, signature catalogapiv1.Signature) error {
+	endpoint := catalogapiv1.NewSignatureEndpoint(signedName, signature)
+	return renderJSON(out, endpoint)
+}
+
+// SignEndpoint signs the endpoint already generated within staging at
+// signedName and generates its signature endpoint alongside it.
+func SignEndpoint(staging output.Staging, signedName string, privateKey ed25519.PrivateKey) error {
+	b, err := fs.ReadFile(staging, signedName)
+	if err != nil {
+		return fmt.Errorf("error reading file to sign: %w", err)
+	}
+	return GenerateSignatureEndpoint(staging, signedName, catalogsign.Sign(privateKey, b))
+}
@@ -1,17 +1,12 @@
 package endpoints
 
 import (
-	"time"
-
 	catalogapiv1 "github.com/sensu/catalog-api/internal/api/catalogapi/v1"
+	"github.com/sensu/catalog-api/internal/output"
 )
 
 // GET /api/version.json
-func GenerateVersionEndpoint(basePath string, sha256 string) error {
-	version := catalogapiv1.ReleaseVersion{
-		ReleaseSHA256: sha256,
-		LastUpdated:   time.Now().Unix(),
-	}
-	endpoint := catalogapiv1.NewVersionEndpoint(basePath, version)
-	return renderJSON(endpoint)
+func GenerateVersionEndpoint(out output.Output, version catalogapiv1.ReleaseVersion) error {
+	endpoint := catalogapiv1.NewVersionEndpoint(outputRoot, version)
+	return renderJSON(out, endpoint)
 }
@@ -6,9 +6,11 @@ import (
 	"fmt"
 	"path"
 	"regexp"
+	"sort"
 
 	git "github.com/go-git/go-git/v5"
 	plumbing "github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
 	"github.com/go-git/go-git/v5/plumbing/object"
 	catalogv1 "github.com/sensu/catalog-api/internal/api/catalog/v1"
 )
@@ -155,6 +157,43 @@ func (l GitLoader) LoadResources() (string, error) {
 	return loadResources(l)
 }
 
+// ListFiles returns the sorted names of the entries of the integration
+// directory at the git ref. The names of directories end with a slash.
+func (l GitLoader) ListFiles() ([]string, error) {
+	// attempt to resolve the git ref to a revision
+	hash, err := l.repo.ResolveRevision(plumbing.Revision(l.ref))
+	if err != nil {
+		return nil, fmt.Errorf("error resolving git revision %s: %w", l.ref, err)
+	}
+
+	// attempt to retrieve the commit object for the hash
+	commit, err := l.repo.CommitObject(*hash)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving commit for hash %s: %w", hash, err)
+	}
+
+	// attempt to retrieve the directory tree of the integration
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving tree for commit %s: %w", hash, err)
+	}
+	integrationTree, err := tree.Tree(l.integrationPath)
+	if err != nil {
+		return nil, fmt.Errorf("error accessing %s for hash %s: %w", l.integrationPath, hash, err)
+	}
+
+	files := []string{}
+	for _, entry := range integrationTree.Entries {
+		name := entry.Name
+		if entry.Mode == filemode.Dir {
+			name += "/"
+		}
+		files = append(files, name)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
 func (l GitLoader) GetFileContentsAsBytes(relativePath string) ([]byte, error) {
 	contents, err := l.GetFileContentsAsString(relativePath)
 	if err != nil {
@@ -2,20 +2,40 @@ package integrationloader
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
 
 	catalogv1 "github.com/sensu/catalog-api/internal/api/catalog/v1"
 	"github.com/sensu/catalog-api/internal/types"
 )
 
+// Names of the files & directories of an integration, relative to the
+// integration directory.
+const (
+	ConfigName        = "sensu-integration.yaml"
+	ResourcesName     = "sensu-resources.yaml"
+	LogoName          = "logo.png"
+	ReadmeName        = "README.md"
+	ChangelogName     = "CHANGELOG.md"
+	ImagesDirName     = "img"
+	DashboardsDirName = "dashboards"
+)
+
+// NamespaceConfigName is the name of the optional metadata file of a
+// namespace, relative to the namespace directory.
+const NamespaceConfigName = "namespace.yaml"
+
 var (
-	defaultConfigName        = "sensu-integration.yaml"
-	defaultResourcesName     = "sensu-resources.yaml"
-	defaultLogoName          = "logo.png"
-	defaultReadmeName        = "README.md"
-	defaultChangelogName     = "CHANGELOG.md"
-	defaultImagesDirName     = "img"
-	defaultDashboardsDirName = "dashboards"
+	defaultConfigName        = ConfigName
+	defaultResourcesName     = ResourcesName
+	defaultLogoName          = LogoName
+	defaultReadmeName        = ReadmeName
+	defaultChangelogName     = ChangelogName
+	defaultImagesDirName     = ImagesDirName
+	defaultDashboardsDirName = DashboardsDirName
 )
 
 type Loader interface {
@@ -26,6 +46,7 @@ type Loader interface {
 	LoadLogo() (string, error)
 	LoadReadme() (string, error)
 	LoadResources() (string, error)
+	ListFiles() ([]string, error)
 	GetFileContentsAsBytes(string) ([]byte, error)
 	GetFileContentsAsString(string) (string, error)
 }
@@ -48,11 +69,44 @@ func loadConfig(loader Loader) (catalogv1.Integration, error) {
 	if err != nil {
 		return integration, err
 	}
-	integration = wrap.Value.(catalogv1.Integration)
+	integration, ok := wrap.Value.(catalogv1.Integration)
+	if !ok {
+		return integration, fmt.Errorf("invalid resource type version: %s, expected %s.Integration", wrap.TypeVersion(), catalogv1.APIVersion)
+	}
 
 	return integration, nil
 }
 
+// LoadNamespace loads the metadata of the namespace whose directory the
+// loader reads files from. The error wraps fs.ErrNotExist if the namespace
+// has no metadata file.
+func LoadNamespace(loader Loader) (catalogv1.Namespace, error) {
+	var namespace catalogv1.Namespace
+
+	b, err := loader.GetFileContentsAsBytes(NamespaceConfigName)
+	if errors.Is(err, object.ErrFileNotFound) {
+		return namespace, fmt.Errorf("%s: %w", NamespaceConfigName, fs.ErrNotExist)
+	} else if err != nil {
+		return namespace, err
+	}
+
+	raw, err := types.RawWrapperFromYAMLBytes(b)
+	if err != nil {
+		return namespace, err
+	}
+
+	wrap, err := types.WrapperFromRawWrapper(raw)
+	if err != nil {
+		return namespace, err
+	}
+	namespace, ok := wrap.Value.(catalogv1.Namespace)
+	if !ok {
+		return namespace, fmt.Errorf("invalid resource type version: %s, expected %s.Namespace", wrap.TypeVersion(), catalogv1.APIVersion)
+	}
+
+	return namespace, nil
+}
+
 func loadChangelog(loader Loader) (string, error) {
 	// TODO(jk): support both .yaml & .yml extensions
 	return loader.GetFileContentsAsString(defaultChangelogName)
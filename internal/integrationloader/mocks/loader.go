@@ -58,6 +58,29 @@ func (_m *Loader) GetFileContentsAsString(_a0 string) (string, error) {
 	return r0, r1
 }
 
+// ListFiles provides a mock function with given fields:
+func (_m *Loader) ListFiles() ([]string, error) {
+	ret := _m.Called()
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func() []string); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // LoadChangelog provides a mock function with given fields:
 func (_m *Loader) LoadChangelog() (string, error) {
 	ret := _m.Called()
@@ -106,6 +106,24 @@ func (l PathLoader) LoadResources() (string, error) {
 	return loadResources(l)
 }
 
+// ListFiles returns the sorted names of the entries of the integration
+// directory. The names of directories end with a slash.
+func (l PathLoader) ListFiles() ([]string, error) {
+	entries, err := os.ReadDir(l.integrationPath)
+	if err != nil {
+		return nil, fmt.Errorf("error listing integration directory: %w", err)
+	}
+	files := []string{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() {
+			name += "/"
+		}
+		files = append(files, name)
+	}
+	return files, nil
+}
+
 func (l PathLoader) GetFileContentsAsBytes(relativePath string) ([]byte, error) {
 	filePath := path.Join(l.integrationPath, relativePath)
 	b, err := os.ReadFile(filePath)
@@ -0,0 +1,116 @@
+package lint
+
+import (
+	"errors"
+	"fmt"
+	"path"
+
+	"github.com/sensu/catalog-api/internal/diagnostics"
+)
+
+// SeverityOff disables a rule.
+const SeverityOff = "off"
+
+const defaultShortDescriptionMaxLength = 120
+
+// Config holds the rule overrides, rule options & suppressions of a catalog
+// repository, as configured by the lint section of its config file.
+type Config struct {
+	// Rules overrides the severity of rules by rule id; a severity of
+	// SeverityOff disables the rule.
+	Rules map[string]string `yaml:"rules"`
+
+	// ShortDescriptionMaxLength is the maximum number of characters of the
+	// short description of an integration. Defaults to 120.
+	ShortDescriptionMaxLength int `yaml:"short_description_max_length"`
+
+	// ReadmeSections lists the headings that every README must contain.
+	ReadmeSections []string `yaml:"readme_sections"`
+
+	// AllowedFiles lists the names of files that may be present within an
+	// integration directory in addition to the files of an integration.
+	// Names of directories end with a slash.
+	AllowedFiles []string `yaml:"allowed_files"`
+
+	// Ignore suppresses the findings of rules for matching integrations.
+	Ignore []Suppression `yaml:"ignore"`
+}
+
+// Suppression suppresses the findings of a rule, or of every rule if Rule is
+// "*", for the integrations matching any of the Integrations patterns, e.g.
+// "nginx/*". Every integration matches if no patterns are given.
+type Suppression struct {
+	Rule         string   `yaml:"rule"`
+	Integrations []string `yaml:"integrations"`
+}
+
+func (c Config) validate() error {
+	ids := map[string]bool{}
+	for _, rule := range Rules() {
+		ids[rule.ID] = true
+	}
+	for id, severity := range c.Rules {
+		if !ids[id] {
+			return fmt.Errorf("unknown lint rule: %s", id)
+		}
+		switch severity {
+		case string(diagnostics.SeverityError), string(diagnostics.SeverityWarning), SeverityOff:
+		default:
+			return fmt.Errorf("severity of lint rule %s must be one of error, warning, off, got: %s", id, severity)
+		}
+	}
+	if c.ShortDescriptionMaxLength < 0 {
+		return errors.New("short_description_max_length must not be negative")
+	}
+	for _, suppression := range c.Ignore {
+		if suppression.Rule != "*" && !ids[suppression.Rule] {
+			return fmt.Errorf("unknown lint rule in ignore: %s", suppression.Rule)
+		}
+		for _, pattern := range suppression.Integrations {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("invalid integration pattern in ignore: %s", pattern)
+			}
+		}
+	}
+	return nil
+}
+
+// severity returns the severity of the rule, and false if the rule is
+// disabled.
+func (c Config) severity(rule Rule) (diagnostics.Severity, bool) {
+	severity, ok := c.Rules[rule.ID]
+	if !ok {
+		return rule.Severity, true
+	}
+	if severity == SeverityOff {
+		return "", false
+	}
+	return diagnostics.Severity(severity), true
+}
+
+// suppressed returns true if the findings of the rule are suppressed for the
+// subject.
+func (c Config) suppressed(ruleID string, subject Subject) bool {
+	integration := subject.Namespace + "/" + subject.Name
+	for _, suppression := range c.Ignore {
+		if suppression.Rule != "*" && suppression.Rule != ruleID {
+			continue
+		}
+		if len(suppression.Integrations) == 0 {
+			return true
+		}
+		for _, pattern := range suppression.Integrations {
+			if ok, _ := path.Match(pattern, integration); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (c Config) shortDescriptionMaxLength() int {
+	if c.ShortDescriptionMaxLength == 0 {
+		return defaultShortDescriptionMaxLength
+	}
+	return c.ShortDescriptionMaxLength
+}
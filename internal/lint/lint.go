@@ -0,0 +1,98 @@
+// Package lint checks integrations against the conventions of a catalog,
+// e.g. the format of tags or the sections of a README, which go beyond the
+// checks required to generate a catalog. Each check is a named rule with a
+// default severity that can be overridden, disabled or suppressed per
+// integration by the catalog repository.
+package lint
+
+import (
+	"path"
+
+	catalogv1 "github.com/sensu/catalog-api/internal/api/catalog/v1"
+	"github.com/sensu/catalog-api/internal/diagnostics"
+	"github.com/sensu/catalog-api/internal/integrationloader"
+)
+
+// Subject is an integration version to lint.
+type Subject struct {
+	Namespace string
+	Name      string
+	Version   string
+
+	// Dir is the path of the integration directory, relative to the catalog
+	// repository.
+	Dir string
+
+	Config catalogv1.Integration
+	Readme string
+
+	// Files holds the names of the entries of the integration directory; the
+	// names of directories end with a slash.
+	Files []string
+}
+
+// Finding is a violation of a rule by a subject.
+type Finding struct {
+	// File is the path of the offending file, relative to the integration
+	// directory. Findings without a file refer to the integration config.
+	File string
+
+	// Field is the dotted path of the offending field of the integration
+	// config, e.g. "tags.1", which is used to position the finding.
+	Field string
+
+	Message string
+}
+
+// Linter lints subjects with the rules enabled by its config.
+type Linter struct {
+	config Config
+	rules  []Rule
+}
+
+func New(config Config) (Linter, error) {
+	if err := config.validate(); err != nil {
+		return Linter{}, err
+	}
+	return Linter{
+		config: config,
+		rules:  Rules(),
+	}, nil
+}
+
+// Lint returns the findings of every enabled rule for the subject as
+// diagnostics, leaving out suppressed findings.
+func (l Linter) Lint(subject Subject) []diagnostics.Diagnostic {
+	diags := []diagnostics.Diagnostic{}
+	for _, rule := range l.rules {
+		severity, enabled := l.config.severity(rule)
+		if !enabled || l.config.suppressed(rule.ID, subject) {
+			continue
+		}
+		for _, finding := range rule.Check(subject, l.config) {
+			diags = append(diags, l.diagnostic(subject, rule, severity, finding))
+		}
+	}
+	return diags
+}
+
+func (l Linter) diagnostic(subject Subject, rule Rule, severity diagnostics.Severity, finding Finding) diagnostics.Diagnostic {
+	d := diagnostics.Diagnostic{
+		Severity:  severity,
+		RuleID:    rule.ID,
+		Namespace: subject.Namespace,
+		Name:      subject.Name,
+		Version:   subject.Version,
+		Message:   finding.Message,
+	}
+	file := finding.File
+	if file == "" {
+		file = integrationloader.ConfigName
+		if finding.Field != "" {
+			pos := subject.Config.Positions.Lookup(finding.Field)
+			d.Line, d.Column = pos.Line, pos.Column
+		}
+	}
+	d.File = path.Join(subject.Dir, file)
+	return d
+}
@@ -0,0 +1,148 @@
+package lint
+
+import (
+	"strings"
+	"testing"
+
+	catalogv1 "github.com/sensu/catalog-api/internal/api/catalog/v1"
+	"github.com/sensu/catalog-api/internal/position"
+)
+
+func fixtureSubject() Subject {
+	config := catalogv1.FixtureIntegration("nginx", "nginx-monitoring")
+	config.DisplayName = "NGINX Monitoring for the Web"
+	config.Tags = []string{"http", "web-server"}
+	config.Contributors = []string{"@octocat", "@sensu-team"}
+	config.ShortDescription = "Monitor NGINX"
+	config.Positions = position.Map{
+		"tags":   {Line: 10, Column: 1},
+		"tags.1": {Line: 12, Column: 5},
+	}
+	return Subject{
+		Namespace: "nginx",
+		Name:      "nginx-monitoring",
+		Version:   "1.2.3",
+		Dir:       "integrations/nginx/nginx-monitoring",
+		Config:    config,
+		Readme:    "# NGINX\n\n## Overview\n\n```\n## Setup\n```\n\n## Metrics ##\n",
+		Files:     []string{"CHANGELOG.md", "README.md", "dashboards/", "img/", "logo.png", "sensu-integration.yaml", "sensu-resources.yaml"},
+	}
+}
+
+func TestLinter_Lint(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		subject func(*Subject)
+		want    []string
+	}{
+		{
+			name: "valid integration",
+		},
+		{
+			name:    "tag format",
+			subject: func(s *Subject) { s.Config.Tags = []string{"http", "Web Server"} },
+			want:    []string{`error: integrations/nginx/nginx-monitoring/sensu-integration.yaml:12:5: nginx/nginx-monitoring@1.2.3: tag "Web Server" is not lowercase kebab-case [tag-format]`},
+		},
+		{
+			name:    "short description length",
+			config:  Config{ShortDescriptionMaxLength: 5},
+			subject: func(s *Subject) {},
+			want:    []string{`warning: integrations/nginx/nginx-monitoring/sensu-integration.yaml: nginx/nginx-monitoring@1.2.3: short_description is 13 characters long, more than the maximum of 5 [short-description-length]`},
+		},
+		{
+			name:    "contributor handle",
+			subject: func(s *Subject) { s.Config.Contributors = []string{"@octocat", "Jane Doe", "@-bad"} },
+			want: []string{
+				`error: integrations/nginx/nginx-monitoring/sensu-integration.yaml: nginx/nginx-monitoring@1.2.3: contributor "Jane Doe" is not a GitHub handle [contributor-handle]`,
+				`error: integrations/nginx/nginx-monitoring/sensu-integration.yaml: nginx/nginx-monitoring@1.2.3: contributor "@-bad" is not a GitHub handle [contributor-handle]`,
+			},
+		},
+		{
+			name:    "display name casing",
+			subject: func(s *Subject) { s.Config.DisplayName = "nginx monitoring" },
+			want:    []string{`warning: integrations/nginx/nginx-monitoring/sensu-integration.yaml: nginx/nginx-monitoring@1.2.3: display_name "nginx monitoring" is not in title case [display-name-casing]`},
+		},
+		{
+			name:    "stray files",
+			config:  Config{AllowedFiles: []string{"NOTICE"}},
+			subject: func(s *Subject) { s.Files = append(s.Files, "NOTICE", "notes.txt", "tmp/") },
+			want: []string{
+				`warning: integrations/nginx/nginx-monitoring/notes.txt: nginx/nginx-monitoring@1.2.3: notes.txt is not part of the integration [stray-files]`,
+				`warning: integrations/nginx/nginx-monitoring/tmp: nginx/nginx-monitoring@1.2.3: tmp/ is not part of the integration [stray-files]`,
+			},
+		},
+		{
+			name:   "readme sections",
+			config: Config{ReadmeSections: []string{"overview", "Setup", "Metrics"}},
+			want:   []string{`warning: integrations/nginx/nginx-monitoring/README.md: nginx/nginx-monitoring@1.2.3: README has no "Setup" section [readme-sections]`},
+		},
+		{
+			name:    "severity override",
+			config:  Config{Rules: map[string]string{RuleDisplayNameCasing: "error"}},
+			subject: func(s *Subject) { s.Config.DisplayName = "nginx" },
+			want:    []string{`error: integrations/nginx/nginx-monitoring/sensu-integration.yaml: nginx/nginx-monitoring@1.2.3: display_name "nginx" is not in title case [display-name-casing]`},
+		},
+		{
+			name:    "disabled rule",
+			config:  Config{Rules: map[string]string{RuleTagFormat: SeverityOff}},
+			subject: func(s *Subject) { s.Config.Tags = []string{"HTTP"} },
+		},
+		{
+			name: "suppressed for matching integrations",
+			config: Config{Ignore: []Suppression{
+				{Rule: RuleTagFormat, Integrations: []string{"nginx/*"}},
+				{Rule: RuleContributorHandle, Integrations: []string{"system/*"}},
+			}},
+			subject: func(s *Subject) {
+				s.Config.Tags = []string{"HTTP"}
+				s.Config.Contributors = []string{"Jane Doe"}
+			},
+			want: []string{`error: integrations/nginx/nginx-monitoring/sensu-integration.yaml: nginx/nginx-monitoring@1.2.3: contributor "Jane Doe" is not a GitHub handle [contributor-handle]`},
+		},
+		{
+			name:    "every rule suppressed",
+			config:  Config{Ignore: []Suppression{{Rule: "*"}}},
+			subject: func(s *Subject) { s.Config.Tags = []string{"HTTP"} },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			linter, err := New(tt.config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			subject := fixtureSubject()
+			if tt.subject != nil {
+				tt.subject(&subject)
+			}
+			got := []string{}
+			for _, d := range linter.Lint(subject) {
+				got = append(got, d.String())
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("Lint() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestNew_invalidConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+	}{
+		{name: "unknown rule", config: Config{Rules: map[string]string{"foo": "error"}}},
+		{name: "invalid severity", config: Config{Rules: map[string]string{RuleTagFormat: "fatal"}}},
+		{name: "negative length", config: Config{ShortDescriptionMaxLength: -1}},
+		{name: "unknown suppressed rule", config: Config{Ignore: []Suppression{{Rule: "foo"}}}},
+		{name: "invalid pattern", config: Config{Ignore: []Suppression{{Rule: "*", Integrations: []string{"["}}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.config); err == nil {
+				t.Error("New() error = nil, want an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/sensu/catalog-api/internal/diagnostics"
+	"github.com/sensu/catalog-api/internal/integrationloader"
+)
+
+// Ids of the lint rules.
+const (
+	RuleTagFormat              = "tag-format"
+	RuleShortDescriptionLength = "short-description-length"
+	RuleContributorHandle      = "contributor-handle"
+	RuleDisplayNameCasing      = "display-name-casing"
+	RuleStrayFiles             = "stray-files"
+	RuleReadmeSections         = "readme-sections"
+)
+
+// Rule is a named check of a subject.
+type Rule struct {
+	ID          string
+	Description string
+
+	// Severity is the severity of the findings of the rule unless it is
+	// overridden by the lint config.
+	Severity diagnostics.Severity
+
+	Check func(subject Subject, config Config) []Finding
+}
+
+// Rules returns every lint rule, ordered by id.
+func Rules() []Rule {
+	return []Rule{
+		{
+			ID:          RuleContributorHandle,
+			Description: "contributors must be GitHub handles, e.g. @octocat",
+			Severity:    diagnostics.SeverityError,
+			Check:       checkContributorHandles,
+		},
+		{
+			ID:          RuleDisplayNameCasing,
+			Description: "display_name must be in title case",
+			Severity:    diagnostics.SeverityWarning,
+			Check:       checkDisplayNameCasing,
+		},
+		{
+			ID:          RuleReadmeSections,
+			Description: "README must contain the sections listed in readme_sections",
+			Severity:    diagnostics.SeverityWarning,
+			Check:       checkReadmeSections,
+		},
+		{
+			ID:          RuleShortDescriptionLength,
+			Description: "short_description must not be longer than short_description_max_length characters",
+			Severity:    diagnostics.SeverityWarning,
+			Check:       checkShortDescriptionLength,
+		},
+		{
+			ID:          RuleStrayFiles,
+			Description: "integration directory must only contain integration files & allowed_files",
+			Severity:    diagnostics.SeverityWarning,
+			Check:       checkStrayFiles,
+		},
+		{
+			ID:          RuleTagFormat,
+			Description: "tags must be lowercase kebab-case, e.g. http-server",
+			Severity:    diagnostics.SeverityError,
+			Check:       checkTagFormat,
+		},
+	}
+}
+
+var (
+	reKebabCase         = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+	reContributorHandle = regexp.MustCompile(`^@[A-Za-z0-9](-?[A-Za-z0-9])*$`)
+)
+
+// IsContributorHandle returns true if contributor is a GitHub handle, e.g.
+// "@octocat", as required by the contributor-handle rule.
+func IsContributorHandle(contributor string) bool {
+	return reContributorHandle.MatchString(contributor)
+}
+
+func checkTagFormat(subject Subject, _ Config) []Finding {
+	findings := []Finding{}
+	for i, tag := range subject.Config.Tags {
+		if !reKebabCase.MatchString(tag) {
+			findings = append(findings, Finding{
+				Field:   fmt.Sprintf("tags.%d", i),
+				Message: fmt.Sprintf("tag %q is not lowercase kebab-case", tag),
+			})
+		}
+	}
+	return findings
+}
+
+func checkShortDescriptionLength(subject Subject, config Config) []Finding {
+	max := config.shortDescriptionMaxLength()
+	length := utf8.RuneCountInString(subject.Config.ShortDescription)
+	if length <= max {
+		return nil
+	}
+	return []Finding{{
+		Field:   "short_description",
+		Message: fmt.Sprintf("short_description is %d characters long, more than the maximum of %d", length, max),
+	}}
+}
+
+func checkContributorHandles(subject Subject, _ Config) []Finding {
+	findings := []Finding{}
+	for i, contributor := range subject.Config.Contributors {
+		if !IsContributorHandle(contributor) {
+			findings = append(findings, Finding{
+				Field:   fmt.Sprintf("contributors.%d", i),
+				Message: fmt.Sprintf("contributor %q is not a GitHub handle", contributor),
+			})
+		}
+	}
+	return findings
+}
+
+// minorWords are the words that remain lowercase within titles, unless they
+// are the first word.
+var minorWords = map[string]bool{
+	"a": true, "an": true, "and": true, "as": true, "at": true, "by": true,
+	"for": true, "in": true, "of": true, "on": true, "or": true, "the": true,
+	"to": true, "via": true, "with": true,
+}
+
+func checkDisplayNameCasing(subject Subject, _ Config) []Finding {
+	for i, word := range strings.Fields(subject.Config.DisplayName) {
+		if i > 0 && minorWords[word] {
+			continue
+		}
+		if r, _ := utf8.DecodeRuneInString(word); unicode.IsLower(r) {
+			return []Finding{{
+				Field:   "display_name",
+				Message: fmt.Sprintf("display_name %q is not in title case", subject.Config.DisplayName),
+			}}
+		}
+	}
+	return nil
+}
+
+// integrationFiles are the files & directories that make up an integration.
+var integrationFiles = []string{
+	integrationloader.ConfigName,
+	integrationloader.ResourcesName,
+	integrationloader.LogoName,
+	integrationloader.ReadmeName,
+	integrationloader.ChangelogName,
+	integrationloader.ImagesDirName + "/",
+	integrationloader.DashboardsDirName + "/",
+}
+
+func checkStrayFiles(subject Subject, config Config) []Finding {
+	allowed := map[string]bool{}
+	for _, name := range integrationFiles {
+		allowed[name] = true
+	}
+	for _, name := range config.AllowedFiles {
+		allowed[name] = true
+	}
+	findings := []Finding{}
+	for _, name := range subject.Files {
+		if !allowed[name] {
+			findings = append(findings, Finding{
+				File:    name,
+				Message: fmt.Sprintf("%s is not part of the integration", name),
+			})
+		}
+	}
+	return findings
+}
+
+func checkReadmeSections(subject Subject, config Config) []Finding {
+	if subject.Readme == "" || len(config.ReadmeSections) == 0 {
+		// missing readmes are reported by validation
+		return nil
+	}
+	headings := map[string]bool{}
+	for _, heading := range markdownHeadings(subject.Readme) {
+		headings[strings.ToLower(heading)] = true
+	}
+	findings := []Finding{}
+	for _, section := range config.ReadmeSections {
+		if !headings[strings.ToLower(section)] {
+			findings = append(findings, Finding{
+				File:    integrationloader.ReadmeName,
+				Message: fmt.Sprintf("README has no %q section", section),
+			})
+		}
+	}
+	return findings
+}
+
+// markdownHeadings returns the text of the ATX headings of a markdown
+// document, e.g. "Setup" for "## Setup", skipping fenced code blocks.
+func markdownHeadings(markdown string) []string {
+	headings := []string{}
+	fenced := false
+	for _, line := range strings.Split(markdown, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "```") || strings.HasPrefix(line, "~~~") {
+			fenced = !fenced
+			continue
+		}
+		if fenced || !strings.HasPrefix(line, "#") {
+			continue
+		}
+		heading := strings.TrimLeft(line, "#")
+		if heading != "" && heading[0] != ' ' {
+			// e.g. a hashtag rather than a heading
+			continue
+		}
+		headings = append(headings, strings.TrimSpace(strings.TrimRight(heading, "# ")))
+	}
+	return headings
+}
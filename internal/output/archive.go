@@ -0,0 +1,175 @@
+package output
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/sensu/catalog-api/internal/util"
+)
+
+// Archive writes files to a tarball or zip archive. Entries are recorded
+// with fixed permissions & the given modification time, and without owners,
+// so archives containing the same files in the same order are identical.
+//
+// The archive is written to a temporary file that replaces path when the
+// archive is closed.
+type Archive struct {
+	path    string
+	modTime time.Time
+	file    *os.File
+	dirs    map[string]bool
+
+	// writeEntry writes a single file or directory (a name ending in /)
+	writeEntry func(name string, data []byte) error
+	close      func() error
+}
+
+// NewTarGz creates a gzip compressed tarball.
+func NewTarGz(path string, modTime time.Time) (*Archive, error) {
+	a, err := newArchive(path, modTime)
+	if err != nil {
+		return nil, err
+	}
+	gw := gzip.NewWriter(a.file)
+	tw := tar.NewWriter(gw)
+	a.writeEntry = func(name string, data []byte) error {
+		hdr := &tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     name,
+			Mode:     0644,
+			Size:     int64(len(data)),
+			ModTime:  a.modTime,
+		}
+		if isDirName(name) {
+			hdr.Typeflag = tar.TypeDir
+			hdr.Mode = 0755
+			hdr.Size = 0
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+	a.close = func() error {
+		if err := tw.Close(); err != nil {
+			return err
+		}
+		return gw.Close()
+	}
+	return a, nil
+}
+
+// NewZip creates a zip archive.
+func NewZip(path string, modTime time.Time) (*Archive, error) {
+	a, err := newArchive(path, modTime)
+	if err != nil {
+		return nil, err
+	}
+	zw := zip.NewWriter(a.file)
+	a.writeEntry = func(name string, data []byte) error {
+		hdr := &zip.FileHeader{
+			Name:     name,
+			Method:   zip.Deflate,
+			Modified: a.modTime,
+		}
+		hdr.SetMode(0644)
+		if isDirName(name) {
+			hdr.Method = zip.Store
+			hdr.SetMode(0755 | os.ModeDir)
+		}
+		w, err := zw.CreateHeader(hdr)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	}
+	a.close = zw.Close
+	return a, nil
+}
+
+func newArchive(path string, modTime time.Time) (*Archive, error) {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return nil, fmt.Errorf("error creating archive: %w", err)
+	}
+	if modTime.IsZero() {
+		modTime = time.Unix(0, 0)
+	}
+	return &Archive{
+		path:    path,
+		modTime: modTime.UTC(),
+		file:    f,
+		dirs:    map[string]bool{},
+	}, nil
+}
+
+func (a *Archive) Path() string {
+	return a.path
+}
+
+func (a *Archive) WriteFile(name string, data []byte) error {
+	if err := validateName(name); err != nil {
+		return err
+	}
+	if err := a.writeDirs(path.Dir(name)); err != nil {
+		return err
+	}
+	return a.writeEntry(name, data)
+}
+
+// writeDirs writes entries for dir & its parents, unless already written.
+func (a *Archive) writeDirs(dir string) error {
+	if dir == "." || a.dirs[dir] {
+		return nil
+	}
+	if err := a.writeDirs(path.Dir(dir)); err != nil {
+		return err
+	}
+	a.dirs[dir] = true
+	return a.writeEntry(dir+"/", nil)
+}
+
+// Close finishes the archive and moves it into place. The archive is
+// discarded if it could not be finished.
+func (a *Archive) Close() (err error) {
+	defer func() {
+		if err != nil {
+			a.Abort()
+		}
+	}()
+
+	if err := a.close(); err != nil {
+		return fmt.Errorf("error finishing archive: %w", err)
+	}
+	if err := a.file.Chmod(0644); err != nil {
+		return err
+	}
+	if err := a.file.Sync(); err != nil {
+		return err
+	}
+	if err := a.file.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(a.file.Name(), a.path); err != nil {
+		return err
+	}
+	return util.SyncDir(filepath.Dir(a.path))
+}
+
+// Abort discards the archive.
+func (a *Archive) Abort() error {
+	a.file.Close()
+	return os.Remove(a.file.Name())
+}
+
+func isDirName(name string) bool {
+	return len(name) > 0 && name[len(name)-1] == '/'
+}
@@ -0,0 +1,47 @@
+package output
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/sensu/catalog-api/internal/util"
+)
+
+// Dir writes files to a directory on the local filesystem & exposes them as an
+// fs.FS. Every file is written atomically, so readers never observe a
+// partially written file.
+type Dir struct {
+	path string
+}
+
+func NewDir(path string) *Dir {
+	return &Dir{path: path}
+}
+
+func (d *Dir) Path() string {
+	return d.path
+}
+
+func (d *Dir) WriteFile(name string, data []byte) error {
+	if err := validateName(name); err != nil {
+		return err
+	}
+	path := filepath.Join(d.path, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return util.WriteFileAtomic(path, data, 0600)
+}
+
+func (d *Dir) Open(name string) (fs.File, error) {
+	return os.DirFS(d.path).Open(name)
+}
+
+func (d *Dir) Close() error {
+	return nil
+}
+
+func (d *Dir) Abort() error {
+	return nil
+}
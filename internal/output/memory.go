@@ -0,0 +1,42 @@
+package output
+
+import (
+	"io/fs"
+	"sync"
+	"testing/fstest"
+)
+
+// Memory holds files in memory and exposes them as an fs.FS, e.g. to stage a
+// release that is written to an archive or bucket without writing it to disk.
+type Memory struct {
+	mu    sync.RWMutex
+	files fstest.MapFS
+}
+
+func NewMemory() *Memory {
+	return &Memory{files: fstest.MapFS{}}
+}
+
+func (m *Memory) WriteFile(name string, data []byte) error {
+	if err := validateName(name); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.files[name] = &fstest.MapFile{Data: append([]byte(nil), data...), Mode: 0644}
+	return nil
+}
+
+func (m *Memory) Close() error {
+	return nil
+}
+
+func (m *Memory) Open(name string) (fs.File, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.files.Open(name)
+}
+
+func (m *Memory) Abort() error {
+	return nil
+}
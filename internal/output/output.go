@@ -0,0 +1,122 @@
+package output
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Output is a destination that generated endpoints are written to. Names are
+// slash separated paths relative to the root of the output, as accepted by
+// fs.ValidPath.
+type Output interface {
+	WriteFile(name string, data []byte) error
+
+	// Close must be called once every file has been written. Files written to
+	// an output may not be visible until it has been closed.
+	Close() error
+
+	// Abort is called instead of Close when writing files failed. Outputs
+	// that only publish files when closed discard them, others keep the files
+	// that have been written.
+	Abort() error
+}
+
+// Staging is an output whose files can be read back. Releases are generated
+// within a staging output, from which the release checksum is calculated,
+// before they are written to their final output.
+type Staging interface {
+	Output
+	fs.FS
+}
+
+// Options configures the outputs created by Parse.
+type Options struct {
+	// ModTime is the modification time recorded for files written to
+	// archives, which keeps archives of the same release identical.
+	ModTime time.Time
+
+	S3 S3Config
+}
+
+// Parse creates an output from a specification of the form "dir:<path>",
+// "tar:<path>" (a gzip compressed tarball), "zip:<path>" or
+// "s3://<bucket>[/<prefix>]".
+func Parse(spec string, opts Options) (Output, error) {
+	if strings.HasPrefix(spec, "s3://") {
+		return NewS3(spec, opts.S3)
+	}
+
+	parts := strings.SplitN(spec, ":", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return nil, fmt.Errorf("invalid output %q, must be one of dir:<path>, tar:<path>, zip:<path> or s3://<bucket>", spec)
+	}
+	kind, path := parts[0], parts[1]
+	switch kind {
+	case "dir":
+		return NewDir(path), nil
+	case "tar":
+		return NewTarGz(path, opts.ModTime)
+	case "zip":
+		return NewZip(path, opts.ModTime)
+	}
+	return nil, fmt.Errorf("unknown output type %q, must be one of dir, tar, zip or s3", kind)
+}
+
+// WriteTree writes every file of fsys to out, within dir. The manifest of a
+// release is written after the other files of the release, so that a release
+// with a manifest is complete, and version.json is written last, after the
+// release it points to & its signature, so that an output that is read while
+// it is being written never points at an incomplete release.
+func WriteTree(out Output, fsys fs.FS, dir string) error {
+	names := []string{}
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		names = append(names, name)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error listing files to write: %w", err)
+	}
+
+	sort.SliceStable(names, func(i, j int) bool {
+		return writeOrder(path.Join(dir, names[i])) < writeOrder(path.Join(dir, names[j]))
+	})
+	for _, name := range names {
+		b, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		if err := out.WriteFile(path.Join(dir, name), b); err != nil {
+			return fmt.Errorf("error writing %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// writeOrder ranks files by the order in which they must be written: files
+// within releases first, then the manifests of releases, then other files at
+// the root, then the version endpoint.
+func writeOrder(name string) int {
+	switch {
+	case name == "version.json":
+		return 4
+	case !strings.Contains(name, "/"):
+		return 3
+	case strings.Count(name, "/") == 1 && path.Base(name) == "manifest.json":
+		return 2
+	}
+	return 1
+}
+
+func validateName(name string) error {
+	if !fs.ValidPath(name) || name == "." {
+		return fmt.Errorf("invalid output file name: %q", name)
+	}
+	return nil
+}
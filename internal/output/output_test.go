@@ -0,0 +1,279 @@
+package output
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+var fixtureFiles = map[string]string{
+	"version.json":                     `{"release_sha256":"abc"}`,
+	"version.json.sig":                 `{"algorithm":"ed25519"}`,
+	"abc/manifest.json":                `{"files":[]}`,
+	"abc/v1/catalog.json":              `{}`,
+	"abc/v1/example_ns/example/1.json": `{"version":"1.0.0"}`,
+}
+
+func writeFixtureTree(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, contents := range fixtureFiles {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// recorder records the order in which files are written.
+type recorder struct {
+	names []string
+}
+
+func (r *recorder) WriteFile(name string, data []byte) error {
+	r.names = append(r.names, name)
+	return nil
+}
+
+func (r *recorder) Close() error { return nil }
+func (r *recorder) Abort() error { return nil }
+
+func TestWriteTree(t *testing.T) {
+	r := &recorder{}
+	if err := WriteTree(r, os.DirFS(writeFixtureTree(t)), ""); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"abc/v1/catalog.json",
+		"abc/v1/example_ns/example/1.json",
+		"abc/manifest.json",
+		"version.json.sig",
+		"version.json",
+	}
+	if !reflect.DeepEqual(r.names, want) {
+		t.Errorf("WriteTree() order = %v, want %v", r.names, want)
+	}
+}
+
+func TestWriteTree_dir(t *testing.T) {
+	fsys := fstest.MapFS{
+		"manifest.json":   &fstest.MapFile{Data: []byte(`{"files":[]}`)},
+		"v1/catalog.json": &fstest.MapFile{Data: []byte(`{}`)},
+	}
+	out := NewMemory()
+	if err := WriteTree(out, fsys, "abc"); err != nil {
+		t.Fatal(err)
+	}
+	for name, file := range fsys {
+		b, err := fs.ReadFile(out, "abc/"+name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(b, file.Data) {
+			t.Errorf("abc/%s = %s, want %s", name, b, file.Data)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	dir := t.TempDir()
+	s3Config := S3Config{AccessKeyID: "id", SecretAccessKey: "secret"}
+
+	tests := []struct {
+		spec    string
+		want    interface{}
+		wantErr bool
+	}{
+		{spec: "dir:" + dir, want: &Dir{}},
+		{spec: "tar:" + filepath.Join(dir, "release.tar.gz"), want: &Archive{}},
+		{spec: "zip:" + filepath.Join(dir, "release.zip"), want: &Archive{}},
+		{spec: "s3://bucket/prefix", want: &S3{}},
+		{spec: dir, wantErr: true},
+		{spec: "dir:", wantErr: true},
+		{spec: "ftp:" + dir, wantErr: true},
+		{spec: "s3://", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := Parse(tt.spec, Options{S3: s3Config})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			defer got.Abort()
+			if reflect.TypeOf(got) != reflect.TypeOf(tt.want) {
+				t.Errorf("Parse() = %T, want %T", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDir(t *testing.T) {
+	dst := filepath.Join(t.TempDir(), "release")
+	out := NewDir(dst)
+	if err := WriteTree(out, os.DirFS(writeFixtureTree(t)), ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := out.Close(); err != nil {
+		t.Fatal(err)
+	}
+	for name, contents := range fixtureFiles {
+		b, err := os.ReadFile(filepath.Join(dst, filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != contents {
+			t.Errorf("%s = %q, want %q", name, b, contents)
+		}
+	}
+
+	if err := out.WriteFile("../escape.json", nil); err == nil {
+		t.Errorf("WriteFile() outside of the directory should fail")
+	}
+}
+
+func TestMemory(t *testing.T) {
+	out := NewMemory()
+	if err := WriteTree(out, os.DirFS(writeFixtureTree(t)), ""); err != nil {
+		t.Fatal(err)
+	}
+	names := []string{}
+	for name := range fixtureFiles {
+		names = append(names, name)
+	}
+	if err := fstest.TestFS(out, names...); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestArchive(t *testing.T) {
+	readTar := func(t *testing.T, b []byte) map[string]string {
+		gr, err := gzip.NewReader(bytes.NewReader(b))
+		if err != nil {
+			t.Fatal(err)
+		}
+		files := map[string]string{}
+		tr := tar.NewReader(gr)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				return files
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if hdr.Typeflag == tar.TypeDir {
+				continue
+			}
+			if hdr.Mode != 0644 || hdr.Uid != 0 || hdr.Uname != "" {
+				t.Errorf("%s mode = %o, owner = %d %q", hdr.Name, hdr.Mode, hdr.Uid, hdr.Uname)
+			}
+			contents, err := io.ReadAll(tr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			files[hdr.Name] = string(contents)
+		}
+	}
+	readZip := func(t *testing.T, b []byte) map[string]string {
+		zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		files := map[string]string{}
+		err = fs.WalkDir(zr, ".", func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			contents, err := fs.ReadFile(zr, path)
+			files[path] = string(contents)
+			return err
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return files
+	}
+
+	tests := []struct {
+		name       string
+		newArchive func(path string, modTime time.Time) (*Archive, error)
+		read       func(t *testing.T, b []byte) map[string]string
+	}{
+		{name: "tar", newArchive: NewTarGz, read: readTar},
+		{name: "zip", newArchive: NewZip, read: readZip},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := writeFixtureTree(t)
+			dir := t.TempDir()
+
+			// archives of the same files must be identical
+			var archives [][]byte
+			for i := 0; i < 2; i++ {
+				path := filepath.Join(dir, "release")
+				out, err := tt.newArchive(path, time.Unix(1643664852, 0))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := WriteTree(out, os.DirFS(src), ""); err != nil {
+					t.Fatal(err)
+				}
+				if err := out.Close(); err != nil {
+					t.Fatal(err)
+				}
+				b, err := os.ReadFile(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				archives = append(archives, b)
+			}
+			if !bytes.Equal(archives[0], archives[1]) {
+				t.Errorf("archives of the same files differ")
+			}
+
+			got := tt.read(t, archives[0])
+			want := map[string]string{}
+			for name, contents := range fixtureFiles {
+				want[name] = contents
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("archive files = %v, want %v", got, want)
+			}
+
+			// an aborted archive must not replace the existing archive nor leave
+			// temporary files behind
+			out, err := tt.newArchive(filepath.Join(dir, "release"), time.Time{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := out.WriteFile("version.json", []byte("{}")); err != nil {
+				t.Fatal(err)
+			}
+			if err := out.Abort(); err != nil {
+				t.Fatal(err)
+			}
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != 1 {
+				t.Errorf("Abort() left files behind: %v", entries)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package output
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	defaultS3Region = "us-east-1"
+
+	// defaultS3Timeout bounds the upload of a single object, so that an
+	// unresponsive object store cannot stall a release indefinitely.
+	defaultS3Timeout = 5 * time.Minute
+)
+
+type S3Config struct {
+	// Endpoint is the URL of an S3 compatible object store, e.g.
+	// http://localhost:9000. Buckets are addressed by path when an endpoint is
+	// given, and as virtual hosts of the AWS S3 endpoint of Region otherwise.
+	Endpoint string
+	Region   string
+
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+
+	// Client is the HTTP client used to upload files. A client that times out
+	// after defaultS3Timeout is used if nil.
+	Client *http.Client
+}
+
+// S3ConfigFromEnv returns the configuration described by the standard AWS
+// environment variables.
+func S3ConfigFromEnv() S3Config {
+	config := S3Config{
+		Endpoint:        os.Getenv("AWS_ENDPOINT_URL_S3"),
+		Region:          os.Getenv("AWS_REGION"),
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+	if config.Region == "" {
+		config.Region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	return config
+}
+
+func (c S3Config) validate() error {
+	if c.AccessKeyID == "" || c.SecretAccessKey == "" {
+		return errors.New("access key id & secret access key must not be empty")
+	}
+	if c.Endpoint != "" {
+		u, err := url.Parse(c.Endpoint)
+		if err != nil {
+			return fmt.Errorf("invalid endpoint: %w", err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+			return fmt.Errorf("invalid endpoint, must be an http or https url: %s", c.Endpoint)
+		}
+	}
+	return nil
+}
+
+// S3 uploads files as objects to an S3 bucket. Objects are uploaded as soon
+// as they are written, so an aborted upload leaves the objects that have
+// been uploaded in place.
+type S3 struct {
+	config S3Config
+	bucket string
+	prefix string
+	signer sigV4Signer
+	now    func() time.Time
+}
+
+// NewS3 creates an output for a bucket URL of the form
+// s3://<bucket>[/<prefix>].
+func NewS3(bucketURL string, config S3Config) (*S3, error) {
+	u, err := url.Parse(bucketURL)
+	if err != nil || u.Scheme != "s3" || u.Host == "" {
+		return nil, fmt.Errorf("invalid bucket url, must be of the form s3://<bucket>[/<prefix>]: %s", bucketURL)
+	}
+	if config.Region == "" {
+		config.Region = defaultS3Region
+	}
+	if config.Client == nil {
+		config.Client = &http.Client{Timeout: defaultS3Timeout}
+	}
+	if err := config.validate(); err != nil {
+		return nil, fmt.Errorf("s3 config validation failed: %w", err)
+	}
+
+	return &S3{
+		config: config,
+		bucket: u.Host,
+		prefix: strings.Trim(u.Path, "/"),
+		signer: sigV4Signer{
+			region:          config.Region,
+			service:         "s3",
+			accessKeyID:     config.AccessKeyID,
+			secretAccessKey: config.SecretAccessKey,
+			sessionToken:    config.SessionToken,
+		},
+		now: time.Now,
+	}, nil
+}
+
+func (s *S3) WriteFile(name string, data []byte) error {
+	if err := validateName(name); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPut, s.objectURL(path.Join(s.prefix, name)), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	if contentType := mime.TypeByExtension(path.Ext(name)); contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	s.signer.sign(req, hashHex(data), s.now())
+
+	resp, err := s.config.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error uploading object: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("error uploading object: %s: %s", resp.Status, bytes.TrimSpace(body))
+	}
+	return nil
+}
+
+func (s *S3) Close() error {
+	return nil
+}
+
+func (s *S3) Abort() error {
+	return nil
+}
+
+func (s *S3) objectURL(key string) string {
+	escapedKey := uriEncode(key, false)
+	if s.config.Endpoint != "" {
+		return strings.TrimSuffix(s.config.Endpoint, "/") + "/" + uriEncode(s.bucket, true) + "/" + escapedKey
+	}
+	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", s.bucket, s.config.Region, escapedKey)
+}
@@ -0,0 +1,194 @@
+// Package position tracks positions within yaml source files and attaches
+// them to errors, so that errors can be traced to the offending line.
+package position
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Position is a position within a source file. Line & Column are 1-based and
+// are zero when unknown.
+type Position struct {
+	Line   int
+	Column int
+}
+
+// Of returns the position of a yaml node.
+func Of(node *yaml.Node) Position {
+	if node == nil {
+		return Position{}
+	}
+	return Position{Line: node.Line, Column: node.Column}
+}
+
+func (p Position) IsValid() bool {
+	return p.Line > 0
+}
+
+func (p Position) String() string {
+	if !p.IsValid() {
+		return ""
+	}
+	if p.Column > 0 {
+		return fmt.Sprintf("%d:%d", p.Line, p.Column)
+	}
+	return strconv.Itoa(p.Line)
+}
+
+// Map maps the dotted paths of the keys & items of a yaml document, e.g.
+// "metadata.name" or "tags.0", to their positions.
+type Map map[string]Position
+
+// MapOf returns the positions of the keys & items within a yaml node. The
+// position of the node itself is recorded as the empty path.
+func MapOf(node *yaml.Node) Map {
+	m := Map{}
+	m.add("", node)
+	return m
+}
+
+func (m Map) add(path string, node *yaml.Node) {
+	if node == nil {
+		return
+	}
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	m[path] = Of(node)
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			keyPath := join(path, node.Content[i].Value)
+			m.add(keyPath, node.Content[i+1])
+			// errors refer to the key rather than to its value
+			m[keyPath] = Of(node.Content[i])
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			m.add(join(path, strconv.Itoa(i)), item)
+		}
+	}
+}
+
+// Sub returns the positions below path, relative to path.
+func (m Map) Sub(path string) Map {
+	sub := Map{}
+	for p, pos := range m {
+		if p == path {
+			sub[""] = pos
+		} else if strings.HasPrefix(p, path+".") {
+			sub[strings.TrimPrefix(p, path+".")] = pos
+		}
+	}
+	return sub
+}
+
+// Lookup returns the position of path. The position of the closest parent
+// is returned when path is not present, e.g. when a required key is
+// missing.
+func (m Map) Lookup(path string) Position {
+	for {
+		if pos, ok := m[path]; ok {
+			return pos
+		}
+		if path == "" {
+			return Position{}
+		}
+		i := strings.LastIndex(path, ".")
+		if i < 0 {
+			path = ""
+		} else {
+			path = path[:i]
+		}
+	}
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// Error is an error at a position within a file. The position is only
+// included in the message once the file is known, i.e. once the error has
+// been wrapped by InFile; until then errors that wrap the error, e.g. with
+// fmt.Errorf, do not repeat it.
+type Error struct {
+	File string
+	Position
+	Err error
+}
+
+// At returns err at the given position, or nil if err is nil.
+func At(pos Position, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Position: pos, Err: err}
+}
+
+func (e *Error) Error() string {
+	if e.File == "" {
+		return e.Err.Error()
+	}
+	location := e.File
+	if e.IsValid() {
+		location += ":" + e.Position.String()
+	}
+	return location + ": " + e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Find returns the outermost position error within err, if any.
+func Find(err error) (*Error, bool) {
+	var posErr *Error
+	if errors.As(err, &posErr) {
+		return posErr, true
+	}
+	return nil, false
+}
+
+// InFile returns err within the given file, at the position of the position
+// error within err, if any. err is returned as is if it contains no position
+// or if its file is known already.
+func InFile(file string, err error) error {
+	posErr, ok := Find(err)
+	if !ok || posErr.File != "" {
+		return err
+	}
+	return &Error{File: file, Position: posErr.Position, Err: err}
+}
+
+var reYAMLLine = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+
+// FromYAML converts the errors of the yaml package, which report the line
+// within their message, into position errors.
+func FromYAML(err error) error {
+	if err == nil {
+		return nil
+	}
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) && len(typeErr.Errors) > 0 {
+		// report the first error; the others are usually caused by it
+		if match := reYAMLLine.FindStringSubmatch(typeErr.Errors[0]); match != nil {
+			line, _ := strconv.Atoi(match[1])
+			return &Error{Position: Position{Line: line}, Err: errors.New(match[2])}
+		}
+		return err
+	}
+	if match := reYAMLLine.FindStringSubmatch(err.Error()); match != nil {
+		line, _ := strconv.Atoi(match[1])
+		return &Error{Position: Position{Line: line}, Err: errors.New(match[2])}
+	}
+	return err
+}
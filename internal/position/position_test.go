@@ -0,0 +1,119 @@
+package position
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const fixtureYAML = `metadata:
+  name: example
+spec:
+  display_name: Example
+  tags:
+    - one
+    - two
+`
+
+func TestMapOf(t *testing.T) {
+	node := yaml.Node{}
+	if err := yaml.Unmarshal([]byte(fixtureYAML), &node); err != nil {
+		t.Fatal(err)
+	}
+	m := MapOf(&node)
+
+	tests := []struct {
+		path string
+		want Position
+	}{
+		{path: "", want: Position{Line: 1, Column: 1}},
+		{path: "metadata.name", want: Position{Line: 2, Column: 3}},
+		{path: "spec.display_name", want: Position{Line: 4, Column: 3}},
+		{path: "spec.tags.1", want: Position{Line: 7, Column: 7}},
+		// missing keys resolve to their closest parent
+		{path: "spec.class", want: Position{Line: 3, Column: 1}},
+		{path: "status.phase", want: Position{Line: 1, Column: 1}},
+	}
+	for _, tt := range tests {
+		if got := m.Lookup(tt.path); got != tt.want {
+			t.Errorf("Lookup(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+
+	if got := m.Sub("spec").Lookup("display_name"); got != (Position{Line: 4, Column: 3}) {
+		t.Errorf("Sub(spec).Lookup(display_name) = %v", got)
+	}
+	if got := (Map(nil)).Lookup("spec"); got.IsValid() {
+		t.Errorf("Lookup() on a nil map = %v, want an invalid position", got)
+	}
+}
+
+func TestError(t *testing.T) {
+	err := At(Position{Line: 4, Column: 3}, errors.New("display_name cannot be empty"))
+	if got, want := err.Error(), "display_name cannot be empty"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+
+	wrapped := InFile("integrations/nginx/nginx/sensu-integration.yaml", fmt.Errorf("integration config: %w", err))
+	if got, want := wrapped.Error(), "integrations/nginx/nginx/sensu-integration.yaml:4:3: integration config: display_name cannot be empty"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+
+	// the file is only recorded once
+	if got := InFile("other.yaml", wrapped); got != wrapped {
+		t.Errorf("InFile() = %v, want %v", got, wrapped)
+	}
+	if posErr, ok := Find(wrapped); !ok || posErr.File != "integrations/nginx/nginx/sensu-integration.yaml" || posErr.Line != 4 {
+		t.Errorf("Find() = %v, %v", posErr, ok)
+	}
+
+	// errors without a position are left as is
+	plain := errors.New("read error")
+	if got := InFile("sensu-integration.yaml", plain); got != plain {
+		t.Errorf("InFile() = %v, want %v", got, plain)
+	}
+	if At(Position{}, nil) != nil {
+		t.Errorf("At() of a nil error should be nil")
+	}
+}
+
+func TestFromYAML(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		wantLine int
+		wantMsg  string
+	}{
+		{
+			name:     "syntax error",
+			data:     "a: 1\nb: [\n",
+			wantLine: 2,
+			wantMsg:  "did not find expected node content",
+		},
+		{
+			name:     "type error",
+			data:     "a: 1\nb: two\n",
+			wantLine: 2,
+			wantMsg:  "cannot unmarshal !!str `two` into int",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := map[string]int{}
+			err := FromYAML(yaml.Unmarshal([]byte(tt.data), &v))
+			posErr, ok := Find(err)
+			if !ok {
+				t.Fatalf("FromYAML() = %v, want a position error", err)
+			}
+			if posErr.Line != tt.wantLine || posErr.Err.Error() != tt.wantMsg {
+				t.Errorf("FromYAML() = line %d: %q, want line %d: %q", posErr.Line, posErr.Err, tt.wantLine, tt.wantMsg)
+			}
+		})
+	}
+
+	if FromYAML(nil) != nil {
+		t.Errorf("FromYAML() of a nil error should be nil")
+	}
+}
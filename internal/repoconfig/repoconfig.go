@@ -0,0 +1,130 @@
+// Package repoconfig loads the configuration of a catalog repository, which
+// holds the taxonomy of its integrations, its lint settings & the defaults of
+// the options of the catalog commands.
+package repoconfig
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+
+	catalogv1 "github.com/sensu/catalog-api/internal/api/catalog/v1"
+	"github.com/sensu/catalog-api/internal/lint"
+	"github.com/sensu/catalog-api/internal/position"
+)
+
+// FileName is the name of the config file within the root of a catalog
+// repository.
+const FileName = ".catalog-api.yaml"
+
+type Config struct {
+	// IntegrationsDir is the path of the directory containing the namespaced
+	// integrations, relative to the catalog repository.
+	IntegrationsDir string `yaml:"integrations_dir"`
+
+	// Providers & Classes replace the providers & classes of the Sensu
+	// catalog. Platforms & Tags, if set, restrict the supported platforms &
+	// tags of integrations.
+	Providers []string `yaml:"providers"`
+	Classes   []string `yaml:"classes"`
+	Platforms []string `yaml:"platforms"`
+	Tags      []string `yaml:"tags"`
+
+	Lint     lint.Config `yaml:"lint"`
+	Generate Generate    `yaml:"generate"`
+	Server   Server      `yaml:"server"`
+}
+
+// Generate holds the defaults of the options of generating releases.
+type Generate struct {
+	TempDir      string `yaml:"temp_dir"`
+	Compress     bool   `yaml:"compress"`
+	Reproducible bool   `yaml:"reproducible"`
+	KeepGoing    bool   `yaml:"keep_going"`
+	MaxExcluded  int    `yaml:"max_excluded"`
+}
+
+// Server holds the defaults of the options of the catalog server.
+type Server struct {
+	Port int `yaml:"port"`
+}
+
+// Load loads the config from the file at path. The empty config is returned
+// if the file does not exist.
+func Load(path string) (Config, error) {
+	config := Config{}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	} else if err != nil {
+		return config, fmt.Errorf("error reading catalog config: %w", err)
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	if err := dec.Decode(&config); err != nil && !errors.Is(err, io.EOF) {
+		return config, fmt.Errorf("error parsing catalog config: %w", position.InFile(path, position.FromYAML(err)))
+	}
+	if err := config.validate(); err != nil {
+		return config, fmt.Errorf("invalid catalog config %s: %w", path, err)
+	}
+	return config, nil
+}
+
+func (c Config) validate() error {
+	if c.Generate.MaxExcluded < 0 {
+		return errors.New("generate.max_excluded must not be negative")
+	}
+	if c.Server.Port < 0 || c.Server.Port > 65535 {
+		return fmt.Errorf("server.port must be between 0 and 65535, got: %d", c.Server.Port)
+	}
+	if _, err := lint.New(c.Lint); err != nil {
+		return fmt.Errorf("lint: %w", err)
+	}
+	return nil
+}
+
+// Taxonomy returns the taxonomy that integrations are validated against.
+func (c Config) Taxonomy() catalogv1.Taxonomy {
+	return catalogv1.Taxonomy{
+		Providers: c.Providers,
+		Classes:   c.Classes,
+		Platforms: c.Platforms,
+		Tags:      c.Tags,
+	}
+}
+
+// FlagValues returns the options set by the config as values of the flags of
+// the catalog commands, keyed by flag name.
+func (c Config) FlagValues() map[string]string {
+	values := map[string]string{}
+	setString := func(name string, value string) {
+		if value != "" {
+			values[name] = value
+		}
+	}
+	setBool := func(name string, value bool) {
+		if value {
+			values[name] = strconv.FormatBool(value)
+		}
+	}
+	setInt := func(name string, value int) {
+		if value != 0 {
+			values[name] = strconv.Itoa(value)
+		}
+	}
+
+	setString("integrations-dir-name", c.IntegrationsDir)
+	setString("temp-dir", c.Generate.TempDir)
+	setBool("compress", c.Generate.Compress)
+	setBool("reproducible", c.Generate.Reproducible)
+	setBool("keep-going", c.Generate.KeepGoing)
+	setInt("max-excluded", c.Generate.MaxExcluded)
+	setInt("port", c.Server.Port)
+	return values
+}
@@ -0,0 +1,122 @@
+package repoconfig
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sensu/catalog-api/internal/lint"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name       string
+		contents   string
+		want       Config
+		wantErrMsg string
+	}{
+		{
+			name:     "empty file",
+			contents: "",
+			want:     Config{},
+		},
+		{
+			name: "every option",
+			contents: `integrations_dir: catalog
+providers: [alerts, tracing]
+classes: [official]
+platforms: [linux]
+tags: [http]
+lint:
+  rules:
+    stray-files: off
+  readme_sections: [Setup]
+generate:
+  temp_dir: /tmp/catalog
+  compress: true
+  keep_going: true
+  max_excluded: 2
+server:
+  port: 9000
+`,
+			want: Config{
+				IntegrationsDir: "catalog",
+				Providers:       []string{"alerts", "tracing"},
+				Classes:         []string{"official"},
+				Platforms:       []string{"linux"},
+				Tags:            []string{"http"},
+				Lint: lint.Config{
+					Rules:          map[string]string{lint.RuleStrayFiles: lint.SeverityOff},
+					ReadmeSections: []string{"Setup"},
+				},
+				Generate: Generate{TempDir: "/tmp/catalog", Compress: true, KeepGoing: true, MaxExcluded: 2},
+				Server:   Server{Port: 9000},
+			},
+		},
+		{
+			name:       "unknown field",
+			contents:   "providers: [alerts]\nprovider: tracing\n",
+			wantErrMsg: ".catalog-api.yaml:2: field provider not found in type repoconfig.Config",
+		},
+		{
+			name:       "unknown lint rule",
+			contents:   "lint:\n  rules:\n    foo: error\n",
+			wantErrMsg: "lint: unknown lint rule: foo",
+		},
+		{
+			name:       "negative max excluded",
+			contents:   "generate:\n  max_excluded: -1\n",
+			wantErrMsg: "generate.max_excluded must not be negative",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), FileName)
+			if err := os.WriteFile(path, []byte(tt.contents), 0600); err != nil {
+				t.Fatal(err)
+			}
+			got, err := Load(path)
+			if tt.wantErrMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErrMsg) {
+					t.Fatalf("Load() error = %v, want %s", err, tt.wantErrMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Load() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad_missingFile(t *testing.T) {
+	got, err := Load(filepath.Join(t.TempDir(), FileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, Config{}) {
+		t.Errorf("Load() = %+v, want the empty config", got)
+	}
+}
+
+func TestConfig_FlagValues(t *testing.T) {
+	config := Config{
+		IntegrationsDir: "catalog",
+		Generate:        Generate{Compress: true, MaxExcluded: 3},
+		Server:          Server{Port: 9000},
+	}
+	want := map[string]string{
+		"integrations-dir-name": "catalog",
+		"compress":              "true",
+		"max-excluded":          "3",
+		"port":                  "9000",
+	}
+	if got := config.FlagValues(); !reflect.DeepEqual(got, want) {
+		t.Errorf("FlagValues() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,317 @@
+package searchindex
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+const (
+	// LunrVersion is the version of lunr.js whose serialization format the
+	// generated index follows.
+	LunrVersion = "2.3.9"
+
+	// BM25 tuning parameters, matching the lunr.js defaults
+	bm25K1 = 1.2
+	bm25B  = 0.75
+
+	fieldRefJoiner = "/"
+)
+
+// Field is a searchable document field. Boost is the relative weight of
+// matches in this field when scoring results.
+type Field struct {
+	Name  string
+	Boost float64
+}
+
+// Index is a search index serialized in the format expected by
+// lunr.Index.load().
+type Index struct {
+	Version       string               `json:"version"`
+	Fields        []string             `json:"fields"`
+	FieldVectors  []FieldVector        `json:"fieldVectors"`
+	InvertedIndex []InvertedIndexEntry `json:"invertedIndex"`
+	Pipeline      []string             `json:"pipeline"`
+}
+
+// FieldVector is the term vector of a single field of a single document,
+// serialized as [fieldRef, [termIndex, score, termIndex, score, ...]].
+type FieldVector struct {
+	FieldRef string
+	Elements []float64
+}
+
+func (v FieldVector) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{v.FieldRef, v.Elements})
+}
+
+func (v *FieldVector) UnmarshalJSON(b []byte) error {
+	tuple := []json.RawMessage{}
+	if err := json.Unmarshal(b, &tuple); err != nil {
+		return err
+	}
+	if len(tuple) != 2 {
+		return fmt.Errorf("field vector must have 2 elements, got: %d", len(tuple))
+	}
+	if err := json.Unmarshal(tuple[0], &v.FieldRef); err != nil {
+		return fmt.Errorf("error unmarshaling field vector ref: %w", err)
+	}
+	if err := json.Unmarshal(tuple[1], &v.Elements); err != nil {
+		return fmt.Errorf("error unmarshaling field vector elements: %w", err)
+	}
+	return nil
+}
+
+// DocRef returns the reference of the document the field vector belongs to.
+func (v FieldVector) DocRef() string {
+	n := strings.Index(v.FieldRef, fieldRefJoiner)
+	return v.FieldRef[n+1:]
+}
+
+// InvertedIndexEntry maps a term to the documents containing it, serialized
+// as [term, {"_index": n, "<field>": {"<ref>": {}}}].
+type InvertedIndexEntry struct {
+	Term     string
+	Index    int
+	Postings map[string][]string
+}
+
+func (e InvertedIndexEntry) MarshalJSON() ([]byte, error) {
+	posting := map[string]interface{}{
+		"_index": e.Index,
+	}
+	for field, refs := range e.Postings {
+		docs := map[string]struct{}{}
+		for _, ref := range refs {
+			docs[ref] = struct{}{}
+		}
+		posting[field] = docs
+	}
+	return json.Marshal([]interface{}{e.Term, posting})
+}
+
+func (e *InvertedIndexEntry) UnmarshalJSON(b []byte) error {
+	tuple := []json.RawMessage{}
+	if err := json.Unmarshal(b, &tuple); err != nil {
+		return err
+	}
+	if len(tuple) != 2 {
+		return fmt.Errorf("inverted index entry must have 2 elements, got: %d", len(tuple))
+	}
+	if err := json.Unmarshal(tuple[0], &e.Term); err != nil {
+		return fmt.Errorf("error unmarshaling inverted index term: %w", err)
+	}
+	posting := map[string]json.RawMessage{}
+	if err := json.Unmarshal(tuple[1], &posting); err != nil {
+		return fmt.Errorf("error unmarshaling posting for term %s: %w", e.Term, err)
+	}
+
+	e.Postings = map[string][]string{}
+	for field, value := range posting {
+		if field == "_index" {
+			if err := json.Unmarshal(value, &e.Index); err != nil {
+				return fmt.Errorf("error unmarshaling index for term %s: %w", e.Term, err)
+			}
+			continue
+		}
+		docs := map[string]json.RawMessage{}
+		if err := json.Unmarshal(value, &docs); err != nil {
+			return fmt.Errorf("error unmarshaling %s posting for term %s: %w", field, e.Term, err)
+		}
+		var refs []string
+		for ref := range docs {
+			refs = append(refs, ref)
+		}
+		sort.Strings(refs)
+		e.Postings[field] = refs
+	}
+	return nil
+}
+
+type document struct {
+	ref    string
+	fields map[string]string
+}
+
+// Builder accumulates documents and builds an Index from them. The resulting
+// index does not depend on the order in which documents are added.
+type Builder struct {
+	fields    []Field
+	documents []document
+}
+
+func NewBuilder(fields ...Field) *Builder {
+	return &Builder{
+		fields: fields,
+	}
+}
+
+// Add adds a document, identified by ref, to the builder. Values of fields
+// that were not registered with the builder are ignored.
+func (b *Builder) Add(ref string, fields map[string]string) {
+	b.documents = append(b.documents, document{
+		ref:    ref,
+		fields: fields,
+	})
+}
+
+func (b *Builder) Build() Index {
+	docs := append([]document{}, b.documents...)
+	sort.SliceStable(docs, func(i, j int) bool {
+		return docs[i].ref < docs[j].ref
+	})
+
+	// term frequencies & lengths for each field of each document
+	fieldTermFrequencies := map[string]map[string]int{}
+	fieldLengths := map[string]int{}
+	fieldLengthTotals := map[string]int{}
+	postings := map[string]map[string][]string{}
+	fieldRefs := []string{}
+
+	for _, doc := range docs {
+		for _, field := range b.fields {
+			fieldRef := field.Name + fieldRefJoiner + doc.ref
+			fieldRefs = append(fieldRefs, fieldRef)
+
+			terms := Terms(doc.fields[field.Name])
+			fieldLengths[fieldRef] = len(terms)
+			fieldLengthTotals[field.Name] += len(terms)
+
+			frequencies := map[string]int{}
+			for _, term := range terms {
+				if frequencies[term] == 0 {
+					if _, ok := postings[term]; !ok {
+						postings[term] = map[string][]string{}
+					}
+					postings[term][field.Name] = append(postings[term][field.Name], doc.ref)
+				}
+				frequencies[term]++
+			}
+			fieldTermFrequencies[fieldRef] = frequencies
+		}
+	}
+
+	// assign each term an index in lexicographical order, which is the order
+	// lunr requires terms to be in when loading the index
+	terms := make([]string, 0, len(postings))
+	for term := range postings {
+		terms = append(terms, term)
+	}
+	sort.Strings(terms)
+	termIndexes := map[string]int{}
+	invertedIndex := make([]InvertedIndexEntry, 0, len(terms))
+	for i, term := range terms {
+		termIndexes[term] = i
+		entry := InvertedIndexEntry{
+			Term:     term,
+			Index:    i,
+			Postings: map[string][]string{},
+		}
+		for _, field := range b.fields {
+			entry.Postings[field.Name] = postings[term][field.Name]
+		}
+		invertedIndex = append(invertedIndex, entry)
+	}
+
+	averageFieldLengths := map[string]float64{}
+	for _, field := range b.fields {
+		if len(docs) > 0 {
+			averageFieldLengths[field.Name] = float64(fieldLengthTotals[field.Name]) / float64(len(docs))
+		}
+	}
+
+	fieldVectors := make([]FieldVector, 0, len(fieldRefs))
+	for i, fieldRef := range fieldRefs {
+		field := b.fields[i%len(b.fields)]
+		boost := field.Boost
+		if boost == 0 {
+			boost = 1
+		}
+
+		frequencies := fieldTermFrequencies[fieldRef]
+		vectorTerms := make([]string, 0, len(frequencies))
+		for term := range frequencies {
+			vectorTerms = append(vectorTerms, term)
+		}
+		sort.Slice(vectorTerms, func(i, j int) bool {
+			return termIndexes[vectorTerms[i]] < termIndexes[vectorTerms[j]]
+		})
+
+		elements := make([]float64, 0, len(vectorTerms)*2)
+		for _, term := range vectorTerms {
+			tf := float64(frequencies[term])
+			fieldLength := float64(fieldLengths[fieldRef])
+			norm := 1 - bm25B
+			if avg := averageFieldLengths[field.Name]; avg > 0 {
+				norm += bm25B * (fieldLength / avg)
+			}
+			score := idf(postings[term], len(docs)) * ((bm25K1 + 1) * tf) / (bm25K1*norm + tf)
+			score *= boost
+			elements = append(elements, float64(termIndexes[term]), math.Round(score*1000)/1000)
+		}
+
+		fieldVectors = append(fieldVectors, FieldVector{
+			FieldRef: fieldRef,
+			Elements: elements,
+		})
+	}
+
+	fieldNames := make([]string, 0, len(b.fields))
+	for _, field := range b.fields {
+		fieldNames = append(fieldNames, field.Name)
+	}
+
+	return Index{
+		Version:       LunrVersion,
+		Fields:        fieldNames,
+		FieldVectors:  fieldVectors,
+		InvertedIndex: invertedIndex,
+		Pipeline:      []string{"stemmer"},
+	}
+}
+
+// Search scores each document against the query by summing the field vector
+// scores of every matching term. The query is processed by the same pipeline
+// as indexed documents. Documents that match none of the query terms are
+// omitted from the result.
+func (i Index) Search(query string) map[string]float64 {
+	termIndexes := map[int]bool{}
+	for _, term := range Terms(query) {
+		n := sort.Search(len(i.InvertedIndex), func(j int) bool {
+			return i.InvertedIndex[j].Term >= term
+		})
+		if n < len(i.InvertedIndex) && i.InvertedIndex[n].Term == term {
+			termIndexes[i.InvertedIndex[n].Index] = true
+		}
+	}
+
+	scores := map[string]float64{}
+	if len(termIndexes) == 0 {
+		return scores
+	}
+	for _, vector := range i.FieldVectors {
+		for j := 0; j+1 < len(vector.Elements); j += 2 {
+			if termIndexes[int(vector.Elements[j])] {
+				scores[vector.DocRef()] += vector.Elements[j+1]
+			}
+		}
+	}
+	for ref, score := range scores {
+		scores[ref] = math.Round(score*1000) / 1000
+	}
+	return scores
+}
+
+// idf calculates the inverse document frequency of a term in the same way as
+// lunr.idf.
+func idf(posting map[string][]string, documentCount int) float64 {
+	documentsWithTerm := 0
+	for _, refs := range posting {
+		documentsWithTerm += len(refs)
+	}
+	x := (float64(documentCount-documentsWithTerm) + 0.5) / (float64(documentsWithTerm) + 0.5)
+	return math.Log(1 + math.Abs(x))
+}
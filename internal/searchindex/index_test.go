@@ -0,0 +1,163 @@
+package searchindex
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestStem(t *testing.T) {
+	tests := map[string]string{
+		"caresses":       "caress",
+		"ponies":         "poni",
+		"cats":           "cat",
+		"feed":           "feed",
+		"agreed":         "agre",
+		"plastered":      "plaster",
+		"motoring":       "motor",
+		"sing":           "sing",
+		"conflated":      "conflat",
+		"hopping":        "hop",
+		"filing":         "file",
+		"happy":          "happi",
+		"relational":     "relat",
+		"generalization": "gener",
+		"monitoring":     "monitor",
+		"yelling":        "yell",
+		"webserver":      "webserv",
+		"by":             "by",
+	}
+	for word, want := range tests {
+		t.Run(word, func(t *testing.T) {
+			if got := Stem(word); got != want {
+				t.Errorf("Stem() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestTerms(t *testing.T) {
+	got := Terms("Monitoring the NGINX web-server, with @nixwiz!")
+	want := []string{"monitor", "nginx", "web", "server", "nixwiz"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Terms() = %v, want %v", got, want)
+	}
+}
+
+func TestBuilder_Build(t *testing.T) {
+	build := func(refs ...string) Index {
+		docs := map[string]map[string]string{
+			"nginx/nginx-monitoring": {
+				"title": "NGINX Monitoring",
+				"body":  "http webserver",
+			},
+			"system/host-monitoring": {
+				"title": "Host Monitoring",
+				"body":  "cpu memory",
+			},
+		}
+		builder := NewBuilder(Field{Name: "title", Boost: 10}, Field{Name: "body"})
+		for _, ref := range refs {
+			builder.Add(ref, docs[ref])
+		}
+		return builder.Build()
+	}
+
+	index := build("nginx/nginx-monitoring", "system/host-monitoring")
+	if index.Version != LunrVersion {
+		t.Errorf("version = %v, want %v", index.Version, LunrVersion)
+	}
+	if !reflect.DeepEqual(index.Fields, []string{"title", "body"}) {
+		t.Errorf("fields = %v", index.Fields)
+	}
+
+	terms := []string{}
+	for _, entry := range index.InvertedIndex {
+		terms = append(terms, entry.Term)
+	}
+	wantTerms := []string{"cpu", "host", "http", "memori", "monitor", "nginx", "webserv"}
+	if !reflect.DeepEqual(terms, wantTerms) {
+		t.Errorf("terms = %v, want %v", terms, wantTerms)
+	}
+
+	nginx := index.InvertedIndex[5]
+	wantPostings := map[string][]string{
+		"title": {"nginx/nginx-monitoring"},
+		"body":  nil,
+	}
+	if !reflect.DeepEqual(nginx.Postings, wantPostings) {
+		t.Errorf("nginx postings = %v, want %v", nginx.Postings, wantPostings)
+	}
+
+	if got := len(index.FieldVectors); got != 4 {
+		t.Fatalf("field vector count = %v, want 4", got)
+	}
+	title := index.FieldVectors[0]
+	if title.FieldRef != "title/nginx/nginx-monitoring" {
+		t.Errorf("field ref = %v", title.FieldRef)
+	}
+	// "monitor" appears in every document, so it must score lower than the
+	// boosted "nginx" term which only appears in a single document
+	if len(title.Elements) != 4 || title.Elements[1] >= title.Elements[3] {
+		t.Errorf("title vector = %v", title.Elements)
+	}
+
+	// output must not depend on insertion order
+	got, err := json.Marshal(build("system/host-monitoring", "nginx/nginx-monitoring"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := json.Marshal(index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("index differs by insertion order:\n%s\n%s", got, want)
+	}
+}
+
+func TestIndex_MarshalJSON(t *testing.T) {
+	builder := NewBuilder(Field{Name: "title"})
+	builder.Add("a", map[string]string{"title": "foo"})
+	index := builder.Build()
+
+	b, err := json.Marshal(index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"version":"2.3.9","fields":["title"],"fieldVectors":[["title/a",[0,0.288]]],"invertedIndex":[["foo",{"_index":0,"title":{"a":{}}}]],"pipeline":["stemmer"]}`
+	if string(b) != want {
+		t.Errorf("json = %s, want %s", b, want)
+	}
+}
+
+func TestIndex_Search(t *testing.T) {
+	builder := NewBuilder(Field{Name: "title", Boost: 10}, Field{Name: "body"})
+	builder.Add("nginx", map[string]string{"title": "NGINX Monitoring", "body": "webserver"})
+	builder.Add("host", map[string]string{"title": "Host Monitoring", "body": "nginx"})
+	builder.Add("disk", map[string]string{"title": "Disk Usage", "body": "filesystem"})
+
+	// the index must survive a round trip through its serialized form
+	b, err := json.Marshal(builder.Build())
+	if err != nil {
+		t.Fatal(err)
+	}
+	index := Index{}
+	if err := json.Unmarshal(b, &index); err != nil {
+		t.Fatal(err)
+	}
+
+	scores := index.Search("nginx")
+	if len(scores) != 2 {
+		t.Fatalf("Search() = %v, want 2 results", scores)
+	}
+	if scores["nginx"] <= scores["host"] {
+		t.Errorf("Search() = %v, want title match to score higher than body match", scores)
+	}
+	if got := index.Search("monitored"); len(got) != 2 {
+		t.Errorf("Search() = %v, want stemmed matches for nginx & host", got)
+	}
+	if got := index.Search("the"); len(got) != 0 {
+		t.Errorf("Search() = %v, want no results", got)
+	}
+}
@@ -0,0 +1,71 @@
+package searchindex
+
+import (
+	"regexp"
+	"strings"
+)
+
+// The text processing pipeline mirrors the default lunr.js pipeline
+// (trimmer, stopWordFilter & stemmer) so that query terms processed by lunr in
+// the browser match the terms stored in the generated index.
+
+var (
+	reSeparator  = regexp.MustCompile(`[\s\-]+`)
+	reTrimStart  = regexp.MustCompile(`^\W+`)
+	reTrimEnd    = regexp.MustCompile(`\W+$`)
+	stopWordsSet = func() map[string]bool {
+		set := map[string]bool{}
+		for _, word := range stopWords() {
+			set[word] = true
+		}
+		return set
+	}()
+)
+
+// Terms splits text into tokens and runs each token through the indexing
+// pipeline. Tokens that are empty after trimming or are stop words are
+// omitted.
+func Terms(text string) []string {
+	terms := []string{}
+	for _, token := range tokenize(text) {
+		term := trim(token)
+		if term == "" || stopWordsSet[term] {
+			continue
+		}
+		terms = append(terms, Stem(term))
+	}
+	return terms
+}
+
+func tokenize(text string) []string {
+	tokens := []string{}
+	for _, token := range reSeparator.Split(strings.ToLower(text), -1) {
+		if token != "" {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+func trim(token string) string {
+	token = reTrimStart.ReplaceAllString(token, "")
+	return reTrimEnd.ReplaceAllString(token, "")
+}
+
+func stopWords() []string {
+	return []string{
+		"a", "able", "about", "across", "after", "all", "almost", "also", "am",
+		"among", "an", "and", "any", "are", "as", "at", "be", "because", "been",
+		"but", "by", "can", "cannot", "could", "dear", "did", "do", "does",
+		"either", "else", "ever", "every", "for", "from", "get", "got", "had",
+		"has", "have", "he", "her", "hers", "him", "his", "how", "however", "i",
+		"if", "in", "into", "is", "it", "its", "just", "least", "let", "like",
+		"likely", "may", "me", "might", "most", "must", "my", "neither", "no",
+		"nor", "not", "of", "off", "often", "on", "only", "or", "other", "our",
+		"own", "rather", "said", "say", "says", "she", "should", "since", "so",
+		"some", "than", "that", "the", "their", "them", "then", "there", "these",
+		"they", "this", "tis", "to", "too", "twas", "us", "wants", "was", "we",
+		"were", "what", "when", "where", "which", "while", "who", "whom", "why",
+		"will", "with", "would", "yet", "you", "your",
+	}
+}
@@ -0,0 +1,171 @@
+package searchindex
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Stem is a port of the Porter stemmer used by lunr.js. It intentionally
+// follows the lunr implementation step for step, rather than a more recent
+// Porter2/Snowball variant, so that both sides produce identical stems.
+
+const (
+	stemConsonant    = `[^aeiou]`
+	stemVowel        = `[aeiouy]`
+	stemConsonantSeq = stemConsonant + `[^aeiouy]*`
+	stemVowelSeq     = stemVowel + `[aeiou]*`
+)
+
+var (
+	// [C]VC... is m>0
+	reStemMgr0 = regexp.MustCompile(`^(` + stemConsonantSeq + `)?` + stemVowelSeq + stemConsonantSeq)
+	// [C]VC[V] is m=1
+	reStemMeq1 = regexp.MustCompile(`^(` + stemConsonantSeq + `)?` + stemVowelSeq + stemConsonantSeq + `(` + stemVowelSeq + `)?$`)
+	// [C]VCVC... is m>1
+	reStemMgr1 = regexp.MustCompile(`^(` + stemConsonantSeq + `)?` + stemVowelSeq + stemConsonantSeq + stemVowelSeq + stemConsonantSeq)
+	// vowel in stem
+	reStemSV = regexp.MustCompile(`^(` + stemConsonantSeq + `)?` + stemVowel)
+
+	reStep1a  = regexp.MustCompile(`^(.+?)(ss|i)es$`)
+	reStep1a2 = regexp.MustCompile(`^(.+?)([^s])s$`)
+	reStep1b  = regexp.MustCompile(`^(.+?)eed$`)
+	reStep1b2 = regexp.MustCompile(`^(.+?)(ed|ing)$`)
+	reStep1b3 = regexp.MustCompile(`(at|bl|iz)$`)
+	reStep1b4 = regexp.MustCompile(`^` + stemConsonantSeq + stemVowel + `[^aeiouwxy]$`)
+	reStep1c  = regexp.MustCompile(`^(.+?[^aeiou])y$`)
+	reStep2   = regexp.MustCompile(`^(.+?)(ational|tional|enci|anci|izer|bli|alli|entli|eli|ousli|ization|ation|ator|alism|iveness|fulness|ousness|aliti|iviti|biliti|logi)$`)
+	reStep3   = regexp.MustCompile(`^(.+?)(icate|ative|alize|iciti|ical|ful|ness)$`)
+	reStep4   = regexp.MustCompile(`^(.+?)(al|ance|ence|er|ic|able|ible|ant|ement|ment|ent|ou|ism|ate|iti|ous|ive|ize)$`)
+	reStep4b  = regexp.MustCompile(`^(.+?)(s|t)(ion)$`)
+	reStep5   = regexp.MustCompile(`^(.+?)e$`)
+	reStep5b  = regexp.MustCompile(`ll$`)
+
+	step2Suffixes = map[string]string{
+		"ational": "ate",
+		"tional":  "tion",
+		"enci":    "ence",
+		"anci":    "ance",
+		"izer":    "ize",
+		"bli":     "ble",
+		"alli":    "al",
+		"entli":   "ent",
+		"eli":     "e",
+		"ousli":   "ous",
+		"ization": "ize",
+		"ation":   "ate",
+		"ator":    "ate",
+		"alism":   "al",
+		"iveness": "ive",
+		"fulness": "ful",
+		"ousness": "ous",
+		"aliti":   "al",
+		"iviti":   "ive",
+		"biliti":  "ble",
+		"logi":    "log",
+	}
+	step3Suffixes = map[string]string{
+		"icate": "ic",
+		"ative": "",
+		"alize": "al",
+		"iciti": "ic",
+		"ical":  "ic",
+		"ful":   "",
+		"ness":  "",
+	}
+)
+
+func Stem(w string) string {
+	if len(w) < 3 {
+		return w
+	}
+
+	// an initial y is treated as a consonant
+	initialY := w[0] == 'y'
+	if initialY {
+		w = "Y" + w[1:]
+	}
+
+	// step 1a
+	if m := reStep1a.FindStringSubmatch(w); m != nil {
+		w = m[1] + m[2]
+	} else if m := reStep1a2.FindStringSubmatch(w); m != nil {
+		w = m[1] + m[2]
+	}
+
+	// step 1b
+	if m := reStep1b.FindStringSubmatch(w); m != nil {
+		if reStemMgr0.MatchString(m[1]) {
+			w = w[:len(w)-1]
+		}
+	} else if m := reStep1b2.FindStringSubmatch(w); m != nil {
+		stem := m[1]
+		if reStemSV.MatchString(stem) {
+			w = stem
+			if reStep1b3.MatchString(w) {
+				w = w + "e"
+			} else if endsWithDoubleConsonant(w) {
+				w = w[:len(w)-1]
+			} else if reStep1b4.MatchString(w) {
+				w = w + "e"
+			}
+		}
+	}
+
+	// step 1c
+	if m := reStep1c.FindStringSubmatch(w); m != nil {
+		w = m[1] + "i"
+	}
+
+	// step 2
+	if m := reStep2.FindStringSubmatch(w); m != nil {
+		if reStemMgr0.MatchString(m[1]) {
+			w = m[1] + step2Suffixes[m[2]]
+		}
+	}
+
+	// step 3
+	if m := reStep3.FindStringSubmatch(w); m != nil {
+		if reStemMgr0.MatchString(m[1]) {
+			w = m[1] + step3Suffixes[m[2]]
+		}
+	}
+
+	// step 4
+	if m := reStep4.FindStringSubmatch(w); m != nil {
+		if reStemMgr1.MatchString(m[1]) {
+			w = m[1]
+		}
+	} else if m := reStep4b.FindStringSubmatch(w); m != nil {
+		stem := m[1] + m[2]
+		if reStemMgr1.MatchString(stem) {
+			w = stem
+		}
+	}
+
+	// step 5
+	if m := reStep5.FindStringSubmatch(w); m != nil {
+		stem := m[1]
+		if reStemMgr1.MatchString(stem) || (reStemMeq1.MatchString(stem) && !reStep1b4.MatchString(stem)) {
+			w = stem
+		}
+	}
+	if reStep5b.MatchString(w) && reStemMgr1.MatchString(w) {
+		w = w[:len(w)-1]
+	}
+
+	if initialY {
+		w = "y" + w[1:]
+	}
+
+	return w
+}
+
+// endsWithDoubleConsonant is the equivalent of the /([^aeiouylsz])\1$/
+// expression used by lunr, as RE2 does not support backreferences.
+func endsWithDoubleConsonant(w string) bool {
+	if len(w) < 2 {
+		return false
+	}
+	last := w[len(w)-1]
+	return last == w[len(w)-2] && !strings.ContainsRune("aeiouylsz", rune(last))
+}
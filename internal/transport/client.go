@@ -36,6 +36,7 @@ func (c *Client) WritePump() {
 	defer func() {
 		ticker.Stop()
 		c.conn.Close()
+		c.transport.Unregister(c)
 	}()
 
 	for {
@@ -2,45 +2,93 @@ package transport
 
 import (
 	"context"
+	"sync/atomic"
 )
 
 type Transport struct {
+	// connected is the number of registered clients; it is accessed
+	// atomically & must therefore be the first field
+	connected int64
+
 	clients    map[*Client]bool
 	broadcast  chan []byte
 	register   chan *Client
 	unregister chan *Client
+
+	// done is closed once the transport has stopped
+	done chan struct{}
 }
 
 func NewTransport() Transport {
 	return Transport{
+		broadcast:  make(chan []byte),
 		register:   make(chan *Client),
 		unregister: make(chan *Client),
 		clients:    make(map[*Client]bool),
+		done:       make(chan struct{}),
 	}
 }
 
+// Broadcast sends msg to every connected client.
 func (t *Transport) Broadcast(msg []byte) {
-	for client, connected := range t.clients {
-		if connected {
-			client.send <- msg
-		}
+	select {
+	case t.broadcast <- msg:
+	case <-t.done:
 	}
 }
 
 func (t *Transport) Register(client *Client) {
-	t.register <- client
+	select {
+	case t.register <- client:
+	case <-t.done:
+		close(client.send)
+	}
+}
+
+func (t *Transport) Unregister(client *Client) {
+	select {
+	case t.unregister <- client:
+	case <-t.done:
+	}
 }
 
+// Clients returns the number of connected clients.
+func (t *Transport) Clients() int {
+	return int(atomic.LoadInt64(&t.connected))
+}
+
+// Start registers & unregisters clients and broadcasts messages until ctx is
+// done, at which point every client is disconnected.
 func (t *Transport) Start(ctx context.Context) {
+	defer close(t.done)
 	for {
 		select {
+		case <-ctx.Done():
+			for client := range t.clients {
+				t.remove(client)
+			}
+			return
 		case client := <-t.register:
 			t.clients[client] = true
 		case client := <-t.unregister:
-			if _, ok := t.clients[client]; ok {
-				delete(t.clients, client)
-				close(client.send)
+			t.remove(client)
+		case msg := <-t.broadcast:
+			for client := range t.clients {
+				select {
+				case client.send <- msg:
+				default:
+					// the client is not keeping up
+					t.remove(client)
+				}
 			}
 		}
+		atomic.StoreInt64(&t.connected, int64(len(t.clients)))
+	}
+}
+
+func (t *Transport) remove(client *Client) {
+	if _, ok := t.clients[client]; ok {
+		delete(t.clients, client)
+		close(client.send)
 	}
 }
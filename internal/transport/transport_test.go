@@ -0,0 +1,45 @@
+package transport
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTransport_Start(t *testing.T) {
+	tr := NewTransport()
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		tr.Start(ctx)
+		close(stopped)
+	}()
+
+	client := NewClient(&tr, nil)
+	tr.Register(&client)
+	tr.Broadcast([]byte("refresh"))
+	if msg := <-client.send; string(msg) != "refresh" {
+		t.Errorf("client received %q, want %q", msg, "refresh")
+	}
+
+	cancel()
+	<-stopped
+	if _, ok := <-client.send; ok {
+		t.Error("client must be disconnected once the transport has stopped")
+	}
+
+	// the transport must not block once it has stopped
+	done := make(chan struct{})
+	go func() {
+		late := NewClient(&tr, nil)
+		tr.Register(&late)
+		tr.Unregister(&late)
+		tr.Broadcast([]byte("refresh"))
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("transport blocked after it was stopped")
+	}
+}
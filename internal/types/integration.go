@@ -3,6 +3,7 @@ package types
 import (
 	"fmt"
 	"path"
+	"sort"
 
 	semver "github.com/Masterminds/semver/v3"
 )
@@ -82,6 +83,22 @@ func (i Integrations) LatestVersion() IntegrationVersion {
 	return latestVersion
 }
 
+// Sorted returns a copy of the integration versions sorted by namespace,
+// name & version, lowest version first.
+func (i Integrations) Sorted() Integrations {
+	sorted := append(Integrations{}, i...)
+	sort.SliceStable(sorted, func(a, b int) bool {
+		if sorted[a].Namespace != sorted[b].Namespace {
+			return sorted[a].Namespace < sorted[b].Namespace
+		}
+		if sorted[a].Name != sorted[b].Name {
+			return sorted[a].Name < sorted[b].Name
+		}
+		return semver.MustParse(sorted[a].SemVer()).LessThan(semver.MustParse(sorted[b].SemVer()))
+	})
+	return sorted
+}
+
 func (i Integrations) ByNamespace() NamespacedIntegrations {
 	integrations := NamespacedIntegrations{}
 	for _, integrationVersion := range i {
@@ -121,5 +138,25 @@ func (i Integrations) Versions() []string {
 // NamespacedIntegrations is a mapping of namespaces to Integrations
 type NamespacedIntegrations map[string]Integrations
 
+// Namespaces returns the sorted namespaces.
+func (n NamespacedIntegrations) Namespaces() []string {
+	namespaces := make([]string, 0, len(n))
+	for namespace := range n {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
 // IntegrationVersions is a mapping of integration names to Integrations
 type IntegrationVersions map[string]Integrations
+
+// Names returns the sorted integration names.
+func (i IntegrationVersions) Names() []string {
+	names := make([]string, 0, len(i))
+	for name := range i {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
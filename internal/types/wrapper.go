@@ -5,6 +5,7 @@ import (
 
 	catalogv1 "github.com/sensu/catalog-api/internal/api/catalog/v1"
 	metav1 "github.com/sensu/catalog-api/internal/api/metadata/v1"
+	"github.com/sensu/catalog-api/internal/position"
 	"gopkg.in/yaml.v3"
 )
 
@@ -13,12 +14,27 @@ type RawWrapper struct {
 	APIVersion string          `json:"api_version" yaml:"api_version"`
 	Metadata   metav1.Metadata `json:"metadata" yaml:"metadata"`
 	Value      yaml.Node       `json:"spec" yaml:"spec"`
+
+	// Positions holds the positions of the fields of the wrapper, e.g.
+	// "metadata.name" or "spec.display_name".
+	Positions position.Map `json:"-" yaml:"-"`
+}
+
+func (r *RawWrapper) UnmarshalYAML(node *yaml.Node) error {
+	type rawWrapper RawWrapper
+	raw := rawWrapper{}
+	if err := node.Decode(&raw); err != nil {
+		return err
+	}
+	*r = RawWrapper(raw)
+	r.Positions = position.MapOf(node)
+	return nil
 }
 
 func RawWrapperFromYAMLBytes(bytes []byte) (RawWrapper, error) {
 	raw := RawWrapper{}
 	if err := yaml.Unmarshal([]byte(bytes), &raw); err != nil {
-		return raw, fmt.Errorf("error unmarshaling raw wrapper: %w", err)
+		return raw, fmt.Errorf("error unmarshaling raw wrapper: %w", position.FromYAML(err))
 	}
 	return raw, nil
 }
@@ -46,11 +62,49 @@ func WrapperFromRawWrapper(raw RawWrapper) (Wrapper, error) {
 			Metadata: raw.Metadata,
 		}
 		if err := raw.Value.Decode(&integration); err != nil {
-			return wrap, fmt.Errorf("failed to decode raw value %s: %w", wrap.TypeVersion(), err)
+			return wrap, fmt.Errorf("failed to decode raw value %s: %w", wrap.TypeVersion(), position.FromYAML(err))
 		}
+
+		integration.Positions = raw.specPositions()
 		wrap.Value = integration
 		return wrap, nil
+	case "catalog/v1.Namespace":
+		namespace := catalogv1.Namespace{
+			Metadata: raw.Metadata,
+		}
+		if err := raw.Value.Decode(&namespace); err != nil {
+			return wrap, fmt.Errorf("failed to decode raw value %s: %w", wrap.TypeVersion(), position.FromYAML(err))
+		}
+		namespace.Positions = raw.specPositions()
+		wrap.Value = namespace
+		return wrap, nil
 	default:
-		return wrap, fmt.Errorf("invalid resource type version: %s", wrap.TypeVersion())
+		// the api version is reported unless it is valid, in which case the
+		// type must be invalid
+		path := "api_version"
+		if raw.APIVersion == "catalog/v1" {
+			path = "type"
+		}
+		err := fmt.Errorf("invalid resource type version: %s", wrap.TypeVersion())
+		return wrap, position.At(raw.Positions.Lookup(path), err)
+	}
+}
+
+// specPositions returns the positions of the fields of the spec. The metadata
+// of the value is defined by the wrapper, so its positions are kept alongside
+// those of the spec.
+func (r RawWrapper) specPositions() position.Map {
+	positions := r.Positions.Sub("spec")
+	if _, ok := positions[""]; !ok {
+		// errors of a missing spec refer to the document
+		positions[""] = r.Positions.Lookup("")
+	}
+	for path, pos := range r.Positions.Sub("metadata") {
+		if path == "" {
+			positions["metadata"] = pos
+		} else {
+			positions["metadata."+path] = pos
+		}
 	}
+	return positions
 }
@@ -0,0 +1,106 @@
+package types
+
+import (
+	"testing"
+
+	catalogv1 "github.com/sensu/catalog-api/internal/api/catalog/v1"
+	"github.com/sensu/catalog-api/internal/position"
+)
+
+const fixtureConfig = `---
+type: Integration
+api_version: catalog/v1
+metadata:
+  namespace: nginx
+  name: nginx-monitoring
+spec:
+  display_name: NGINX Monitoring
+  class: community
+  provider: unknown
+  short_description: NGINX monitoring
+  contributors:
+    - "@sensu"
+`
+
+func TestWrapperFromRawWrapper_positions(t *testing.T) {
+	raw, err := RawWrapperFromYAMLBytes([]byte(fixtureConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrap, err := WrapperFromRawWrapper(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	integration := wrap.Value.(catalogv1.Integration)
+
+	tests := map[string]position.Position{
+		"metadata.name": {Line: 6, Column: 3},
+		"provider":      {Line: 10, Column: 3},
+		// missing fields resolve to the spec
+		"tags": {Line: 7, Column: 1},
+	}
+	for path, want := range tests {
+		if got := integration.Positions.Lookup(path); got != want {
+			t.Errorf("Lookup(%q) = %v, want %v", path, got, want)
+		}
+	}
+
+	err = position.InFile("sensu-integration.yaml", integration.Validate())
+	want := "sensu-integration.yaml:10:3: provider must be one of [alerts deregistration discovery events incidents metrics monitoring remediation], got: unknown"
+	if err == nil || err.Error() != want {
+		t.Errorf("Validate() error = %v, want %s", err, want)
+	}
+}
+
+func TestRawWrapperFromYAMLBytes_position(t *testing.T) {
+	_, err := RawWrapperFromYAMLBytes([]byte("type: Integration\nmetadata: [\n"))
+	posErr, ok := position.Find(err)
+	if !ok || posErr.Line != 2 {
+		t.Errorf("RawWrapperFromYAMLBytes() error = %v, want a position error on line 2", err)
+	}
+}
+
+func TestWrapperFromRawWrapper_invalidTypeVersion(t *testing.T) {
+	tests := map[string]struct {
+		config string
+		want   string
+	}{
+		"invalid type": {
+			config: "api_version: catalog/v1\ntype: Foo\n",
+			want:   "sensu-integration.yaml:2:1: invalid resource type version: catalog/v1.Foo",
+		},
+		"invalid api version": {
+			config: "type: Integration\napi_version: catalog/v2\n",
+			want:   "sensu-integration.yaml:2:1: invalid resource type version: catalog/v2.Integration",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			raw, err := RawWrapperFromYAMLBytes([]byte(tt.config))
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = WrapperFromRawWrapper(raw)
+			err = position.InFile("sensu-integration.yaml", err)
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("WrapperFromRawWrapper() error = %v, want %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrapperFromRawWrapper_missingSpec(t *testing.T) {
+	raw, err := RawWrapperFromYAMLBytes([]byte("---\ntype: Integration\napi_version: catalog/v1\nmetadata:\n  name: foo\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrap, err := WrapperFromRawWrapper(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	integration := wrap.Value.(catalogv1.Integration)
+	want := position.Position{Line: 2, Column: 1}
+	if got := integration.Positions.Lookup("provider"); got != want {
+		t.Errorf("Lookup(%q) = %v, want %v", "provider", got, want)
+	}
+}
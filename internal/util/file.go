@@ -0,0 +1,188 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"syscall"
+)
+
+// WriteFileAtomic writes data to a temporary file within the directory of
+// path, syncs it and renames it into place, so that readers observe either
+// the previous or the new contents of path but never a partially written
+// file, even after a crash.
+func WriteFileAtomic(path string, data []byte, perm fs.FileMode) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	tmpPath := f.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Chmod(perm); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return err
+	}
+	return SyncDir(filepath.Dir(path))
+}
+
+// CopyDirAtomic recursively copies src to dst by copying it to a temporary
+// directory alongside dst, syncing every copied file & directory and then
+// renaming it into place. dst must not exist yet.
+func CopyDirAtomic(src string, dst string) error {
+	tmpDir, err := os.MkdirTemp(filepath.Dir(dst), "."+filepath.Base(dst)+".tmp*")
+	if err != nil {
+		return err
+	}
+	if err := copyDir(src, tmpDir); err != nil {
+		_ = os.RemoveAll(tmpDir)
+		return err
+	}
+	if err := os.Rename(tmpDir, dst); err != nil {
+		_ = os.RemoveAll(tmpDir)
+		return err
+	}
+	return SyncDir(filepath.Dir(dst))
+}
+
+// MoveDir moves src to dst, which must not exist yet. The contents of src are
+// synced and src is renamed if both are on the same filesystem; otherwise src
+// is copied atomically & removed.
+func MoveDir(src string, dst string) error {
+	if err := syncTree(src); err != nil {
+		return err
+	}
+	err := os.Rename(src, dst)
+	if errors.Is(err, syscall.EXDEV) {
+		if err := CopyDirAtomic(src, dst); err != nil {
+			return err
+		}
+		return os.RemoveAll(src)
+	} else if err != nil {
+		return err
+	}
+	return SyncDir(filepath.Dir(dst))
+}
+
+// SyncDir syncs a directory, which persists the creation, removal & renaming
+// of the entries within it. Directories cannot be synced on Windows, where
+// SyncDir is a no-op.
+func SyncDir(path string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	d, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	if err := d.Sync(); err != nil {
+		d.Close()
+		return fmt.Errorf("error syncing %s: %w", path, err)
+	}
+	return d.Close()
+}
+
+// copyDir recursively copies the contents of src to the existing directory
+// dst, syncing every file & directory it creates.
+func copyDir(src string, dst string) error {
+	dirs := []string{}
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		if d.IsDir() {
+			dirs = append(dirs, target)
+			if rel == "." {
+				return nil
+			}
+			return os.Mkdir(target, 0700)
+		}
+		return copyFile(path, target)
+	})
+	if err != nil {
+		return err
+	}
+	return syncDirs(dirs)
+}
+
+func copyFile(src string, dst string) error {
+	b, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// syncTree syncs every file & directory within path.
+func syncTree(path string) error {
+	dirs := []string{}
+	err := filepath.WalkDir(path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			dirs = append(dirs, path)
+			return nil
+		}
+		// files must be opened for writing to be synced on windows
+		f, err := os.OpenFile(path, os.O_RDWR, 0)
+		if err != nil {
+			return err
+		}
+		if err := f.Sync(); err != nil {
+			f.Close()
+			return fmt.Errorf("error syncing %s: %w", path, err)
+		}
+		return f.Close()
+	})
+	if err != nil {
+		return err
+	}
+	return syncDirs(dirs)
+}
+
+// syncDirs syncs dirs, deepest first, so that each directory is persisted
+// before the entry referring to it within its parent.
+func syncDirs(dirs []string) error {
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if err := SyncDir(dirs[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package util
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTree(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, contents := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func readTree(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	files := map[string]string{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		b, err := os.ReadFile(path)
+		files[filepath.ToSlash(rel)] = string(b)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func assertTree(t *testing.T, dir string, want map[string]string) {
+	t.Helper()
+	got := readTree(t, dir)
+	if len(got) != len(want) {
+		t.Fatalf("files = %v, want %v", got, want)
+	}
+	for name, contents := range want {
+		if got[name] != contents {
+			t.Errorf("%s = %q, want %q", name, got[name], contents)
+		}
+	}
+}
+
+func TestCopyDirAtomic(t *testing.T) {
+	files := map[string]string{
+		"manifest.json":         `{"files":[]}`,
+		"v1/catalog.json":       `{}`,
+		"v1/ns/name/1.0.0.json": `{"version":"1.0.0"}`,
+	}
+	src := filepath.Join(t.TempDir(), "staging")
+	writeTree(t, src, files)
+
+	parent := t.TempDir()
+	dst := filepath.Join(parent, "abc")
+	if err := CopyDirAtomic(src, dst); err != nil {
+		t.Fatal(err)
+	}
+	assertTree(t, dst, files)
+	assertTree(t, src, files)
+
+	// copying onto an existing release must fail without modifying it
+	if err := CopyDirAtomic(src, dst); err == nil {
+		t.Errorf("CopyDirAtomic() onto an existing directory should fail")
+	}
+	entries, err := os.ReadDir(parent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("CopyDirAtomic() left temporary directories behind: %v", entries)
+	}
+}
+
+func TestCopyDirAtomic_Failure(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "staging")
+	writeTree(t, src, map[string]string{"v1/catalog.json": `{}`})
+	if err := os.Symlink("missing", filepath.Join(src, "v1", "dangling.json")); err != nil {
+		t.Skip("symlinks not supported:", err)
+	}
+
+	// a failed copy must neither create dst nor leave a partial copy behind
+	parent := t.TempDir()
+	dst := filepath.Join(parent, "abc")
+	if err := CopyDirAtomic(src, dst); err == nil {
+		t.Fatal("CopyDirAtomic() should fail")
+	}
+	entries, err := os.ReadDir(parent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("CopyDirAtomic() left files behind: %v", entries)
+	}
+}
+
+func TestMoveDir(t *testing.T) {
+	files := map[string]string{
+		"manifest.json":   `{"files":[]}`,
+		"v1/catalog.json": `{}`,
+	}
+	src := filepath.Join(t.TempDir(), "release")
+	writeTree(t, src, files)
+
+	dst := filepath.Join(t.TempDir(), "abc")
+	if err := MoveDir(src, dst); err != nil {
+		t.Fatal(err)
+	}
+	assertTree(t, dst, files)
+	if _, err := os.Stat(src); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("MoveDir() did not remove %s", src)
+	}
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "version.json")
+	for _, contents := range []string{`{"release_sha256":"abc"}`, `{"release_sha256":"def"}`} {
+		if err := WriteFileAtomic(path, []byte(contents), 0600); err != nil {
+			t.Fatal(err)
+		}
+		assertTree(t, dir, map[string]string{"version.json": contents})
+	}
+}
@@ -1,17 +1,54 @@
 package util
 
 import (
+	"crypto/sha256"
 	"encoding/base64"
 	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
 	"regexp"
+	"sort"
+	"strings"
 
 	"golang.org/x/mod/sumdb/dirhash"
 )
 
-func CalculateDirChecksum(path string, prefix string) (string, error) {
+// CalculateDirChecksum calculates the dirhash of the files within path.
+// Files for which exclude, if not nil, returns true are omitted; exclude is
+// passed the slash separated path of each file relative to path.
+func CalculateDirChecksum(path string, prefix string, exclude func(name string) bool) (string, error) {
+	return CalculateFSChecksum(os.DirFS(path), ".", prefix, exclude)
+}
+
+// CalculateFSChecksum calculates the dirhash of the files within dir of fsys,
+// as CalculateDirChecksum does for a directory on the local filesystem.
+func CalculateFSChecksum(fsys fs.FS, dir string, prefix string, exclude func(name string) bool) (string, error) {
+	included := []string{}
+	err := fs.WalkDir(fsys, dir, func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel := name
+		if dir != "." {
+			rel = strings.TrimPrefix(name, dir+"/")
+		}
+		if exclude == nil || !exclude(rel) {
+			included = append(included, path.Join(prefix, rel))
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("error calculating checksum of dir: %w", err)
+	}
+	open := func(name string) (io.ReadCloser, error) {
+		return fsys.Open(path.Join(dir, strings.TrimPrefix(strings.TrimPrefix(name, prefix), "/")))
+	}
+
 	// calculate sha256 checksum, which is returned as a base64 encoded string
 	// prefixed with "h1:"
-	h1, err := dirhash.HashDir(path, prefix, dirhash.Hash1)
+	h1, err := dirhash.Hash1(included, open)
 	if err != nil {
 		return "", fmt.Errorf("error calculating checksum of dir: %w", err)
 	}
@@ -28,3 +65,55 @@ func CalculateDirChecksum(path string, prefix string) (string, error) {
 
 	return fmt.Sprintf("%x", bytes), nil
 }
+
+// FileDigest is the sha256 checksum & size of a file. Path is slash separated
+// and relative to the directory the digest was calculated from.
+type FileDigest struct {
+	Path   string
+	SHA256 string
+	Size   int64
+}
+
+// CalculateFileDigests calculates the digest of every file within dir, sorted
+// by path. Files whose relative path is one of exclude are omitted.
+func CalculateFileDigests(dir string, exclude ...string) ([]FileDigest, error) {
+	return CalculateFSFileDigests(os.DirFS(dir), exclude...)
+}
+
+// CalculateFSFileDigests calculates the digest of every file of fsys, as
+// CalculateFileDigests does for a directory on the local filesystem.
+func CalculateFSFileDigests(fsys fs.FS, exclude ...string) ([]FileDigest, error) {
+	excluded := map[string]bool{}
+	for _, name := range exclude {
+		excluded[name] = true
+	}
+
+	digests := []FileDigest{}
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || excluded[name] {
+			return nil
+		}
+
+		b, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		digests = append(digests, FileDigest{
+			Path:   name,
+			SHA256: fmt.Sprintf("%x", sha256.Sum256(b)),
+			Size:   int64(len(b)),
+		})
+		return nil
+	})
+	if err != nil {
+		return digests, fmt.Errorf("error calculating file digests of dir: %w", err)
+	}
+
+	sort.Slice(digests, func(i, j int) bool {
+		return digests[i].Path < digests[j].Path
+	})
+	return digests, nil
+}
@@ -2,16 +2,22 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/sensu/catalog-api/internal/commands"
+	cmderrors "github.com/sensu/catalog-api/internal/commands/errors"
 )
 
 func fatalErr(err error) {
 	fmt.Fprintf(os.Stderr, "error: %s\n", err)
+	var exitErr cmderrors.ErrExitStatus
+	if errors.As(err, &exitErr) {
+		os.Exit(exitErr.Status)
+	}
 	os.Exit(1)
 }
 